@@ -10,5 +10,9 @@ const AttributeQueryKind = "AttributeQuery"
 type AttributeQuery struct {
 	TypeMeta `json:",inline"`
 	// Attributes list the configuration for Attribute types.
-	Attributes json.RawMessage `json:"attributes"`
+	Attributes json.RawMessage `json:"attributes,omitempty"`
+	// Query is a boolean expression evaluated against the attributes
+	// (e.g. size > 1024 && (type == "model" || type == "weights") && !deprecated).
+	// If Attributes is also set, both must match.
+	Query string `json:"query,omitempty"`
 }
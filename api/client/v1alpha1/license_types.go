@@ -0,0 +1,19 @@
+package v1alpha1
+
+// LicensePolicyKind object kind of LicensePolicy.
+const LicensePolicyKind = "LicensePolicy"
+
+// LicensePolicy configures the licenses allowed for the
+// components of collections. Licenses are matched against
+// the SPDX license identifiers in the license expressions of
+// components case-insensitively and may use "*" as a wildcard
+// (e.g. LicenseRef-*).
+type LicensePolicy struct {
+	TypeMeta `json:",inline"`
+	// Allow lists the allowed licenses. If set, all other
+	// licenses are disallowed.
+	Allow []string `json:"allow,omitempty"`
+	// Deny lists the disallowed licenses. Deny takes
+	// precedence over Allow.
+	Deny []string `json:"deny,omitempty"`
+}
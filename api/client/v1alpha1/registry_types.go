@@ -0,0 +1,64 @@
+package v1alpha1
+
+// RegistryConfigurationKind object kind of RegistryConfiguration.
+const RegistryConfigurationKind = "RegistryConfiguration"
+
+// PullFromMirror policies.
+const (
+	// PullFromMirrorAll uses the mirror for pulls by tag and by digest.
+	PullFromMirrorAll = "all"
+	// PullFromMirrorDigestOnly uses the mirror for pulls by digest only.
+	PullFromMirrorDigestOnly = "digest-only"
+	// PullFromMirrorTagOnly uses the mirror for pulls by tag only.
+	PullFromMirrorTagOnly = "tag-only"
+)
+
+// RegistryConfiguration configures the mirrors used to
+// pull content from registries.
+type RegistryConfiguration struct {
+	TypeMeta `json:",inline"`
+	// Registries configures the mirrors of each registry.
+	Registries []Registry `json:"registries,omitempty"`
+}
+
+// Registry configures the mirrors of a single registry or
+// repository namespace.
+type Registry struct {
+	// Location is the registry host or a repository namespace
+	// prefix (e.g. registry.example.com/team) the mirrors apply to.
+	// The most specific location matching a reference is used.
+	Location string `json:"location"`
+	// Mirrors are tried in order before the location when pulling.
+	// Content is always pushed to the location.
+	Mirrors []Mirror `json:"mirrors,omitempty"`
+	// TLS configures the connections to the registry host
+	// of the location.
+	TLS RegistryTLS `json:"tls,omitempty"`
+}
+
+// RegistryTLS configures the TLS connections to a registry. Settings that are
+// not set default to the settings of the client.
+type RegistryTLS struct {
+	// CAFile is the path to a PEM encoded bundle of certificate
+	// authorities trusted in addition to the system certificate authorities.
+	CAFile string `json:"caFile,omitempty"`
+	// CertFile is the path to a PEM encoded client certificate
+	// for mutual TLS.
+	CertFile string `json:"certFile,omitempty"`
+	// KeyFile is the path to the PEM encoded private key
+	// of the client certificate.
+	KeyFile string `json:"keyFile,omitempty"`
+}
+
+// Mirror configures a single registry mirror.
+type Mirror struct {
+	// Location is the registry host or repository namespace prefix
+	// that replaces the registry location in the reference.
+	Location string `json:"location"`
+	// PullFromMirror sets the references the mirror is used for
+	// (all, digest-only, or tag-only). Defaults to all.
+	PullFromMirror string `json:"pullFromMirror,omitempty"`
+	// PlainHTTP uses plain http and not https
+	// when contacting the mirror.
+	PlainHTTP bool `json:"plainHTTP,omitempty"`
+}
@@ -22,6 +22,7 @@ type SchemaConfigurationSpec struct {
 	// SchemaPath defines that path to a JSON schema. If set, the AttributeTypes fields
 	// will be ignored.
 	SchemaPath string
-	// AttributeTypes is a collection of attribute type definitions.
-	AttributeTypes schema.Types `json:"attributeTypes,omitempty"`
+	// AttributeTypes is a collection of attribute type definitions. Each
+	// definition is either a type or a property with the type and constraints.
+	AttributeTypes schema.Properties `json:"attributeTypes,omitempty"`
 }
@@ -0,0 +1,73 @@
+package v1alpha1
+
+// VerificationPolicyKind object kind of VerificationPolicy.
+const VerificationPolicyKind = "VerificationPolicy"
+
+// Verification modes.
+const (
+	// VerificationModeEnforce fails pulls of collections that do not
+	// satisfy the policy.
+	VerificationModeEnforce = "enforce"
+	// VerificationModeWarn logs a warning for collections that do not
+	// satisfy the policy and pulls them.
+	VerificationModeWarn = "warn"
+	// VerificationModeSkip pulls collections without verifying
+	// their signatures.
+	VerificationModeSkip = "skip"
+)
+
+// VerificationPolicy configures the signers trusted
+// for collections by reference.
+type VerificationPolicy struct {
+	TypeMeta `json:",inline"`
+	// Policies are evaluated in order. The first policy with a pattern
+	// matching the reference of a collection is used to verify it.
+	Policies []ReferencePolicy `json:"policies,omitempty"`
+}
+
+// ReferencePolicy configures the signers trusted for the
+// collections matching a reference pattern.
+type ReferencePolicy struct {
+	// Pattern is matched against the repository of a reference (the registry
+	// host and path without the tag or digest). A "*" matches any characters
+	// within a path segment and a final "**" segment matches one or more
+	// path segments (e.g. registry.example.com/team/**).
+	Pattern string `json:"pattern"`
+	// Mode sets how the policy is applied (enforce, warn, or skip).
+	// Defaults to enforce.
+	Mode string `json:"mode,omitempty"`
+	// Threshold is the number of authorities that must have signed
+	// the collection. Defaults to one.
+	Threshold int `json:"threshold,omitempty"`
+	// Authorities are the trusted signers.
+	Authorities []Authority `json:"authorities,omitempty"`
+}
+
+// Authority is a trusted signer identified by a public key
+// or a keyless signing identity.
+type Authority struct {
+	// Name identifies the authority in messages.
+	Name string `json:"name,omitempty"`
+	// PublicKey is the path to the public key of
+	// signatures created with a private key.
+	PublicKey string `json:"publicKey,omitempty"`
+	// Keyless identifies the signer of keyless signatures
+	// by the signing certificate.
+	Keyless *KeylessAuthority `json:"keyless,omitempty"`
+}
+
+// KeylessAuthority identifies the signer of keyless signatures. At least
+// one field must be set. Fields that are not set match any value.
+type KeylessAuthority struct {
+	// Subject is the expected certificate subject
+	// (e.g. an email address or workflow URI).
+	Subject string `json:"subject,omitempty"`
+	// SubjectRegExp is a regular expression the
+	// certificate subject must match.
+	SubjectRegExp string `json:"subjectRegExp,omitempty"`
+	// Issuer is the expected OIDC issuer of the certificate.
+	Issuer string `json:"issuer,omitempty"`
+	// IssuerRegExp is a regular expression the OIDC
+	// issuer of the certificate must match.
+	IssuerRegExp string `json:"issuerRegExp,omitempty"`
+}
@@ -27,7 +27,8 @@ func (a Attributes) Find(key string) model.Attribute {
 }
 
 // Exists returns whether a key,value pair exists in the
-// attribute set.
+// attribute set. If the stored value is a list and the input is not,
+// Exists reports whether the input is an element of the list.
 func (a Attributes) Exists(input model.Attribute) (bool, error) {
 	// Fail fast. Just check that the key exists and the Kinds match.
 	val, ok := a[input.Key()]
@@ -35,6 +36,34 @@ func (a Attributes) Exists(input model.Attribute) (bool, error) {
 		return false, nil
 	}
 
+	if val.Kind() == model.KindList && input.Kind() != model.KindList {
+		return contains(val, input)
+	}
+
+	return equal(val, input)
+}
+
+// contains returns whether the value is an element of the list attribute.
+func contains(list model.Attribute, value model.Attribute) (bool, error) {
+	elems, err := list.AsList()
+	if err != nil {
+		return false, err
+	}
+	for _, elem := range elems {
+		match, err := equal(elem, value)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// equal returns whether two attribute values are equal. Attribute
+// keys are not compared. Lists and maps are compared element-wise.
+func equal(val model.Attribute, input model.Attribute) (bool, error) {
 	if val.Kind() != input.Kind() {
 		return false, nil
 	}
@@ -85,6 +114,48 @@ func (a Attributes) Exists(input model.Attribute) (bool, error) {
 			return true, nil
 		}
 		return false, nil
+	case model.KindList:
+		outL, err := val.AsList()
+		if err != nil {
+			return false, err
+		}
+		inL, err := input.AsList()
+		if err != nil {
+			return false, err
+		}
+		if len(outL) != len(inL) {
+			return false, nil
+		}
+		for i := range outL {
+			match, err := equal(outL[i], inL[i])
+			if err != nil || !match {
+				return false, err
+			}
+		}
+		return true, nil
+	case model.KindMap:
+		outM, err := val.AsMap()
+		if err != nil {
+			return false, err
+		}
+		inM, err := input.AsMap()
+		if err != nil {
+			return false, err
+		}
+		if len(outM) != len(inM) {
+			return false, nil
+		}
+		for key, inVal := range inM {
+			outVal, ok := outM[key]
+			if !ok {
+				return false, nil
+			}
+			match, err := equal(outVal, inVal)
+			if err != nil || !match {
+				return false, err
+			}
+		}
+		return true, nil
 	default:
 		return false, nil
 	}
@@ -118,7 +189,7 @@ func (a Attributes) Len() int {
 // Merge attempts to merge multiple attribute sets. If a duplicate key
 // is found while merging, an error will be thrown if the value kind is
 // not the same. If the value types are the same, the first set will take
-// precedent.
+// precedent. Map values with the same key are merged recursively.
 func Merge(sets ...model.AttributeSet) (model.AttributeSet, error) {
 	newSet := Attributes{}
 
@@ -133,12 +204,54 @@ func Merge(sets ...model.AttributeSet) (model.AttributeSet, error) {
 	for _, set := range sets {
 		for key, value := range set.List() {
 			existingVal, exists := newSet[key]
-			if exists && existingVal.Kind() != value.Kind() {
-				return newSet, fmt.Errorf("key %s: %w", key, ErrWrongKind)
+			if !exists {
+				newSet[key] = value
+				continue
+			}
+			merged, err := mergeAttribute(key, existingVal, value)
+			if err != nil {
+				return newSet, err
 			}
-			newSet[key] = value
+			newSet[key] = merged
 		}
 	}
 
 	return newSet, nil
 }
+
+// mergeAttribute merges two attributes with the same key. The path
+// is used to identify nested keys in errors.
+func mergeAttribute(path string, existingVal, value model.Attribute) (model.Attribute, error) {
+	if existingVal.Kind() != value.Kind() {
+		return nil, fmt.Errorf("key %s: %w", path, ErrWrongKind)
+	}
+	if value.Kind() != model.KindMap {
+		return value, nil
+	}
+
+	existingMap, err := existingVal.AsMap()
+	if err != nil {
+		return nil, err
+	}
+	valueMap, err := value.AsMap()
+	if err != nil {
+		return nil, err
+	}
+	merged := make(map[string]model.Attribute, len(existingMap)+len(valueMap))
+	for key, nested := range existingMap {
+		merged[key] = nested
+	}
+	for key, nested := range valueMap {
+		existingNested, exists := merged[key]
+		if !exists {
+			merged[key] = nested
+			continue
+		}
+		mergedNested, err := mergeAttribute(path+"."+key, existingNested, nested)
+		if err != nil {
+			return nil, err
+		}
+		merged[key] = mergedNested
+	}
+	return NewMap(value.Key(), merged), nil
+}
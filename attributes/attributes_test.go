@@ -32,6 +32,43 @@ func TestAttributes_Exists(t *testing.T) {
 	require.True(t, exists)
 }
 
+func TestAttributes_ExistsListAndMap(t *testing.T) {
+	test := Attributes{
+		"tags": NewList("tags", []model.Attribute{
+			NewString("tags", "gpu"),
+			NewString("tags", "fp16"),
+		}),
+		"owner": NewMap("owner", map[string]model.Attribute{
+			"team": NewString("team", "x"),
+		}),
+	}
+	exists, err := test.Exists(NewString("tags", "fp16"))
+	require.NoError(t, err)
+	require.True(t, exists)
+	exists, err = test.Exists(NewString("tags", "int8"))
+	require.NoError(t, err)
+	require.False(t, exists)
+	exists, err = test.Exists(NewList("tags", []model.Attribute{
+		NewString("tags", "gpu"),
+		NewString("tags", "fp16"),
+	}))
+	require.NoError(t, err)
+	require.True(t, exists)
+	exists, err = test.Exists(NewList("tags", []model.Attribute{NewString("tags", "gpu")}))
+	require.NoError(t, err)
+	require.False(t, exists)
+	exists, err = test.Exists(NewMap("owner", map[string]model.Attribute{
+		"team": NewString("team", "x"),
+	}))
+	require.NoError(t, err)
+	require.True(t, exists)
+	exists, err = test.Exists(NewMap("owner", map[string]model.Attribute{
+		"team": NewString("team", "y"),
+	}))
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
 func TestAttributes_Find(t *testing.T) {
 	test := Attributes{
 		"name": NewString("name", "test"),
@@ -95,6 +132,36 @@ func TestMerge(t *testing.T) {
 			},
 			expString: `{"breed":"beagle","name":"pluto","size":2}`,
 		},
+		{
+			name: "Success/MergedNestedMaps",
+			set1: Attributes{
+				"owner": NewMap("owner", map[string]model.Attribute{
+					"team": NewString("team", "x"),
+				}),
+				"tags": NewList("tags", []model.Attribute{NewString("tags", "gpu")}),
+			},
+			set2: Attributes{
+				"owner": NewMap("owner", map[string]model.Attribute{
+					"email": NewString("email", "x@example.com"),
+				}),
+				"tags": NewList("tags", []model.Attribute{NewString("tags", "fp16")}),
+			},
+			expString: `{"owner":{"email":"x@example.com","team":"x"},"tags":["fp16"]}`,
+		},
+		{
+			name: "Failure/NestedTypeMismatch",
+			set1: Attributes{
+				"owner": NewMap("owner", map[string]model.Attribute{
+					"team": NewString("team", "x"),
+				}),
+			},
+			set2: Attributes{
+				"owner": NewMap("owner", map[string]model.Attribute{
+					"team": NewInt("team", 1),
+				}),
+			},
+			expError: "key owner.team: wrong value kind",
+		},
 		{
 			name: "Failure/TypeMismatch",
 			set1: Attributes{
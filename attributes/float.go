@@ -53,6 +53,18 @@ func (a floatAttribute) AsInt() (int64, error) {
 	return 0, ErrWrongKind
 }
 
+// AsList returns the value as a list of attributes and errors if that is not
+// the underlying type.
+func (a floatAttribute) AsList() ([]model.Attribute, error) {
+	return nil, ErrWrongKind
+}
+
+// AsMap returns the value as a map of attributes and errors if that is not
+// the underlying type.
+func (a floatAttribute) AsMap() (map[string]model.Attribute, error) {
+	return nil, ErrWrongKind
+}
+
 // AsAny returns the value as an interface.
 func (a floatAttribute) AsAny() interface{} {
 	return a.value
@@ -53,6 +53,18 @@ func (a intAttribute) AsInt() (int64, error) {
 	return a.value, nil
 }
 
+// AsList returns the value as a list of attributes and errors if that is not
+// the underlying type.
+func (a intAttribute) AsList() ([]model.Attribute, error) {
+	return nil, ErrWrongKind
+}
+
+// AsMap returns the value as a map of attributes and errors if that is not
+// the underlying type.
+func (a intAttribute) AsMap() (map[string]model.Attribute, error) {
+	return nil, ErrWrongKind
+}
+
 // AsAny returns the value as an interface.
 func (a intAttribute) AsAny() interface{} {
 	return a.value
@@ -0,0 +1,76 @@
+package attributes
+
+import "github.com/emporous/emporous-go/model"
+
+type listAttribute struct {
+	key   string
+	value []model.Attribute
+}
+
+var _ model.Attribute = listAttribute{}
+
+// NewList returns a new list attribute. By convention, list elements
+// share the key of the list.
+func NewList(key string, value []model.Attribute) model.Attribute {
+	return listAttribute{key: key, value: value}
+}
+
+// Kind returns the kind for the attribute.
+func (a listAttribute) Kind() model.Kind {
+	return model.KindList
+}
+
+// Key return the attribute key.
+func (a listAttribute) Key() string {
+	return a.key
+}
+
+// IsNull returns whether the value is null.
+func (a listAttribute) IsNull() bool {
+	return false
+}
+
+// AsBool returns the value as a boolean and errors if that is not
+// the underlying type.
+func (a listAttribute) AsBool() (bool, error) {
+	return false, ErrWrongKind
+}
+
+// AsString returns the value as a string and errors if that is not
+// the underlying type.
+func (a listAttribute) AsString() (string, error) {
+	return "", ErrWrongKind
+}
+
+// AsFloat returns the value as a float value and errors if that is not
+// the underlying type.
+func (a listAttribute) AsFloat() (float64, error) {
+	return 0, ErrWrongKind
+}
+
+// AsInt returns the value as an int value errors and if that is not
+// the underlying type.
+func (a listAttribute) AsInt() (int64, error) {
+	return 0, ErrWrongKind
+}
+
+// AsList returns the value as a list of attributes and errors if that is not
+// the underlying type.
+func (a listAttribute) AsList() ([]model.Attribute, error) {
+	return a.value, nil
+}
+
+// AsMap returns the value as a map of attributes and errors if that is not
+// the underlying type.
+func (a listAttribute) AsMap() (map[string]model.Attribute, error) {
+	return nil, ErrWrongKind
+}
+
+// AsAny returns the value as an interface.
+func (a listAttribute) AsAny() interface{} {
+	values := make([]interface{}, 0, len(a.value))
+	for _, elem := range a.value {
+		values = append(values, elem.AsAny())
+	}
+	return values
+}
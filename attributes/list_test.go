@@ -0,0 +1,52 @@
+package attributes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/model"
+)
+
+func TestListAttribute_Kind(t *testing.T) {
+	test := NewList("test", []model.Attribute{NewString("test", "testvalue")})
+	require.Equal(t, model.KindList, test.Kind())
+}
+
+func TestListAttribute_AsBool(t *testing.T) {
+	test := NewList("test", []model.Attribute{NewString("test", "testvalue")})
+	l, err := test.AsBool()
+	require.ErrorIs(t, ErrWrongKind, err)
+	require.Equal(t, false, l)
+}
+
+func TestListAttribute_AsString(t *testing.T) {
+	test := NewList("test", []model.Attribute{NewString("test", "testvalue")})
+	l, err := test.AsString()
+	require.ErrorIs(t, ErrWrongKind, err)
+	require.Equal(t, "", l)
+}
+
+func TestListAttribute_AsMap(t *testing.T) {
+	test := NewList("test", []model.Attribute{NewString("test", "testvalue")})
+	l, err := test.AsMap()
+	require.ErrorIs(t, ErrWrongKind, err)
+	require.Nil(t, l)
+}
+
+func TestListAttribute_AsList(t *testing.T) {
+	test := NewList("test", []model.Attribute{NewString("test", "testvalue")})
+	l, err := test.AsList()
+	require.NoError(t, err)
+	require.Equal(t, []model.Attribute{NewString("test", "testvalue")}, l)
+}
+
+func TestListAttribute_AsAny(t *testing.T) {
+	test := NewList("test", []model.Attribute{NewString("test", "testvalue"), NewInt("test", 1)})
+	require.Equal(t, []interface{}{"testvalue", int64(1)}, test.AsAny())
+}
+
+func TestListAttribute_IsNull(t *testing.T) {
+	test := NewList("test", nil)
+	require.False(t, test.IsNull())
+}
@@ -0,0 +1,76 @@
+package attributes
+
+import "github.com/emporous/emporous-go/model"
+
+type mapAttribute struct {
+	key   string
+	value map[string]model.Attribute
+}
+
+var _ model.Attribute = mapAttribute{}
+
+// NewMap returns a new map attribute. The map is keyed by
+// the keys of the nested attributes.
+func NewMap(key string, value map[string]model.Attribute) model.Attribute {
+	return mapAttribute{key: key, value: value}
+}
+
+// Kind returns the kind for the attribute.
+func (a mapAttribute) Kind() model.Kind {
+	return model.KindMap
+}
+
+// Key return the attribute key.
+func (a mapAttribute) Key() string {
+	return a.key
+}
+
+// IsNull returns whether the value is null.
+func (a mapAttribute) IsNull() bool {
+	return false
+}
+
+// AsBool returns the value as a boolean and errors if that is not
+// the underlying type.
+func (a mapAttribute) AsBool() (bool, error) {
+	return false, ErrWrongKind
+}
+
+// AsString returns the value as a string and errors if that is not
+// the underlying type.
+func (a mapAttribute) AsString() (string, error) {
+	return "", ErrWrongKind
+}
+
+// AsFloat returns the value as a float value and errors if that is not
+// the underlying type.
+func (a mapAttribute) AsFloat() (float64, error) {
+	return 0, ErrWrongKind
+}
+
+// AsInt returns the value as an int value errors and if that is not
+// the underlying type.
+func (a mapAttribute) AsInt() (int64, error) {
+	return 0, ErrWrongKind
+}
+
+// AsList returns the value as a list of attributes and errors if that is not
+// the underlying type.
+func (a mapAttribute) AsList() ([]model.Attribute, error) {
+	return nil, ErrWrongKind
+}
+
+// AsMap returns the value as a map of attributes and errors if that is not
+// the underlying type.
+func (a mapAttribute) AsMap() (map[string]model.Attribute, error) {
+	return a.value, nil
+}
+
+// AsAny returns the value as an interface.
+func (a mapAttribute) AsAny() interface{} {
+	values := make(map[string]interface{}, len(a.value))
+	for key, elem := range a.value {
+		values[key] = elem.AsAny()
+	}
+	return values
+}
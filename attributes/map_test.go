@@ -0,0 +1,48 @@
+package attributes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/model"
+)
+
+func TestMapAttribute_Kind(t *testing.T) {
+	test := NewMap("test", map[string]model.Attribute{"team": NewString("team", "x")})
+	require.Equal(t, model.KindMap, test.Kind())
+}
+
+func TestMapAttribute_AsBool(t *testing.T) {
+	test := NewMap("test", map[string]model.Attribute{"team": NewString("team", "x")})
+	m, err := test.AsBool()
+	require.ErrorIs(t, ErrWrongKind, err)
+	require.Equal(t, false, m)
+}
+
+func TestMapAttribute_AsList(t *testing.T) {
+	test := NewMap("test", map[string]model.Attribute{"team": NewString("team", "x")})
+	m, err := test.AsList()
+	require.ErrorIs(t, ErrWrongKind, err)
+	require.Nil(t, m)
+}
+
+func TestMapAttribute_AsMap(t *testing.T) {
+	test := NewMap("test", map[string]model.Attribute{"team": NewString("team", "x")})
+	m, err := test.AsMap()
+	require.NoError(t, err)
+	require.Equal(t, map[string]model.Attribute{"team": NewString("team", "x")}, m)
+}
+
+func TestMapAttribute_AsAny(t *testing.T) {
+	test := NewMap("test", map[string]model.Attribute{
+		"team": NewString("team", "x"),
+		"tags": NewList("tags", []model.Attribute{NewBool("tags", true)}),
+	})
+	require.Equal(t, map[string]interface{}{"team": "x", "tags": []interface{}{true}}, test.AsAny())
+}
+
+func TestMapAttribute_IsNull(t *testing.T) {
+	test := NewMap("test", nil)
+	require.False(t, test.IsNull())
+}
@@ -0,0 +1,592 @@
+package matchers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/emporous/emporous-go/model"
+)
+
+var _ model.Matcher = &ExpressionMatcher{}
+
+// ExpressionMatcher evaluates a boolean attribute query expression against
+// node attributes.
+//
+// Expressions are built from comparisons of an attribute key with a literal value
+// combined with the logical operators "&&", "||", and "!" and grouped with parentheses.
+// Supported comparisons are:
+//
+//	key                   the attribute is the boolean true
+//	key == value          the attribute is equal to value (string, number, bool, or null)
+//	key != value          the attribute is not equal to value
+//	key < number          numeric comparisons with <, <=, >, and >=
+//	key in low..high      the attribute is a number in the inclusive range
+//	key ^= "prefix"       the attribute is a string starting with prefix
+//	key =~ "regex"        the attribute is a string matching the regular expression
+//	key contains value    the attribute is a list with an element equal to value
+//
+// A key can be qualified with a schema ID using "schema.key" and keys of nested
+// map attributes are separated with dots, as in "owner.team". Comparisons against
+// a key that does not exist evaluate to false, with the exception of "!=" and
+// "== null".
+type ExpressionMatcher struct {
+	expression string
+	root       expression
+}
+
+// ParseExpression parses a query expression into an ExpressionMatcher.
+func ParseExpression(input string) (*ExpressionMatcher, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, &ExpressionError{Pos: tok.pos, Err: fmt.Errorf("unexpected %q", tok.value)}
+	}
+	return &ExpressionMatcher{expression: input, root: root}, nil
+}
+
+// Matches determines whether the node attributes satisfy the expression.
+func (m *ExpressionMatcher) Matches(n model.Node) (bool, error) {
+	attr := n.Attributes()
+	if attr == nil {
+		return false, errors.New("node attributes cannot be nil")
+	}
+	return m.root.eval(attr)
+}
+
+// String returns the original expression.
+func (m *ExpressionMatcher) String() string {
+	return m.expression
+}
+
+// ExpressionError describes a syntax error in a query expression.
+type ExpressionError struct {
+	Pos int
+	Err error
+}
+
+func (e *ExpressionError) Error() string {
+	return fmt.Sprintf("invalid expression at position %d: %v", e.Pos, e.Err)
+}
+
+func (e *ExpressionError) Unwrap() error {
+	return e.Err
+}
+
+// expression is a node in the parsed expression tree.
+type expression interface {
+	eval(model.AttributeSet) (bool, error)
+}
+
+type andExpression struct {
+	left, right expression
+}
+
+func (e andExpression) eval(set model.AttributeSet) (bool, error) {
+	l, err := e.left.eval(set)
+	if err != nil || !l {
+		return false, err
+	}
+	return e.right.eval(set)
+}
+
+type orExpression struct {
+	left, right expression
+}
+
+func (e orExpression) eval(set model.AttributeSet) (bool, error) {
+	l, err := e.left.eval(set)
+	if err != nil || l {
+		return l, err
+	}
+	return e.right.eval(set)
+}
+
+type notExpression struct {
+	expr expression
+}
+
+func (e notExpression) eval(set model.AttributeSet) (bool, error) {
+	res, err := e.expr.eval(set)
+	return !res, err
+}
+
+type comparison struct {
+	key   string
+	op    string
+	value interface{}
+	// low and high are set for range comparisons.
+	low, high float64
+	re        *regexp.Regexp
+}
+
+func (c comparison) eval(set model.AttributeSet) (bool, error) {
+	attr := findAttribute(set, c.key)
+	if attr == nil {
+		switch c.op {
+		case "!=":
+			return true, nil
+		case "==":
+			return c.value == nil, nil
+		}
+		return false, nil
+	}
+
+	switch c.op {
+	case "":
+		if attr.Kind() != model.KindBool {
+			return false, nil
+		}
+		return attr.AsBool()
+	case "==", "!=":
+		equal, err := equals(attr, c.value)
+		if err != nil {
+			return false, err
+		}
+		return equal == (c.op == "=="), nil
+	case "<", "<=", ">", ">=":
+		num, ok, err := asNumber(attr)
+		if err != nil || !ok {
+			return false, err
+		}
+		limit := c.value.(float64)
+		switch c.op {
+		case "<":
+			return num < limit, nil
+		case "<=":
+			return num <= limit, nil
+		case ">":
+			return num > limit, nil
+		default:
+			return num >= limit, nil
+		}
+	case "in":
+		num, ok, err := asNumber(attr)
+		if err != nil || !ok {
+			return false, err
+		}
+		return num >= c.low && num <= c.high, nil
+	case "^=", "=~":
+		if attr.Kind() != model.KindString {
+			return false, nil
+		}
+		s, err := attr.AsString()
+		if err != nil {
+			return false, err
+		}
+		if c.op == "^=" {
+			return strings.HasPrefix(s, c.value.(string)), nil
+		}
+		return c.re.MatchString(s), nil
+	case "contains":
+		if attr.Kind() != model.KindList {
+			return false, nil
+		}
+		elems, err := attr.AsList()
+		if err != nil {
+			return false, err
+		}
+		for _, elem := range elems {
+			equal, err := equals(elem, c.value)
+			if err != nil || equal {
+				return equal, err
+			}
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("unsupported operator %q", c.op)
+	}
+}
+
+// schemaFinder is implemented by attribute sets that group
+// attributes by schema ID.
+type schemaFinder interface {
+	FindBySchema(schema, key string) model.Attribute
+}
+
+// findAttribute returns the attribute for the key. If the key is not found and is
+// qualified by a schema ID, the attribute is searched for under that schema. Otherwise,
+// the key is treated as a path to a value in nested map attributes.
+func findAttribute(set model.AttributeSet, key string) model.Attribute {
+	if attr := set.Find(key); attr != nil {
+		return attr
+	}
+	parts := strings.Split(key, ".")
+	if len(parts) < 2 {
+		return nil
+	}
+	finder, ok := set.(schemaFinder)
+	if ok {
+		if attr := finder.FindBySchema(parts[0], strings.Join(parts[1:], ".")); attr != nil {
+			return attr
+		}
+	}
+	if attr := findNested(set.Find(parts[0]), parts[1:]); attr != nil {
+		return attr
+	}
+	if ok {
+		return findNested(finder.FindBySchema(parts[0], parts[1]), parts[2:])
+	}
+	return nil
+}
+
+// findNested returns the attribute at the path within nested
+// map attributes.
+func findNested(attr model.Attribute, path []string) model.Attribute {
+	for _, key := range path {
+		if attr == nil || attr.Kind() != model.KindMap {
+			return nil
+		}
+		values, err := attr.AsMap()
+		if err != nil {
+			return nil
+		}
+		attr = values[key]
+	}
+	return attr
+}
+
+// asNumber returns the attribute value as a float if the attribute
+// is numeric.
+func asNumber(attr model.Attribute) (float64, bool, error) {
+	switch attr.Kind() {
+	case model.KindInt:
+		i, err := attr.AsInt()
+		return float64(i), err == nil, err
+	case model.KindFloat:
+		f, err := attr.AsFloat()
+		return f, err == nil, err
+	default:
+		return 0, false, nil
+	}
+}
+
+// equals compares the attribute value with a literal value.
+func equals(attr model.Attribute, value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return attr.IsNull(), nil
+	case bool:
+		if attr.Kind() != model.KindBool {
+			return false, nil
+		}
+		b, err := attr.AsBool()
+		return b == v, err
+	case string:
+		if attr.Kind() != model.KindString {
+			return false, nil
+		}
+		s, err := attr.AsString()
+		return s == v, err
+	case float64:
+		num, ok, err := asNumber(attr)
+		return ok && num == v, err
+	default:
+		return false, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// operators lists all supported operators. Longer operators
+// must be listed before their prefixes.
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "^=", "..", "<", ">", "!"}
+
+// lex splits the input into tokens.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		r, width := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += width
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: i})
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for ; end < len(input) && input[end] != input[i]; end++ {
+				if input[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(input) {
+				return nil, &ExpressionError{Pos: i, Err: errors.New("unterminated string")}
+			}
+			value := input[i+1 : end]
+			if r == '"' {
+				var err error
+				value, err = strconv.Unquote(input[i : end+1])
+				if err != nil {
+					return nil, &ExpressionError{Pos: i, Err: err}
+				}
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: i})
+			i = end + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(input) && unicode.IsDigit(rune(input[i+1]))):
+			end := i + 1
+			for end < len(input) {
+				c := input[end]
+				if unicode.IsDigit(rune(c)) || c == 'e' || c == 'E' ||
+					((c == '+' || c == '-') && (input[end-1] == 'e' || input[end-1] == 'E')) ||
+					(c == '.' && !strings.HasPrefix(input[end:], "..")) {
+					end++
+					continue
+				}
+				break
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: input[i:end], pos: i})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i + width
+			for end < len(input) {
+				c, size := utf8.DecodeRuneInString(input[end:])
+				if !isIdentChar(c) {
+					break
+				}
+				end += size
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: input[i:end], pos: i})
+			i = end
+		default:
+			var matched bool
+			for _, op := range operators {
+				if strings.HasPrefix(input[i:], op) {
+					tokens = append(tokens, token{kind: tokenOperator, value: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &ExpressionError{Pos: i, Err: fmt.Errorf("unexpected character %q", r)}
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(input)}), nil
+}
+
+func isIdentChar(r rune) bool {
+	return r == '_' || r == '-' || r == '.' || r == '/' ||
+		unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// parser is a recursive descent parser for query expressions.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) parseOr() (expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.kind == tokenOperator && tok.value == "||"; tok = p.peek() {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpression{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.kind == tokenOperator && tok.value == "&&"; tok = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpression{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (expression, error) {
+	if tok := p.peek(); tok.kind == tokenOperator && tok.value == "!" {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpression{expr: expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expression, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &ExpressionError{Pos: closing.pos, Err: errors.New("expected \")\"")}
+		}
+		return expr, nil
+	case tokenIdent:
+		return p.parseComparison(tok)
+	case tokenEOF:
+		return nil, &ExpressionError{Pos: tok.pos, Err: errors.New("unexpected end of expression")}
+	default:
+		return nil, &ExpressionError{Pos: tok.pos, Err: fmt.Errorf("expected attribute key, got %q", tok.value)}
+	}
+}
+
+func (p *parser) parseComparison(key token) (expression, error) {
+	c := comparison{key: key.value}
+
+	op := p.peek()
+	switch {
+	case op.kind == tokenIdent && op.value == "in":
+		p.next()
+		return p.parseRange(c)
+	case op.kind == tokenIdent && op.value == "contains":
+		p.next()
+		c.op = op.value
+		value, err := literal(p.next())
+		if err != nil {
+			return nil, err
+		}
+		c.value = value
+		return c, nil
+	case op.kind != tokenOperator:
+		return c, nil
+	}
+
+	switch op.value {
+	case "==", "!=", "<", "<=", ">", ">=", "^=", "=~":
+		p.next()
+	default:
+		// Logical operators are handled by the caller.
+		return c, nil
+	}
+	c.op = op.value
+
+	valueTok := p.next()
+	value, err := literal(valueTok)
+	if err != nil {
+		return nil, err
+	}
+	c.value = value
+
+	switch c.op {
+	case "<", "<=", ">", ">=":
+		if _, ok := value.(float64); !ok {
+			return nil, &ExpressionError{Pos: valueTok.pos, Err: fmt.Errorf("operator %q requires a number", c.op)}
+		}
+	case "^=", "=~":
+		s, ok := value.(string)
+		if !ok {
+			return nil, &ExpressionError{Pos: valueTok.pos, Err: fmt.Errorf("operator %q requires a string", c.op)}
+		}
+		if c.op == "=~" {
+			c.re, err = regexp.Compile(s)
+			if err != nil {
+				return nil, &ExpressionError{Pos: valueTok.pos, Err: err}
+			}
+		}
+	}
+	return c, nil
+}
+
+func (p *parser) parseRange(c comparison) (expression, error) {
+	c.op = "in"
+	lowTok := p.next()
+	if lowTok.kind != tokenNumber {
+		return nil, &ExpressionError{Pos: lowTok.pos, Err: errors.New("expected number for range start")}
+	}
+	if sep := p.next(); sep.kind != tokenOperator || sep.value != ".." {
+		return nil, &ExpressionError{Pos: sep.pos, Err: errors.New("expected \"..\" in range")}
+	}
+	highTok := p.next()
+	if highTok.kind != tokenNumber {
+		return nil, &ExpressionError{Pos: highTok.pos, Err: errors.New("expected number for range end")}
+	}
+	var err error
+	if c.low, err = strconv.ParseFloat(lowTok.value, 64); err != nil {
+		return nil, &ExpressionError{Pos: lowTok.pos, Err: err}
+	}
+	if c.high, err = strconv.ParseFloat(highTok.value, 64); err != nil {
+		return nil, &ExpressionError{Pos: highTok.pos, Err: err}
+	}
+	if c.low > c.high {
+		return nil, &ExpressionError{Pos: lowTok.pos, Err: errors.New("range start is greater than range end")}
+	}
+	return c, nil
+}
+
+// literal converts a token into a literal value.
+func literal(tok token) (interface{}, error) {
+	switch tok.kind {
+	case tokenString:
+		return tok.value, nil
+	case tokenNumber:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, &ExpressionError{Pos: tok.pos, Err: err}
+		}
+		return f, nil
+	case tokenIdent:
+		switch tok.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+	}
+	return nil, &ExpressionError{Pos: tok.pos, Err: fmt.Errorf("expected literal value, got %q", tok.value)}
+}
@@ -0,0 +1,233 @@
+package matchers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/attributes"
+	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/nodes/descriptor"
+	"github.com/emporous/emporous-go/util/testutils"
+)
+
+func TestExpressionMatches(t *testing.T) {
+	mockAttributes := attributes.Attributes{
+		"type":       attributes.NewString("type", "model"),
+		"name":       attributes.NewString("name", "resnet-50.onnx"),
+		"size":       attributes.NewInt("size", 2048),
+		"accuracy":   attributes.NewFloat("accuracy", 0.92),
+		"deprecated": attributes.NewBool("deprecated", false),
+		"owner":      attributes.NewNull("owner"),
+		"tags": attributes.NewList("tags", []model.Attribute{
+			attributes.NewString("tags", "gpu"),
+			attributes.NewString("tags", "fp16"),
+		}),
+		"labels": attributes.NewMap("labels", map[string]model.Attribute{
+			"team": attributes.NewString("team", "vision"),
+		}),
+	}
+
+	type spec struct {
+		name       string
+		expression string
+		attributes model.AttributeSet
+		expRes     bool
+	}
+
+	cases := []spec{
+		{
+			name:       "Success/Combined",
+			expression: `size > 1024 && (type == "model" || type == "weights") && !deprecated`,
+			expRes:     true,
+		},
+		{
+			name:       "Success/StringEquality",
+			expression: `type == "weights"`,
+			expRes:     false,
+		},
+		{
+			name:       "Success/NotEqual",
+			expression: `type != "weights"`,
+			expRes:     true,
+		},
+		{
+			name:       "Success/FloatComparison",
+			expression: "accuracy >= 0.9 && accuracy < 1",
+			expRes:     true,
+		},
+		{
+			name:       "Success/IntEqualsNumber",
+			expression: "size == 2048",
+			expRes:     true,
+		},
+		{
+			name:       "Success/Range",
+			expression: "size in 1024..4096",
+			expRes:     true,
+		},
+		{
+			name:       "Success/OutOfRange",
+			expression: "size in 0..1024",
+			expRes:     false,
+		},
+		{
+			name:       "Success/Prefix",
+			expression: `name ^= "resnet"`,
+			expRes:     true,
+		},
+		{
+			name:       "Success/Regex",
+			expression: `name =~ "\\.onnx$"`,
+			expRes:     true,
+		},
+		{
+			name:       "Success/NullValue",
+			expression: "owner == null",
+			expRes:     true,
+		},
+		{
+			name:       "Success/MissingKeyIsNull",
+			expression: "license == null",
+			expRes:     true,
+		},
+		{
+			name:       "Success/MissingKeyComparison",
+			expression: "license > 1 || license == \"MIT\"",
+			expRes:     false,
+		},
+		{
+			name:       "Success/KindMismatch",
+			expression: "type > 1",
+			expRes:     false,
+		},
+		{
+			name:       "Success/BareKeyNotBool",
+			expression: "type",
+			expRes:     false,
+		},
+		{
+			name:       "Success/ListContains",
+			expression: `tags contains "fp16"`,
+			expRes:     true,
+		},
+		{
+			name:       "Success/ListNotContains",
+			expression: `tags contains "int8"`,
+			expRes:     false,
+		},
+		{
+			name:       "Success/ContainsNotList",
+			expression: `type contains "model"`,
+			expRes:     false,
+		},
+		{
+			name:       "Success/NestedMapKey",
+			expression: `labels.team == "vision"`,
+			expRes:     true,
+		},
+		{
+			name:       "Success/MissingNestedMapKey",
+			expression: `labels.owner == "vision"`,
+			expRes:     false,
+		},
+		{
+			name:       "Success/NonASCIIKey",
+			expression: `café == 1 && größe > 2`,
+			attributes: attributes.Attributes{
+				"café":  attributes.NewInt("café", 1),
+				"größe": attributes.NewInt("größe", 3),
+			},
+			expRes: true,
+		},
+		{
+			name:       "Success/SchemaQualifiedKey",
+			expression: `myschema.color == "orange"`,
+			attributes: &descriptor.Properties{
+				Others: map[string]model.AttributeSet{
+					"myschema": attributes.Attributes{
+						"color": attributes.NewString("color", "orange"),
+					},
+				},
+			},
+			expRes: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m, err := ParseExpression(c.expression)
+			require.NoError(t, err)
+			set := c.attributes
+			if set == nil {
+				set = mockAttributes
+			}
+			match, err := m.Matches(&testutils.FakeNode{A: set})
+			require.NoError(t, err)
+			require.Equal(t, c.expRes, match)
+		})
+	}
+}
+
+func TestParseExpression(t *testing.T) {
+	type spec struct {
+		name       string
+		expression string
+		expError   string
+	}
+
+	cases := []spec{
+		{
+			name:       "Failure/UnbalancedParentheses",
+			expression: `(size > 1`,
+			expError:   "invalid expression at position 9: expected \")\"",
+		},
+		{
+			name:       "Failure/NumericOperatorWithString",
+			expression: `size > "big"`,
+			expError:   "invalid expression at position 7: operator \">\" requires a number",
+		},
+		{
+			name:       "Failure/InvalidRegex",
+			expression: `name =~ "("`,
+			expError:   "invalid expression at position 8: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name:       "Failure/InvalidRange",
+			expression: `size in 10..1`,
+			expError:   "invalid expression at position 8: range start is greater than range end",
+		},
+		{
+			name:       "Failure/ContainsMissingValue",
+			expression: `tags contains`,
+			expError:   "invalid expression at position 13: expected literal value, got \"\"",
+		},
+		{
+			name:       "Failure/TrailingTokens",
+			expression: `size > 1 size`,
+			expError:   "invalid expression at position 9: unexpected \"size\"",
+		},
+		{
+			name:       "Failure/UnterminatedString",
+			expression: `type == "model`,
+			expError:   "invalid expression at position 8: unterminated string",
+		},
+		{
+			name:       "Failure/UnexpectedNonASCIICharacter",
+			expression: `size > 1 § 2`,
+			expError:   "invalid expression at position 9: unexpected character '§'",
+		},
+		{
+			name:       "Failure/Empty",
+			expression: ``,
+			expError:   "invalid expression at position 0: unexpected end of expression",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := ParseExpression(c.expression)
+			require.EqualError(t, err, c.expError)
+		})
+	}
+}
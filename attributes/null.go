@@ -52,6 +52,18 @@ func (a nullAttribute) AsInt() (int64, error) {
 	return 0, ErrWrongKind
 }
 
+// AsList returns the value as a list of attributes and errors if that is not
+// the underlying type.
+func (a nullAttribute) AsList() ([]model.Attribute, error) {
+	return nil, ErrWrongKind
+}
+
+// AsMap returns the value as a map of attributes and errors if that is not
+// the underlying type.
+func (a nullAttribute) AsMap() (map[string]model.Attribute, error) {
+	return nil, ErrWrongKind
+}
+
 // AsAny returns the value as an interface.
 func (a nullAttribute) AsAny() interface{} {
 	return nil
@@ -38,6 +38,32 @@ func Reflect(key string, value interface{}) (model.Attribute, error) {
 		return NewFloat(key, reflectVal.Float()), nil
 	case reflect.String:
 		return NewString(key, reflectVal.String()), nil
+	case reflect.Slice, reflect.Array:
+		// List elements share the key of the list
+		values := make([]model.Attribute, 0, reflectVal.Len())
+		for i := 0; i < reflectVal.Len(); i++ {
+			elem, err := Reflect(key, reflectVal.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, elem)
+		}
+		return NewList(key, values), nil
+	case reflect.Map:
+		if reflectVal.Type().Key().Kind() != reflect.String {
+			return nil, ErrInvalidAttribute
+		}
+		values := make(map[string]model.Attribute, reflectVal.Len())
+		iter := reflectVal.MapRange()
+		for iter.Next() {
+			nestedKey := iter.Key().String()
+			elem, err := Reflect(nestedKey, iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			values[nestedKey] = elem
+		}
+		return NewMap(key, values), nil
 	default:
 		return nil, ErrInvalidAttribute
 	}
@@ -0,0 +1,76 @@
+package attributes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/model"
+)
+
+func TestReflect(t *testing.T) {
+	type spec struct {
+		name     string
+		value    interface{}
+		expKind  model.Kind
+		expAny   interface{}
+		expError error
+	}
+
+	cases := []spec{
+		{
+			name:    "Success/String",
+			value:   "test",
+			expKind: model.KindString,
+			expAny:  "test",
+		},
+		{
+			name:    "Success/Int",
+			value:   2,
+			expKind: model.KindInt,
+			expAny:  int64(2),
+		},
+		{
+			name:    "Success/List",
+			value:   []interface{}{"gpu", "fp16"},
+			expKind: model.KindList,
+			expAny:  []interface{}{"gpu", "fp16"},
+		},
+		{
+			name:    "Success/TypedList",
+			value:   []string{"gpu"},
+			expKind: model.KindList,
+			expAny:  []interface{}{"gpu"},
+		},
+		{
+			name:    "Success/Map",
+			value:   map[string]interface{}{"team": "x", "members": []interface{}{"a"}},
+			expKind: model.KindMap,
+			expAny:  map[string]interface{}{"team": "x", "members": []interface{}{"a"}},
+		},
+		{
+			name:     "Failure/NonStringMapKey",
+			value:    map[int]string{1: "x"},
+			expError: ErrInvalidAttribute,
+		},
+		{
+			name:     "Failure/InvalidNestedValue",
+			value:    []interface{}{struct{}{}},
+			expError: ErrInvalidAttribute,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			attr, err := Reflect("test", c.value)
+			if c.expError != nil {
+				require.ErrorIs(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "test", attr.Key())
+				require.Equal(t, c.expKind, attr.Kind())
+				require.Equal(t, c.expAny, attr.AsAny())
+			}
+		})
+	}
+}
@@ -53,6 +53,18 @@ func (a stringAttribute) AsInt() (int64, error) {
 	return 0, ErrWrongKind
 }
 
+// AsList returns the value as a list of attributes and errors if that is not
+// the underlying type.
+func (a stringAttribute) AsList() ([]model.Attribute, error) {
+	return nil, ErrWrongKind
+}
+
+// AsMap returns the value as a map of attributes and errors if that is not
+// the underlying type.
+func (a stringAttribute) AsMap() (map[string]model.Attribute, error) {
+	return nil, ErrWrongKind
+}
+
 // AsAny returns the value as an interface.
 func (a stringAttribute) AsAny() interface{} {
 	return a.value
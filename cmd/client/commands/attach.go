@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// AttachOptions describe configuration options that can
+// be set using the attach subcommand.
+type AttachOptions struct {
+	*options.Common
+	options.Remote
+	options.RemoteAuth
+	Subject      string
+	Files        []string
+	ArtifactType string
+	Annotations  map[string]string
+}
+
+var clientAttachExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "attach localhost:5001/test:latest --type application/spdx+json sbom.spdx.json",
+		Descriptions: []string{
+			"Attach an SPDX SBOM to a collection.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "attach localhost:5001/test:latest --type application/vnd.in-toto+json --annotation builder=ci attestation.json",
+		Descriptions: []string{
+			"Attach an attestation with annotations to a collection.",
+		},
+	},
+}
+
+// NewAttachCmd creates a new cobra.Command for the attach subcommand.
+func NewAttachCmd(common *options.Common) *cobra.Command {
+	o := AttachOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "attach REF FILE...",
+		Short:         "Attach artifacts such as SBOMs and attestations to a Emporous collection",
+		Long:          "Push an artifact whose subject is the collection manifest so it can be discovered with the OCI referrers API",
+		Example:       examples.FormatExamples(clientAttachExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+
+	cmd.Flags().StringVarP(&o.ArtifactType, "type", "t", o.ArtifactType, "Artifact type of the attached artifact (e.g. application/spdx+json)")
+	cmd.Flags().StringToStringVar(&o.Annotations, "annotation", o.Annotations, "Annotations of the artifact manifest in key=value format")
+
+	return cmd
+}
+
+func (o *AttachOptions) Complete(args []string) error {
+	if len(args) < 2 {
+		return errors.New("bug: expecting at least two arguments")
+	}
+	o.Subject = args[0]
+	o.Files = args[1:]
+	return nil
+}
+
+func (o *AttachOptions) Validate() error {
+	if o.ArtifactType == "" {
+		return errors.New("--type must be set")
+	}
+	for _, file := range o.Files {
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("artifact file %q: %v", file, err)
+		}
+	}
+	return nil
+}
+
+func (o *AttachOptions) Run(ctx context.Context) error {
+	registryConfig, err := o.Remote.RegistryConfiguration()
+	if err != nil {
+		return err
+	}
+
+	client, err := orasclient.NewClient(
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithCAFile(o.CAFile),
+		orasclient.WithClientCertificate(o.CertFile, o.KeyFile),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+		orasclient.WithRetryPolicy(o.Remote.RetryPolicy()),
+		orasclient.WithLogger(o.Logger),
+		orasclient.WithRegistryConfig(registryConfig),
+	)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	desc, err := client.Attach(ctx, o.Subject, o.ArtifactType, o.Annotations, o.Files...)
+	if err != nil {
+		return err
+	}
+	o.Logger.Infof("Attached %s to %s", desc.Digest, o.Subject)
+	return o.PrintResult(AttachResult{Subject: o.Subject, Descriptor: desc}, nil)
+}
+
+// AttachResult describes an artifact
+// attached to a subject collection.
+type AttachResult struct {
+	Subject    string             `json:"subject"`
+	Descriptor ocispec.Descriptor `json:"descriptor"`
+}
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/log"
+)
+
+func TestAttachValidate(t *testing.T) {
+	type spec struct {
+		name     string
+		opts     *AttachOptions
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/ArtifactType",
+			opts: &AttachOptions{
+				ArtifactType: "application/spdx+json",
+				Files:        []string{"testdata/flatworkspace/fish.jpg"},
+			},
+		},
+		{
+			name: "Invalid/NoArtifactType",
+			opts: &AttachOptions{
+				Files: []string{"testdata/flatworkspace/fish.jpg"},
+			},
+			expError: "--type must be set",
+		},
+		{
+			name: "Invalid/FileDoesNotExist",
+			opts: &AttachOptions{
+				ArtifactType: "application/spdx+json",
+				Files:        []string{"testdata/fake.json"},
+			},
+			expError: "artifact file \"testdata/fake.json\": stat testdata/fake.json: no such file or directory",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.opts.Validate()
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAttachDiscoverRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(io.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	common := &options.Common{
+		IOStreams: genericclioptions.IOStreams{
+			Out:    out,
+			In:     os.Stdin,
+			ErrOut: os.Stderr,
+		},
+		Logger:   testlogr,
+		CacheDir: t.TempDir(),
+	}
+	remote := options.Remote{PlainHTTP: true}
+	reference := fmt.Sprintf("%s/attach:latest", u.Host)
+	require.NoError(t, prepCache(reference, common.CacheDir, nil))
+	push := &PushOptions{Common: common, Remote: remote, Destination: reference}
+	require.NoError(t, push.Run(context.TODO()))
+
+	sbomFile := filepath.Join(t.TempDir(), "sbom.spdx.json")
+	require.NoError(t, os.WriteFile(sbomFile, []byte(`{"spdxVersion":"SPDX-2.3"}`), 0600))
+	attach := &AttachOptions{
+		Common:       common,
+		Remote:       remote,
+		Subject:      reference,
+		Files:        []string{sbomFile},
+		ArtifactType: "application/spdx+json",
+	}
+	require.NoError(t, attach.Run(context.TODO()))
+
+	type spec struct {
+		name         string
+		artifactType string
+		expReferrers int
+	}
+
+	cases := []spec{
+		{
+			name:         "Success/AllReferrers",
+			expReferrers: 1,
+		},
+		{
+			name:         "Success/MatchingArtifactType",
+			artifactType: "application/spdx+json",
+			expReferrers: 1,
+		},
+		{
+			name:         "Success/OtherArtifactType",
+			artifactType: "application/vnd.cyclonedx+json",
+			expReferrers: 0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out.Reset()
+			discover := &DiscoverOptions{
+				Common:       common,
+				Remote:       remote,
+				Subject:      reference,
+				ArtifactType: c.artifactType,
+			}
+			require.NoError(t, discover.Run(context.TODO()))
+			lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+			require.Len(t, lines, c.expReferrers+1)
+			if c.expReferrers != 0 {
+				require.Contains(t, string(lines[1]), "application/spdx+json")
+			}
+		})
+	}
+}
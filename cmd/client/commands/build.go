@@ -32,3 +32,10 @@ func NewBuildCmd(common *options.Common) *cobra.Command {
 
 	return cmd
 }
+
+// BuildResult describes a collection or schema
+// built into the build cache.
+type BuildResult struct {
+	Reference string `json:"reference"`
+	Digest    string `json:"digest"`
+}
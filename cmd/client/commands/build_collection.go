@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
 	"github.com/spf13/cobra"
 
 	"github.com/emporous/emporous-go/api/client/v1alpha1"
@@ -15,6 +16,7 @@ import (
 	"github.com/emporous/emporous-go/content/layout"
 	"github.com/emporous/emporous-go/manager/defaultmanager"
 	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/sbom"
 	"github.com/emporous/emporous-go/util/examples"
 	"github.com/emporous/emporous-go/util/workspace"
 )
@@ -25,7 +27,11 @@ type BuildCollectionOptions struct {
 	*BuildOptions
 	options.Remote
 	options.RemoteAuth
+	options.Progress
+	options.Sigstore
+	options.License
 	NoVerify bool
+	SBOM     string
 	RootDir  string
 	// Dataset Config
 	DSConfig string
@@ -42,6 +48,11 @@ var clientBuildCollectionExamples = []examples.Example{
 		Descriptions:  []string{"Build artifacts with custom annotations."},
 		CommandString: "build collection my-directory localhost:5000/myartifacts:latest --dsconfig dataset-config.yaml",
 	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		Descriptions:  []string{"Build artifacts and attach an SPDX SBOM that is pushed with the collection."},
+		CommandString: "build collection my-directory localhost:5000/myartifacts:latest --sbom spdx-json",
+	},
 }
 
 // NewBuildCollectionCmd creates a new cobra.Command for the build collection subcommand.
@@ -64,9 +75,14 @@ func NewBuildCollectionCmd(buildOpts *BuildOptions) *cobra.Command {
 
 	o.Remote.BindFlags(cmd.Flags())
 	o.RemoteAuth.BindFlags(cmd.Flags())
+	o.Progress.BindFlags(cmd.Flags())
+	o.Sigstore.BindFlags(cmd.Flags())
+	o.Sigstore.BindVerifyFlags(cmd.Flags())
+	o.License.BindFlags(cmd.Flags())
 
 	cmd.Flags().StringVarP(&o.DSConfig, "dsconfig", "d", o.DSConfig, "config path for artifact building and dataset configuration")
 	cmd.Flags().BoolVar(&o.NoVerify, "no-verify", o.NoVerify, "skip schema signature verification")
+	cmd.Flags().StringVar(&o.SBOM, "sbom", o.SBOM, fmt.Sprintf("Attach an SBOM in the format to the collection, one of %v", sbom.Formats))
 
 	return cmd
 }
@@ -84,6 +100,11 @@ func (o *BuildCollectionOptions) Validate() error {
 	if _, err := os.Stat(o.RootDir); err != nil {
 		return fmt.Errorf("workspace directory %q: %v", o.RootDir, err)
 	}
+	if o.SBOM != "" {
+		if _, err := sbom.MediaType(o.SBOM); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -102,24 +123,33 @@ func (o *BuildCollectionOptions) Run(ctx context.Context) error {
 		return err
 	}
 
+	registryConfig, err := o.Remote.RegistryConfiguration()
+	if err != nil {
+		return err
+	}
 	var clientOpts = []orasclient.ClientOption{
 		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithCAFile(o.CAFile),
+		orasclient.WithClientCertificate(o.CertFile, o.KeyFile),
 		orasclient.WithAuthConfigs(o.Configs),
 		orasclient.WithPlainHTTP(o.PlainHTTP),
+		orasclient.WithRetryPolicy(o.Remote.RetryPolicy()),
+		orasclient.WithLogger(o.Logger),
+		orasclient.WithRegistryConfig(registryConfig),
 	}
 
 	if !o.NoVerify {
-		verificationFn := func(ctx context.Context, reference string) error {
-			o.Logger.Debugf("Checking signature of %s", reference)
-			err = verifyCollection(ctx, reference, o.RemoteAuth.Configs, o.Remote)
-			if err != nil {
-				return fmt.Errorf("collection %q: %v", reference, err)
-			}
-			return nil
+		verifier, err := newVerifier(o.Common, o.RemoteAuth.Configs, o.Remote, o.Sigstore)
+		if err != nil {
+			return err
 		}
-		clientOpts = append(clientOpts, orasclient.WithPrePullFunc(verificationFn))
+		clientOpts = append(clientOpts, orasclient.WithPrePullFunc(verifier.Verify))
 	}
 
+	progressOpts, flushProgress := progressClientOptions(o.Common, o.Progress)
+	defer flushProgress()
+	clientOpts = append(clientOpts, progressOpts...)
+
 	client, err := orasclient.NewClient(clientOpts...)
 	if err != nil {
 		return fmt.Errorf("error configuring client: %v", err)
@@ -138,8 +168,33 @@ func (o *BuildCollectionOptions) Run(ctx context.Context) error {
 		}
 	}
 
+	// Check the component licenses before the collection is built and saved.
+	licensePolicy, err := o.License.Policy()
+	if err != nil {
+		return err
+	}
+	if licensePolicy != nil && config.Collection.Components.Name != "" {
+		component := empspec.Component{
+			Name:      config.Collection.Components.Name,
+			Locations: config.Collection.Components.Locations,
+			Licenses:  config.Collection.Components.Licenses,
+		}
+		if err := licensePolicy.CheckComponent(o.Destination, component); err != nil {
+			return err
+		}
+	}
+
 	manager := defaultmanager.New(cache, o.Logger)
 
-	_, err = manager.Build(ctx, space, config, o.Destination, client)
-	return err
+	digest, err := manager.Build(ctx, space, config, o.Destination, client)
+	if err != nil {
+		return err
+	}
+
+	if o.SBOM != "" {
+		if err := attachCachedSBOM(ctx, cache, o.Destination, o.SBOM, o.Logger); err != nil {
+			return err
+		}
+	}
+	return o.PrintResult(BuildResult{Reference: o.Destination, Digest: digest}, nil)
 }
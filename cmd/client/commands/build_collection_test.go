@@ -221,6 +221,30 @@ func TestBuildCollectionRun(t *testing.T) {
 			},
 			expError: fmt.Sprintf("reference %s/test:latest is not a schema address", u.Host),
 		},
+		{
+			name: "Failure/SchemaViolation",
+			opts: &BuildCollectionOptions{
+				BuildOptions: &BuildOptions{
+					Common: &options.Common{
+						IOStreams: genericclioptions.IOStreams{
+							Out:    os.Stdout,
+							In:     os.Stdin,
+							ErrOut: os.Stderr,
+						},
+						Logger: testlogr,
+					},
+					Destination: fmt.Sprintf("%s/client-schema-violation:latest", u.Host),
+				},
+				DSConfig: "./testdata/configs/dataset-config-schema-violation.yaml",
+				RootDir:  "./testdata/multi-level-workspace",
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				NoVerify: true,
+			},
+			expError: fmt.Sprintf("attributes are not valid for schema %s/schema-test:latest: "+
+				"file images/fish.jpg: /test: test is required", u.Host),
+		},
 	}
 
 	for _, c := range cases {
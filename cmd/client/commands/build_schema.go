@@ -12,6 +12,7 @@ import (
 	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
 	"github.com/spf13/cobra"
 
+	"github.com/emporous/emporous-go/api/client/v1alpha1"
 	load "github.com/emporous/emporous-go/config"
 	"github.com/emporous/emporous-go/content/layout"
 	"github.com/emporous/emporous-go/nodes/descriptor"
@@ -99,21 +100,9 @@ func (o *BuildSchemaOptions) Run(ctx context.Context) error {
 		}
 	}()
 
-	var userSchema schema.Loader
-	if config.Schema.SchemaPath != "" {
-		schemaBytes, err := ioutil.ReadFile(config.Schema.SchemaPath)
-		if err != nil {
-			return err
-		}
-		userSchema, err = schema.FromBytes(schemaBytes)
-		if err != nil {
-			return err
-		}
-	} else {
-		userSchema, err = schema.FromTypes(config.Schema.AttributeTypes)
-		if err != nil {
-			return err
-		}
+	userSchema, err := schemaFromConfig(config)
+	if err != nil {
+		return err
 	}
 
 	schemaAnnotations := map[string]string{}
@@ -150,5 +139,18 @@ func (o *BuildSchemaOptions) Run(ctx context.Context) error {
 
 	o.Logger.Infof("Schema %s built with reference name %s\n", desc.Digest, o.Destination)
 
-	return nil
+	return o.PrintResult(BuildResult{Reference: o.Destination, Digest: desc.Digest.String()}, nil)
+}
+
+// schemaFromConfig loads the JSON Schema set in the schema path of the
+// configuration or builds it from the configured attribute types.
+func schemaFromConfig(config v1alpha1.SchemaConfiguration) (schema.Loader, error) {
+	if config.Schema.SchemaPath != "" {
+		schemaBytes, err := ioutil.ReadFile(config.Schema.SchemaPath)
+		if err != nil {
+			return schema.Loader{}, err
+		}
+		return schema.FromBytes(schemaBytes)
+	}
+	return schema.FromProperties(config.Schema.AttributeTypes)
 }
@@ -127,6 +127,23 @@ func TestBuildSchemaRun(t *testing.T) {
 				SchemaConfig: "testdata/configs/schema-config.yaml",
 			},
 		},
+		{
+			name: "Success/Constraints",
+			opts: &BuildSchemaOptions{
+				BuildOptions: &BuildOptions{
+					Destination: fmt.Sprintf("%s/client-constraints-test:latest", u.Host),
+					Common: &options.Common{
+						IOStreams: genericclioptions.IOStreams{
+							Out:    os.Stdout,
+							In:     os.Stdin,
+							ErrOut: os.Stderr,
+						},
+						Logger: testlogr,
+					},
+				},
+				SchemaConfig: "testdata/configs/schema-config-constraints.yaml",
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+)
+
+// CacheOptions describe configuration options that can
+// be set using the cache subcommand.
+type CacheOptions struct {
+	*options.Common
+}
+
+// NewCacheCmd creates a new cobra.Command for the cache subcommand.
+func NewCacheCmd(common *options.Common) *cobra.Command {
+	o := CacheOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "cache",
+		Short:         "Manage the local Emporous collection cache",
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(NewCacheListCmd(&o))
+	cmd.AddCommand(NewCacheRemoveCmd(&o))
+	cmd.AddCommand(NewCachePruneCmd(&o))
+	cmd.AddCommand(NewCacheDiskUsageCmd(&o))
+
+	return cmd
+}
@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// CacheDiskUsageOptions describe configuration options that can
+// be set using the cache du subcommand.
+type CacheDiskUsageOptions struct {
+	*CacheOptions
+}
+
+var clientCacheDiskUsageExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		Descriptions:  []string{"Show cache disk usage by reference."},
+		CommandString: "cache du",
+	},
+}
+
+// NewCacheDiskUsageCmd creates a new cobra.Command for the cache du subcommand.
+func NewCacheDiskUsageCmd(cacheOpts *CacheOptions) *cobra.Command {
+	o := CacheDiskUsageOptions{CacheOptions: cacheOpts}
+
+	cmd := &cobra.Command{
+		Use:           "du",
+		Short:         "Show disk usage of the cache",
+		Example:       examples.FormatExamples(clientCacheDiskUsageExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	return cmd
+}
+
+func (o *CacheDiskUsageOptions) Complete(_ []string) error {
+	return nil
+}
+
+func (o *CacheDiskUsageOptions) Validate() error {
+	return nil
+}
+
+func (o *CacheDiskUsageOptions) Run(ctx context.Context) error {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	idx, err := cache.Index()
+	if err != nil {
+		return err
+	}
+
+	var refs []string
+	for _, desc := range idx.Manifests {
+		ref := desc.Annotations[ocispec.AnnotationRefName]
+		// SBOMs attached to collections are not listed
+		// separately, as they are removed with their collection.
+		if isSBOMReference(ref) {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	result := CacheDiskUsageResult{References: []CacheUsage{}}
+	for _, ref := range refs {
+		descs, err := cache.ResolveAll(ctx, ref)
+		if err != nil {
+			return err
+		}
+		result.References = append(result.References, CacheUsage{Reference: ref, Blobs: len(descs), Size: totalSize(descs)})
+	}
+
+	blobs, err := cache.Blobs(ctx)
+	if err != nil {
+		return err
+	}
+	reclaimable, err := cache.GarbageCollect(ctx, true)
+	if err != nil {
+		return err
+	}
+	result.Total = CacheUsage{Blobs: len(blobs), Size: totalSize(blobs)}
+	result.Reclaimable = CacheUsage{Blobs: len(reclaimable), Size: totalSize(reclaimable)}
+
+	return o.PrintResult(result, func(w io.Writer) error {
+		return formatDiskUsage(w, result)
+	})
+}
+
+// CacheDiskUsageResult describes the disk usage of the cache.
+type CacheDiskUsageResult struct {
+	References  []CacheUsage `json:"references"`
+	Total       CacheUsage   `json:"total"`
+	Reclaimable CacheUsage   `json:"reclaimable"`
+}
+
+// CacheUsage describes the number and size of blobs
+// of a reference or the cache.
+type CacheUsage struct {
+	Reference string `json:"reference,omitempty"`
+	Blobs     int    `json:"blobs"`
+	Size      int64  `json:"size"`
+}
+
+func formatDiskUsage(w io.Writer, result CacheDiskUsageResult) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "Reference\tBlobs\tSize"); err != nil {
+		return err
+	}
+	for _, usage := range result.References {
+		if _, err := fmt.Fprintf(tw, "%s\t%d\t%s\n", usage.Reference, usage.Blobs, humanize.Bytes(uint64(usage.Size))); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(tw, "\nTotal\t%d\t%s\n", result.Total.Blobs, humanize.Bytes(uint64(result.Total.Size))); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(tw, "Reclaimable\t%d\t%s\n", result.Reclaimable.Blobs, humanize.Bytes(uint64(result.Reclaimable.Size))); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
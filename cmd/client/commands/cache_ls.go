@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// CacheListOptions describe configuration options that can
+// be set using the cache ls subcommand.
+type CacheListOptions struct {
+	*CacheOptions
+}
+
+var clientCacheListExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		Descriptions:  []string{"List all references stored in the cache."},
+		CommandString: "cache ls",
+	},
+}
+
+// NewCacheListCmd creates a new cobra.Command for the cache ls subcommand.
+func NewCacheListCmd(cacheOpts *CacheOptions) *cobra.Command {
+	o := CacheListOptions{CacheOptions: cacheOpts}
+
+	cmd := &cobra.Command{
+		Use:           "ls",
+		Short:         "List references stored in the cache",
+		Example:       examples.FormatExamples(clientCacheListExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	return cmd
+}
+
+func (o *CacheListOptions) Complete(_ []string) error {
+	return nil
+}
+
+func (o *CacheListOptions) Validate() error {
+	return nil
+}
+
+func (o *CacheListOptions) Run(ctx context.Context) error {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	idx, err := cache.Index()
+	if err != nil {
+		return err
+	}
+
+	descs := idx.Manifests
+	// Keep the output order deterministic
+	sort.Slice(descs, func(i, j int) bool {
+		return descs[i].Annotations[ocispec.AnnotationRefName] < descs[j].Annotations[ocispec.AnnotationRefName]
+	})
+	results := []CacheReference{}
+	for _, desc := range descs {
+		// SBOMs attached to collections are listed with their collection.
+		if isSBOMReference(desc.Annotations[ocispec.AnnotationRefName]) {
+			continue
+		}
+		results = append(results, CacheReference{
+			Reference: desc.Annotations[ocispec.AnnotationRefName],
+			Digest:    desc.Digest.String(),
+			MediaType: desc.MediaType,
+		})
+	}
+	return o.PrintResult(results, func(w io.Writer) error {
+		return formatReferences(w, results)
+	})
+}
+
+// CacheReference describes a reference stored in the cache.
+type CacheReference struct {
+	Reference string `json:"reference"`
+	Digest    string `json:"digest"`
+	MediaType string `json:"mediaType"`
+}
+
+func formatReferences(w io.Writer, refs []CacheReference) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "Reference\tDigest\tMediaType"); err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\n", ref.Reference, ref.Digest, ref.MediaType); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/sbom"
+)
+
+func TestCacheListRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	require.NoError(t, os.MkdirAll(cacheDir, 0750))
+	ref := "localhost:5001/test:latest"
+	require.NoError(t, prepCache(ref, cacheDir, nil))
+	cache, err := layout.NewWithContext(ctx, cacheDir)
+	require.NoError(t, err)
+	require.NoError(t, attachCachedSBOM(ctx, cache, ref, sbom.FormatSPDXJSON, testlogr))
+
+	out := new(bytes.Buffer)
+	cacheOpts := &CacheOptions{
+		Common: &options.Common{
+			IOStreams: genericclioptions.IOStreams{
+				Out: out,
+			},
+			Logger:   testlogr,
+			CacheDir: cacheDir,
+			Format:   options.FormatJSON,
+		},
+	}
+
+	t.Run("Success/List", func(t *testing.T) {
+		out.Reset()
+		o := CacheListOptions{CacheOptions: cacheOpts}
+		require.NoError(t, o.Run(ctx))
+		var results []CacheReference
+		require.NoError(t, json.Unmarshal(out.Bytes(), &results))
+		require.Len(t, results, 1)
+		require.Equal(t, ref, results[0].Reference)
+	})
+
+	t.Run("Success/DiskUsage", func(t *testing.T) {
+		out.Reset()
+		o := CacheDiskUsageOptions{CacheOptions: cacheOpts}
+		require.NoError(t, o.Run(ctx))
+		var result CacheDiskUsageResult
+		require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+		require.Len(t, result.References, 1)
+		require.Equal(t, ref, result.References[0].Reference)
+		require.Equal(t, 5, result.Total.Blobs)
+	})
+}
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/dustin/go-humanize"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// CachePruneOptions describe configuration options that can
+// be set using the cache prune subcommand.
+type CachePruneOptions struct {
+	*CacheOptions
+	DryRun bool
+}
+
+var clientCachePruneExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		Descriptions:  []string{"Remove all content that is not reachable from a reference."},
+		CommandString: "cache prune",
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		Descriptions:  []string{"Report the content that would be removed."},
+		CommandString: "cache prune --dry-run",
+	},
+}
+
+// NewCachePruneCmd creates a new cobra.Command for the cache prune subcommand.
+func NewCachePruneCmd(cacheOpts *CacheOptions) *cobra.Command {
+	o := CachePruneOptions{CacheOptions: cacheOpts}
+
+	cmd := &cobra.Command{
+		Use:           "prune",
+		Short:         "Remove unreferenced content from the cache",
+		Example:       examples.FormatExamples(clientCachePruneExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun, "Report reclaimable content without removing it")
+
+	return cmd
+}
+
+func (o *CachePruneOptions) Complete(_ []string) error {
+	return nil
+}
+
+func (o *CachePruneOptions) Validate() error {
+	return nil
+}
+
+func (o *CachePruneOptions) Run(ctx context.Context) error {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	removed, err := cache.GarbageCollect(ctx, o.DryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, desc := range removed {
+		o.Logger.Debugf("Unreferenced blob %s (%d bytes)", desc.Digest, desc.Size)
+	}
+
+	result := CachePruneResult{DryRun: o.DryRun, Blobs: len(removed), Size: totalSize(removed)}
+	return o.PrintResult(result, func(w io.Writer) error {
+		size := humanize.Bytes(uint64(result.Size))
+		if o.DryRun {
+			_, err := fmt.Fprintf(w, "Would remove %d blob(s), reclaimable space: %s\n", result.Blobs, size)
+			return err
+		}
+		_, err := fmt.Fprintf(w, "Removed %d blob(s), reclaimed space: %s\n", result.Blobs, size)
+		return err
+	})
+}
+
+// CachePruneResult describes the blobs removed from the cache,
+// or that would be removed for a dry run.
+type CachePruneResult struct {
+	DryRun bool  `json:"dryRun"`
+	Blobs  int   `json:"blobs"`
+	Size   int64 `json:"size"`
+}
+
+// totalSize returns the sum of the descriptor sizes.
+func totalSize(descs []ocispec.Descriptor) int64 {
+	var size int64
+	for _, desc := range descs {
+		size += desc.Size
+	}
+	return size
+}
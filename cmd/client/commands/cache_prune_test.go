@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/log"
+)
+
+func TestCachePruneRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	type spec struct {
+		name      string
+		dryRun    bool
+		untag     bool
+		expOutput string
+		expBlobs  int
+	}
+
+	cases := []spec{
+		{
+			name:      "Success/NothingToPrune",
+			expOutput: "Removed 0 blob(s), reclaimed space: 0 B\n",
+			expBlobs:  3,
+		},
+		{
+			name:      "Success/DryRun",
+			dryRun:    true,
+			untag:     true,
+			expOutput: "Would remove 3 blob(s), reclaimable space: 412 B\n",
+			expBlobs:  3,
+		},
+		{
+			name:      "Success/UntaggedReference",
+			untag:     true,
+			expOutput: "Removed 3 blob(s), reclaimed space: 412 B\n",
+			expBlobs:  0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.TODO()
+			cacheDir := filepath.Join(t.TempDir(), "cache")
+			require.NoError(t, os.MkdirAll(cacheDir, 0750))
+			ref := "localhost:5001/test:latest"
+			require.NoError(t, prepCache(ref, cacheDir, nil))
+
+			if c.untag {
+				cache, err := layout.NewWithContext(ctx, cacheDir)
+				require.NoError(t, err)
+				require.NoError(t, cache.Untag(ctx, ref))
+			}
+
+			out := new(bytes.Buffer)
+			o := CachePruneOptions{
+				CacheOptions: &CacheOptions{
+					Common: &options.Common{
+						IOStreams: genericclioptions.IOStreams{
+							Out: out,
+						},
+						Logger:   testlogr,
+						CacheDir: cacheDir,
+					},
+				},
+				DryRun: c.dryRun,
+			}
+			require.NoError(t, o.Run(ctx))
+			require.Equal(t, c.expOutput, out.String())
+
+			cache, err := layout.NewWithContext(ctx, cacheDir)
+			require.NoError(t, err)
+			blobs, err := cache.Blobs(ctx)
+			require.NoError(t, err)
+			require.Len(t, blobs, c.expBlobs)
+		})
+	}
+}
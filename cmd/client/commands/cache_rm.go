@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/dustin/go-humanize"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// CacheRemoveOptions describe configuration options that can
+// be set using the cache rm subcommand.
+type CacheRemoveOptions struct {
+	*CacheOptions
+	References []string
+	Prune      bool
+}
+
+var clientCacheRemoveExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		Descriptions:  []string{"Remove a reference from the cache."},
+		CommandString: "cache rm localhost:5001/test:latest",
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		Descriptions:  []string{"Remove a reference from the cache and prune unreferenced content."},
+		CommandString: "cache rm localhost:5001/test:latest --prune",
+	},
+}
+
+// NewCacheRemoveCmd creates a new cobra.Command for the cache rm subcommand.
+func NewCacheRemoveCmd(cacheOpts *CacheOptions) *cobra.Command {
+	o := CacheRemoveOptions{CacheOptions: cacheOpts}
+
+	cmd := &cobra.Command{
+		Use:           "rm REF...",
+		Short:         "Remove references from the cache",
+		Example:       examples.FormatExamples(clientCacheRemoveExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.Prune, "prune", o.Prune, "Remove content that is no longer referenced after removal")
+
+	return cmd
+}
+
+func (o *CacheRemoveOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting at least one argument")
+	}
+	o.References = args
+	return nil
+}
+
+func (o *CacheRemoveOptions) Validate() error {
+	return nil
+}
+
+func (o *CacheRemoveOptions) Run(ctx context.Context) error {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	for _, ref := range o.References {
+		desc, err := cache.Resolve(ctx, ref)
+		if err != nil {
+			return err
+		}
+		if err := cache.Untag(ctx, ref); err != nil {
+			return err
+		}
+		o.Logger.Infof("Removed reference %s", ref)
+		if err := untagSBOM(ctx, cache, ref, desc.Digest); err != nil {
+			return err
+		}
+	}
+
+	result := CacheRemoveResult{References: o.References}
+	if o.Prune {
+		removed, err := cache.GarbageCollect(ctx, false)
+		if err != nil {
+			return err
+		}
+		o.Logger.Infof("Removed %d blob(s), reclaimed %s", len(removed), humanize.Bytes(uint64(totalSize(removed))))
+		result.Blobs = len(removed)
+		result.Size = totalSize(removed)
+	}
+	return o.PrintResult(result, nil)
+}
+
+// untagSBOM removes the tag of the SBOM attached to the collection with the
+// digest, unless the collection is still tagged in the repository of the reference.
+func untagSBOM(ctx context.Context, cache *layout.Layout, reference string, dgst digest.Digest) error {
+	sbomRef, err := sbomReference(reference, dgst)
+	if err != nil {
+		return err
+	}
+	if _, err := cache.Resolve(ctx, sbomRef); err != nil {
+		return nil
+	}
+
+	idx, err := cache.Index()
+	if err != nil {
+		return err
+	}
+	for _, desc := range idx.Manifests {
+		if desc.Digest != dgst {
+			continue
+		}
+		if ref, err := sbomReference(desc.Annotations[ocispec.AnnotationRefName], dgst); err == nil && ref == sbomRef {
+			return nil
+		}
+	}
+	return cache.Untag(ctx, sbomRef)
+}
+
+// CacheRemoveResult describes the references removed from
+// the cache and the blobs removed when pruning.
+type CacheRemoveResult struct {
+	References []string `json:"references"`
+	Blobs      int      `json:"blobs"`
+	Size       int64    `json:"size"`
+}
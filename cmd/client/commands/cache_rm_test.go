@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/sbom"
+)
+
+func TestCacheRemoveRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	type spec struct {
+		name     string
+		tags     []string
+		expRefs  []string
+		expBlobs int
+	}
+
+	cases := []spec{
+		{
+			name:     "Success/RemoveSBOM",
+			expBlobs: 0,
+		},
+		{
+			name:     "Success/KeepSBOMOfTaggedCollection",
+			tags:     []string{"localhost:5001/test:v1"},
+			expRefs:  []string{"localhost:5001/test:v1"},
+			expBlobs: 5,
+		},
+		{
+			name:     "Success/RemoveSBOMOfOtherRepository",
+			tags:     []string{"localhost:5001/other:latest"},
+			expRefs:  []string{"localhost:5001/other:latest"},
+			expBlobs: 3,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.TODO()
+			cacheDir := filepath.Join(t.TempDir(), "cache")
+			require.NoError(t, os.MkdirAll(cacheDir, 0750))
+			ref := "localhost:5001/test:latest"
+			require.NoError(t, prepCache(ref, cacheDir, nil))
+
+			cache, err := layout.NewWithContext(ctx, cacheDir)
+			require.NoError(t, err)
+			require.NoError(t, attachCachedSBOM(ctx, cache, ref, sbom.FormatSPDXJSON, testlogr))
+			desc, err := cache.Resolve(ctx, ref)
+			require.NoError(t, err)
+			for _, tag := range c.tags {
+				tagged := ocispec.Descriptor{MediaType: desc.MediaType, Digest: desc.Digest, Size: desc.Size}
+				require.NoError(t, cache.Tag(ctx, tagged, tag))
+			}
+
+			out := new(bytes.Buffer)
+			o := CacheRemoveOptions{
+				CacheOptions: &CacheOptions{
+					Common: &options.Common{
+						IOStreams: genericclioptions.IOStreams{
+							Out: out,
+						},
+						Logger:   testlogr,
+						CacheDir: cacheDir,
+						Format:   options.FormatJSON,
+					},
+				},
+				References: []string{ref},
+				Prune:      true,
+			}
+			require.NoError(t, o.Run(ctx))
+
+			var result CacheRemoveResult
+			require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+			require.Equal(t, []string{ref}, result.References)
+
+			cache, err = layout.NewWithContext(ctx, cacheDir)
+			require.NoError(t, err)
+			idx, err := cache.Index()
+			require.NoError(t, err)
+			var refs []string
+			for _, desc := range idx.Manifests {
+				name := desc.Annotations[ocispec.AnnotationRefName]
+				if isSBOMReference(name) {
+					continue
+				}
+				refs = append(refs, name)
+			}
+			require.Equal(t, c.expRefs, refs)
+			blobs, err := cache.Blobs(ctx)
+			require.NoError(t, err)
+			require.Len(t, blobs, c.expBlobs)
+		})
+	}
+}
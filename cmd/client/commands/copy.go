@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"oras.land/oras-go/v2/registry"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/manager/defaultmanager"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// CopyOptions describe configuration options that can
+// be set using the copy subcommand.
+type CopyOptions struct {
+	*options.Common
+	options.Remote
+	options.RemoteAuth
+	options.Progress
+	options.Sigstore
+	Source      string
+	Destination string
+	CopyAll     bool
+	NoVerify    bool
+	Sign        bool
+}
+
+var clientCopyExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "copy localhost:5001/test:latest localhost:5002/test:latest",
+		Descriptions: []string{
+			"Copy collection reference to another registry.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "copy localhost:5001/test:latest localhost:5002/test:latest --copy-all",
+		Descriptions: []string{
+			"Copy collection reference and all linked references to another registry.",
+		},
+	},
+}
+
+// NewCopyCmd creates a new cobra.Command for the copy subcommand.
+func NewCopyCmd(common *options.Common) *cobra.Command {
+	o := CopyOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "copy SRC DST",
+		Short:         "Copy a Emporous collection between registries",
+		Example:       examples.FormatExamples(clientCopyExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+	o.Progress.BindFlags(cmd.Flags())
+	o.Sigstore.BindFlags(cmd.Flags())
+	o.Sigstore.BindSignFlags(cmd.Flags())
+	o.Sigstore.BindVerifyFlags(cmd.Flags())
+
+	cmd.Flags().BoolVar(&o.CopyAll, "copy-all", o.CopyAll, "Copy all linked collections and rewrite the links to the destination")
+	cmd.Flags().BoolVar(&o.NoVerify, "no-verify", o.NoVerify, "Skip collection signature verification")
+	cmd.Flags().BoolVarP(&o.Sign, "sign", "s", o.Sign, "Sign the copied emporous Collection with Sigstore, keyless OIDC signing unless --key is set")
+
+	return cmd
+}
+
+func (o *CopyOptions) Complete(args []string) error {
+	if len(args) < 2 {
+		return errors.New("bug: expecting two arguments")
+	}
+	o.Source = args[0]
+	o.Destination = args[1]
+	return nil
+}
+
+func (o *CopyOptions) Validate() error {
+	if o.Source == o.Destination {
+		return errors.New("source and destination must be different")
+	}
+	if o.NoTlogUpload && o.Key == "" {
+		return errors.New("--no-tlog-upload requires --key")
+	}
+	return nil
+}
+
+func (o *CopyOptions) Run(ctx context.Context) error {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	registryConfig, err := o.Remote.RegistryConfiguration()
+	if err != nil {
+		return err
+	}
+	var clientOpts = []orasclient.ClientOption{
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithCAFile(o.CAFile),
+		orasclient.WithClientCertificate(o.CertFile, o.KeyFile),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+		orasclient.WithRetryPolicy(o.Remote.RetryPolicy()),
+		orasclient.WithLogger(o.Logger),
+		orasclient.WithRegistryConfig(registryConfig),
+	}
+
+	if !o.NoVerify {
+		verifier, err := newVerifier(o.Common, o.RemoteAuth.Configs, o.Remote, o.Sigstore)
+		if err != nil {
+			return err
+		}
+		clientOpts = append(clientOpts, orasclient.WithPrePullFunc(verifier.Verify))
+	}
+
+	progressOpts, flushProgress := progressClientOptions(o.Common, o.Progress)
+	defer flushProgress()
+	clientOpts = append(clientOpts, progressOpts...)
+
+	client, err := orasclient.NewClient(clientOpts...)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	manager := defaultmanager.New(cache, o.Logger)
+	digest, err := manager.Copy(ctx, o.Source, o.Destination, client, o.CopyAll)
+	if err != nil {
+		return err
+	}
+
+	destination := o.Destination
+	if !strings.Contains(destination, "@") {
+		reference, err := registry.ParseReference(o.Destination)
+		if err != nil {
+			return err
+		}
+		destination = fmt.Sprintf("%s/%s@%s", reference.Registry, reference.Repository, digest)
+	}
+
+	if o.Sign {
+		o.Logger.Infof("Signing collection")
+		err = signCollection(ctx, destination, o.RemoteAuth.Configs, o.Remote, o.Sigstore)
+		if err != nil {
+			return err
+		}
+	}
+
+	return o.PrintResult(CopyResult{Source: o.Source, Destination: o.Destination, Digest: digest}, nil)
+}
+
+// CopyResult describes a collection copied
+// between registry locations.
+type CopyResult struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Digest      string `json:"digest"`
+}
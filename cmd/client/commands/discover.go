@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// DiscoverOptions describe configuration options that can
+// be set using the discover subcommand.
+type DiscoverOptions struct {
+	*options.Common
+	options.Remote
+	options.RemoteAuth
+	Subject      string
+	ArtifactType string
+}
+
+var clientDiscoverExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "discover localhost:5001/test:latest",
+		Descriptions: []string{
+			"List the artifacts attached to a collection.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "discover localhost:5001/test:latest --type application/spdx+json",
+		Descriptions: []string{
+			"List the SPDX SBOMs attached to a collection.",
+		},
+	},
+}
+
+// NewDiscoverCmd creates a new cobra.Command for the discover subcommand.
+func NewDiscoverCmd(common *options.Common) *cobra.Command {
+	o := DiscoverOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "discover REF",
+		Short:         "List the artifacts attached to a Emporous collection",
+		Example:       examples.FormatExamples(clientDiscoverExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+
+	cmd.Flags().StringVarP(&o.ArtifactType, "type", "t", o.ArtifactType, "Only list artifacts of the artifact type")
+
+	return cmd
+}
+
+func (o *DiscoverOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting one argument")
+	}
+	o.Subject = args[0]
+	return nil
+}
+
+func (o *DiscoverOptions) Validate() error {
+	return nil
+}
+
+func (o *DiscoverOptions) Run(ctx context.Context) error {
+	registryConfig, err := o.Remote.RegistryConfiguration()
+	if err != nil {
+		return err
+	}
+
+	client, err := orasclient.NewClient(
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithCAFile(o.CAFile),
+		orasclient.WithClientCertificate(o.CertFile, o.KeyFile),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+		orasclient.WithRetryPolicy(o.Remote.RetryPolicy()),
+		orasclient.WithLogger(o.Logger),
+		orasclient.WithRegistryConfig(registryConfig),
+	)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	referrers, err := client.Referrers(ctx, o.Subject, o.ArtifactType)
+	if err != nil {
+		return err
+	}
+
+	// Keep the output order deterministic
+	sort.Slice(referrers, func(i, j int) bool {
+		if referrers[i].ArtifactType != referrers[j].ArtifactType {
+			return referrers[i].ArtifactType < referrers[j].ArtifactType
+		}
+		return referrers[i].Digest < referrers[j].Digest
+	})
+	result := DiscoverResult{Subject: o.Subject, Referrers: []ocispec.Descriptor{}}
+	result.Referrers = append(result.Referrers, referrers...)
+	return o.PrintResult(result, func(w io.Writer) error {
+		return formatReferrers(w, referrers)
+	})
+}
+
+// DiscoverResult describes the referrers of a subject collection.
+type DiscoverResult struct {
+	Subject   string               `json:"subject"`
+	Referrers []ocispec.Descriptor `json:"referrers"`
+}
+
+func formatReferrers(w io.Writer, descs []ocispec.Descriptor) error {
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "Digest\tArtifactType\tMediaType"); err != nil {
+		return err
+	}
+	for _, desc := range descs {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\n", desc.Digest, desc.ArtifactType, desc.MediaType); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -12,7 +13,12 @@ import (
 
 	"github.com/emporous/emporous-go/cmd/client/commands/options"
 	"github.com/emporous/emporous-go/config"
+	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/model/traversal"
+	"github.com/emporous/emporous-go/nodes/collection"
 	"github.com/emporous/emporous-go/nodes/descriptor"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
 	"github.com/emporous/emporous-go/util/examples"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -25,9 +31,12 @@ import (
 // be set when using the inspect subcommand.
 type InspectOptions struct {
 	*options.Common
+	options.Remote
+	options.RemoteAuth
 	Source          string
 	AttributeQuery  string
 	PrintAttributes bool
+	RemoteInspect   bool
 }
 
 var clientInspectExamples = []examples.Example{
@@ -52,6 +61,13 @@ var clientInspectExamples = []examples.Example{
 			"List all descriptors for reference with attribute filtering",
 		},
 	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "inspect --remote --reference localhost:5001/test:latest --attributes attribute-query.yaml",
+		Descriptions: []string{
+			"Preview the descriptors and bytes a filtered pull of a remote reference would fetch",
+		},
+	},
 }
 
 // NewInspectCmd creates a new cobra.Command for the inspect subcommand.
@@ -71,9 +87,13 @@ func NewInspectCmd(common *options.Common) *cobra.Command {
 		},
 	}
 
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+
 	cmd.Flags().StringVarP(&o.AttributeQuery, "attributes", "a", o.AttributeQuery, "Attribute query config path")
 	cmd.Flags().StringVarP(&o.Source, "reference", "r", o.Source, "A reference to list descriptors for")
 	cmd.Flags().BoolVarP(&o.PrintAttributes, "print-attributes", "p", o.PrintAttributes, "print descriptor attributes")
+	cmd.Flags().BoolVar(&o.RemoteInspect, "remote", o.RemoteInspect, "inspect the reference in the remote registry instead of the build cache")
 
 	return cmd
 }
@@ -89,10 +109,17 @@ func (o *InspectOptions) Validate() error {
 	if o.PrintAttributes && o.Source == "" {
 		return fmt.Errorf("must specify a reference with --reference")
 	}
+	if o.RemoteInspect && o.Source == "" {
+		return fmt.Errorf("must specify a reference with --reference")
+	}
 	return nil
 }
 
 func (o *InspectOptions) Run(ctx context.Context) error {
+	if o.RemoteInspect {
+		return o.runRemote(ctx)
+	}
+
 	cache, err := layout.NewWithContext(ctx, o.CacheDir)
 	if err != nil {
 		return err
@@ -103,7 +130,7 @@ func (o *InspectOptions) Run(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
-		return o.formatManifestDescriptors(o.IOStreams.Out, idx.Manifests)
+		return o.printReferences(idx.Manifests)
 	}
 
 	if o.AttributeQuery == "" {
@@ -111,7 +138,7 @@ func (o *InspectOptions) Run(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
-		return o.formatDescriptors(o.IOStreams.Out, descs)
+		return o.printDescriptors(descs, nil)
 	}
 
 	query, err := config.ReadAttributeQuery(o.AttributeQuery)
@@ -121,25 +148,222 @@ func (o *InspectOptions) Run(ctx context.Context) error {
 
 	o.Logger.Debugf("Resolving source %s to descriptor with provided attributes", o.Source)
 
-	matcher := descriptor.JSONSubsetMatcher(query.Attributes)
+	matcher, err := config.ConvertToMatcher(query)
+	if err != nil {
+		return err
+	}
 	descs, err := cache.ResolveByAttribute(ctx, o.Source, matcher)
 	if err != nil {
 		return err
 	}
-	return o.formatDescriptors(o.IOStreams.Out, descs)
+	return o.printDescriptors(descs, nil)
 }
 
-func (o *InspectOptions) formatManifestDescriptors(w io.Writer, descs []ocispec.Descriptor) error {
-	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
-	if _, err := fmt.Fprintf(tw, "Listing all references:\t%s\n", o.Source); err != nil {
+// runRemote lists the descriptors of a remote reference. Only the manifests are
+// fetched to build the collection graph. The number of bytes a pull with the same
+// attribute query would fetch is printed after the descriptors.
+func (o *InspectOptions) runRemote(ctx context.Context) error {
+	var matcher model.Matcher
+	if o.AttributeQuery != "" {
+		query, err := config.ReadAttributeQuery(o.AttributeQuery)
+		if err != nil {
+			return err
+		}
+		matcher, err = config.ConvertToMatcher(query)
+		if err != nil {
+			return err
+		}
+	}
+
+	registryConfig, err := o.Remote.RegistryConfiguration()
+	if err != nil {
+		return err
+	}
+
+	client, err := orasclient.NewClient(
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithCAFile(o.CAFile),
+		orasclient.WithClientCertificate(o.CertFile, o.KeyFile),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+		orasclient.WithRetryPolicy(o.Remote.RetryPolicy()),
+		orasclient.WithLogger(o.Logger),
+		orasclient.WithRegistryConfig(registryConfig),
+	)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	o.Logger.Debugf("Loading collection for remote source %s", o.Source)
+	graph, err := client.LoadCollection(ctx, o.Source)
+	if err != nil {
 		return err
 	}
+
+	descs, err := collectionDescriptors(ctx, graph, matcher)
+	if err != nil {
+		return err
+	}
+
+	// Mirror the filtering of pull to count
+	// the bytes it would fetch.
+	pullable := graph
+	if matcher != nil {
+		var matched bool
+		pullable, matched, err = orasclient.FilterCollection(graph, matcher)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			pullable = collection.Collection{}
+		}
+	}
+	pull := &PullEstimate{}
+	for _, n := range pullable.Nodes() {
+		node, ok := n.(*v2.Node)
+		// Links are not fetched by pull.
+		if !ok || (node.Properties != nil && node.Properties.IsALink()) {
+			continue
+		}
+		pull.Descriptors++
+		pull.Size += node.Descriptor().Size
+	}
+	return o.printDescriptors(descs, pull)
+}
+
+// collectionDescriptors returns the descriptors of the collection that satisfy
+// the matcher, if set, in traversal order starting from the root. Links are skipped
+// because they refer to other collections.
+func collectionDescriptors(ctx context.Context, graph collection.Collection, matcher model.Matcher) ([]ocispec.Descriptor, error) {
+	root, err := graph.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []ocispec.Descriptor
+	tracker := traversal.NewTracker(root, nil)
+	handler := traversal.HandlerFunc(func(ctx context.Context, tracker traversal.Tracker, node model.Node) ([]model.Node, error) {
+		successors := graph.From(node.ID())
+		desc, ok := node.(*v2.Node)
+		if !ok || (desc.Properties != nil && desc.Properties.IsALink()) {
+			return successors, nil
+		}
+		if matcher != nil {
+			match, err := matcher.Matches(node)
+			if err != nil {
+				return nil, err
+			}
+			if !match {
+				return successors, nil
+			}
+		}
+		res = append(res, desc.Descriptor())
+		return successors, nil
+	})
+
+	if err := tracker.Walk(ctx, handler, root); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ReferencesResult lists the references stored in the build cache.
+type ReferencesResult struct {
+	References []string `json:"references"`
+}
+
+// InspectResult describes the descriptors of an inspected reference.
+type InspectResult struct {
+	Reference   string             `json:"reference"`
+	Descriptors []DescriptorResult `json:"descriptors"`
+	// Pull is set when a remote reference is inspected.
+	Pull *PullEstimate `json:"pull,omitempty"`
+}
+
+// DescriptorResult is a descriptor with its file name
+// and parsed attributes.
+type DescriptorResult struct {
+	Name string `json:"name,omitempty"`
+	ocispec.Descriptor
+	Properties *descriptor.Properties `json:"properties,omitempty"`
+}
+
+// PullEstimate describes the content a pull would fetch.
+type PullEstimate struct {
+	Descriptors int   `json:"descriptors"`
+	Size        int64 `json:"size"`
+}
+
+func (o *InspectOptions) printReferences(descs []ocispec.Descriptor) error {
+	result := ReferencesResult{References: []string{}}
 	for _, desc := range descs {
-		if _, err := fmt.Fprintf(tw, "%s\n", desc.Annotations[ocispec.AnnotationRefName]); err != nil {
+		reference := desc.Annotations[ocispec.AnnotationRefName]
+		// SBOMs attached during the build are listed with their collection.
+		if isSBOMReference(reference) {
+			continue
+		}
+		result.References = append(result.References, reference)
+	}
+	return o.PrintResult(result, func(w io.Writer) error {
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		if _, err := fmt.Fprintf(tw, "Listing all references:\t%s\n", o.Source); err != nil {
+			return err
+		}
+		for _, ref := range result.References {
+			if _, err := fmt.Fprintf(tw, "%s\n", ref); err != nil {
+				return err
+			}
+		}
+		return tw.Flush()
+	})
+}
+
+func (o *InspectOptions) printDescriptors(descs []ocispec.Descriptor, pull *PullEstimate) error {
+	result := InspectResult{Reference: o.Source, Descriptors: []DescriptorResult{}, Pull: pull}
+	for _, desc := range descs {
+		props, err := emporousProperties(desc)
+		if err != nil {
 			return err
 		}
+		result.Descriptors = append(result.Descriptors, DescriptorResult{
+			Name:       desc.Annotations[ocispec.AnnotationTitle],
+			Descriptor: desc,
+			Properties: props,
+		})
 	}
-	return tw.Flush()
+	return o.PrintResult(result, func(w io.Writer) error {
+		if err := o.formatDescriptors(w, descs); err != nil {
+			return err
+		}
+		if pull == nil {
+			return nil
+		}
+		_, err := fmt.Fprintf(w, "Pull would fetch %d descriptors (%d bytes)\n", pull.Descriptors, pull.Size)
+		return err
+	})
+}
+
+// emporousProperties parses the Emporous attributes of the descriptor.
+// Nil is returned if the descriptor has no Emporous attributes.
+func emporousProperties(desc ocispec.Descriptor) (*descriptor.Properties, error) {
+	attrDoc, ok := desc.Annotations[empspec.AnnotationEmporousAttributes]
+	if !ok {
+		return nil, nil
+	}
+	var attr map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(attrDoc), &attr); err != nil {
+		return nil, fmt.Errorf("descriptor %s: attributes: %w", desc.Digest, err)
+	}
+	props, err := descriptor.Parse(attr)
+	if err != nil {
+		return nil, fmt.Errorf("descriptor %s: %w", desc.Digest, err)
+	}
+	return props, nil
 }
 
 func (o *InspectOptions) formatDescriptors(w io.Writer, descs []ocispec.Descriptor) error {
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http/httptest"
@@ -45,6 +46,13 @@ func TestInspectValidate(t *testing.T) {
 				Source: "localhost:5001/test:latest",
 			},
 		},
+		{
+			name: "Invalid/RemoteOnly",
+			opts: &InspectOptions{
+				RemoteInspect: true,
+			},
+			expError: "must specify a reference with --reference",
+		},
 		{
 			name: "Invalid/AttributesOnly",
 			opts: &InspectOptions{
@@ -107,6 +115,29 @@ func TestInspectRun(t *testing.T) {
 				"  sha256:03ba204e50d126e4674c005e04d82e84c21366780af1f43bd54a37816b6ab340" +
 				"  13    application/vnd.oci.image.layer.v1.tar\n",
 		},
+		{
+			name: "Success/QueryMatch",
+			opts: &InspectOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    os.Stdout,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger: testlogr,
+				},
+				Source:         fmt.Sprintf("%s/success:latest", u.Host),
+				AttributeQuery: "testdata/configs/query.yaml",
+			},
+			annotations: map[string]string{
+				"test": "annotation",
+			},
+			expRes: "Listing matching descriptors for source:\t" + u.Host + "/success:latest\nName" +
+				"       Digest                    " +
+				"                                               Size  MediaType\nhello.txt" +
+				"  sha256:03ba204e50d126e4674c005e04d82e84c21366780af1f43bd54a37816b6ab340" +
+				"  13    application/vnd.oci.image.layer.v1.tar\n",
+		},
 		{
 			name: "Success/NoAttributesMatch",
 			opts: &InspectOptions{
@@ -165,3 +196,124 @@ func TestInspectRun(t *testing.T) {
 		})
 	}
 }
+
+func TestInspectRunRemote(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(ioutil.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	common := &options.Common{
+		IOStreams: genericclioptions.IOStreams{
+			Out:    os.Stdout,
+			In:     os.Stdin,
+			ErrOut: os.Stderr,
+		},
+		Logger:   testlogr,
+		CacheDir: t.TempDir(),
+	}
+	remote := options.Remote{PlainHTTP: true}
+	reference := fmt.Sprintf("%s/remote:latest", u.Host)
+
+	build := &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{Common: common, Destination: reference},
+		Remote:       remote,
+		RootDir:      "./testdata/flatworkspace",
+		DSConfig:     "./testdata/configs/dataset-config-basic.yaml",
+	}
+	require.NoError(t, build.Run(context.TODO()))
+	push := &PushOptions{Common: common, Remote: remote, Destination: reference}
+	require.NoError(t, push.Run(context.TODO()))
+
+	type spec struct {
+		name     string
+		query    string
+		expDescs []string
+		expPull  string
+	}
+
+	cases := []spec{
+		{
+			name:     "Success/All",
+			expDescs: []string{"None", "None", "fish.jpg"},
+			expPull:  "Pull would fetch 3 descriptors (6500 bytes)",
+		},
+		{
+			name:     "Success/AttributesMatch",
+			query:    "testdata/configs/match-unknown.yaml",
+			expDescs: []string{"fish.jpg"},
+			expPull:  "Pull would fetch 3 descriptors (6500 bytes)",
+		},
+		{
+			name:    "Success/NoAttributesMatch",
+			query:   "testdata/configs/nomatch.yaml",
+			expPull: "Pull would fetch 0 descriptors (0 bytes)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := new(strings.Builder)
+			o := &InspectOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{Out: out},
+					Logger:    testlogr,
+					CacheDir:  t.TempDir(),
+				},
+				Remote:         remote,
+				Source:         reference,
+				AttributeQuery: c.query,
+				RemoteInspect:  true,
+			}
+			require.NoError(t, o.Validate())
+			require.NoError(t, o.Run(context.TODO()))
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			require.Len(t, lines, len(c.expDescs)+3)
+			var names []string
+			for _, line := range lines[2 : len(lines)-1] {
+				names = append(names, strings.Fields(line)[0])
+			}
+			require.ElementsMatch(t, c.expDescs, names)
+			require.Equal(t, c.expPull, lines[len(lines)-1])
+		})
+	}
+
+	t.Run("Success/JSONResult", func(t *testing.T) {
+		out := new(strings.Builder)
+		o := &InspectOptions{
+			Common: &options.Common{
+				IOStreams: genericclioptions.IOStreams{Out: out},
+				Logger:    testlogr,
+				CacheDir:  t.TempDir(),
+				Format:    options.FormatJSON,
+			},
+			Remote:         remote,
+			Source:         reference,
+			AttributeQuery: "testdata/configs/match-unknown.yaml",
+			RemoteInspect:  true,
+		}
+		require.NoError(t, o.Run(context.TODO()))
+
+		var result InspectResult
+		require.NoError(t, json.Unmarshal([]byte(out.String()), &result))
+		require.Equal(t, reference, result.Reference)
+		require.Equal(t, &PullEstimate{Descriptors: 3, Size: 6500}, result.Pull)
+		require.Len(t, result.Descriptors, 1)
+		require.Equal(t, "fish.jpg", result.Descriptors[0].Name)
+		require.Equal(t, "image/jpeg", result.Descriptors[0].MediaType)
+
+		// Properties are only marshaled, so compare the raw JSON.
+		var raw struct {
+			Descriptors []struct {
+				Properties json.RawMessage `json:"properties"`
+			} `json:"descriptors"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out.String()), &raw))
+		require.JSONEq(t, `{"converted":{"org.opencontainers.image.title":"fish.jpg"},"unknown":{"size":2,"test":"testing"}}`,
+			string(raw.Descriptors[0].Properties))
+	})
+}
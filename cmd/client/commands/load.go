@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// LoadOptions describe configuration options that can
+// be set using the load subcommand.
+type LoadOptions struct {
+	*options.Common
+	Input string
+}
+
+var clientLoadExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "load bundle.tar",
+		Descriptions: []string{
+			"Load the collections in an OCI layout archive into the cache.",
+		},
+	},
+}
+
+// NewLoadCmd creates a new cobra.Command for the load subcommand.
+func NewLoadCmd(common *options.Common) *cobra.Command {
+	o := LoadOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "load ARCHIVE",
+		Short:         "Load Emporous collections from an OCI layout archive into the cache",
+		Example:       examples.FormatExamples(clientLoadExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	return cmd
+}
+
+func (o *LoadOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting one argument")
+	}
+	o.Input = args[0]
+	return nil
+}
+
+func (o *LoadOptions) Validate() error {
+	if _, err := os.Stat(o.Input); err != nil {
+		return fmt.Errorf("archive %q: %w", o.Input, err)
+	}
+	return nil
+}
+
+func (o *LoadOptions) Run(ctx context.Context) error {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	archive, err := os.Open(o.Input)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	refs, err := cache.Import(ctx, archive)
+	if err != nil {
+		return fmt.Errorf("error loading archive %s: %w", o.Input, err)
+	}
+
+	for _, ref := range refs {
+		o.Logger.Infof("Loaded reference %s", ref)
+	}
+	result := LoadResult{Input: o.Input, References: []string{}}
+	result.References = append(result.References, refs...)
+	return o.PrintResult(result, nil)
+}
+
+// LoadResult describes the references
+// loaded from an archive.
+type LoadResult struct {
+	Input      string   `json:"input"`
+	References []string `json:"references"`
+}
@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"oras.land/oras-go/v2/registry/remote/auth"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// LoginOptions describe configuration options that can
+// be set using the login subcommand.
+type LoginOptions struct {
+	*options.Common
+	options.Remote
+	options.RemoteAuth
+	Registry      string
+	Username      string
+	Password      string
+	PasswordStdin bool
+}
+
+var clientLoginExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "login localhost:5001 -u myuser",
+		Descriptions: []string{
+			"Log in to a registry and prompt for the password.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "login localhost:5001 -u myuser --password-stdin < password.txt",
+		Descriptions: []string{
+			"Log in to a registry with the password read from stdin.",
+		},
+	},
+}
+
+// NewLoginCmd creates a new cobra.Command for the login subcommand.
+func NewLoginCmd(common *options.Common) *cobra.Command {
+	o := LoginOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "login REGISTRY",
+		Short:         "Log in to a registry",
+		Long:          "Log in to a registry and store the credentials in the registry credentials file or the configured credential helper",
+		Example:       examples.FormatExamples(clientLoginExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+
+	cmd.Flags().StringVarP(&o.Username, "username", "u", o.Username, "Registry username")
+	cmd.Flags().StringVarP(&o.Password, "password", "p", o.Password, "Registry password or identity token")
+	cmd.Flags().BoolVar(&o.PasswordStdin, "password-stdin", o.PasswordStdin, "Read the password or identity token from stdin")
+
+	return cmd
+}
+
+func (o *LoginOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting one argument")
+	}
+	o.Registry = registryHost(args[0])
+	return nil
+}
+
+func (o *LoginOptions) Validate() error {
+	if o.PasswordStdin && o.Password != "" {
+		return errors.New("--password and --password-stdin are mutually exclusive")
+	}
+	if o.PasswordStdin && o.Username == "" {
+		return errors.New("--username is required with --password-stdin")
+	}
+	return nil
+}
+
+func (o *LoginOptions) Run(ctx context.Context) error {
+	if err := o.readCredentials(); err != nil {
+		return err
+	}
+
+	registryConfig, err := o.Remote.RegistryConfiguration()
+	if err != nil {
+		return err
+	}
+
+	cred := auth.Credential{
+		Username: o.Username,
+		Password: o.Password,
+	}
+	if o.Username == "" {
+		// Without a username the password is
+		// used as an identity token.
+		cred = auth.Credential{RefreshToken: o.Password}
+	}
+
+	err = orasclient.Login(ctx, o.Registry, cred,
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithCAFile(o.CAFile),
+		orasclient.WithClientCertificate(o.CertFile, o.KeyFile),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+		orasclient.WithRetryPolicy(o.Remote.RetryPolicy()),
+		orasclient.WithLogger(o.Logger),
+		orasclient.WithRegistryConfig(registryConfig),
+	)
+	if err != nil {
+		return err
+	}
+	o.Logger.Infof("Login succeeded")
+	return nil
+}
+
+// readCredentials reads the password from stdin and prompts
+// for any credentials that are not set.
+func (o *LoginOptions) readCredentials() error {
+	if o.PasswordStdin {
+		password, err := io.ReadAll(o.IOStreams.In)
+		if err != nil {
+			return err
+		}
+		o.Password = strings.TrimRight(string(password), "\r\n")
+		return nil
+	}
+	if o.Password != "" {
+		return nil
+	}
+
+	in, ok := o.IOStreams.In.(*os.File)
+	if !ok || !term.IsTerminal(int(in.Fd())) {
+		return errors.New("a password is required, use --password or --password-stdin")
+	}
+	if o.Username == "" {
+		fmt.Fprint(o.IOStreams.Out, "Username: ")
+		username, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil {
+			return err
+		}
+		o.Username = strings.TrimSpace(username)
+	}
+	fmt.Fprint(o.IOStreams.Out, "Password: ")
+	password, err := term.ReadPassword(int(in.Fd()))
+	fmt.Fprintln(o.IOStreams.Out)
+	if err != nil {
+		return err
+	}
+	o.Password = string(password)
+	return nil
+}
+
+// registryHost returns the registry host of a
+// registry address that may include a scheme.
+func registryHost(registry string) string {
+	registry = strings.TrimPrefix(registry, "https://")
+	registry = strings.TrimPrefix(registry, "http://")
+	host, _, _ := strings.Cut(registry, "/")
+	return host
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/log"
+)
+
+func TestLoginValidate(t *testing.T) {
+	type spec struct {
+		name     string
+		opts     *LoginOptions
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/PasswordStdin",
+			opts: &LoginOptions{
+				Username:      "user",
+				PasswordStdin: true,
+			},
+		},
+		{
+			name: "Invalid/PasswordAndPasswordStdin",
+			opts: &LoginOptions{
+				Username:      "user",
+				Password:      "pass",
+				PasswordStdin: true,
+			},
+			expError: "--password and --password-stdin are mutually exclusive",
+		},
+		{
+			name: "Invalid/PasswordStdinWithoutUsername",
+			opts: &LoginOptions{
+				PasswordStdin: true,
+			},
+			expError: "--username is required with --password-stdin",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.opts.Validate()
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoginLogoutRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(io.Discard, "debug")
+	require.NoError(t, err)
+
+	reg := registry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "user" || password != "pass" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	common := &options.Common{
+		IOStreams: genericclioptions.IOStreams{
+			Out:    os.Stdout,
+			In:     strings.NewReader("pass\n"),
+			ErrOut: os.Stderr,
+		},
+		Logger: testlogr,
+	}
+
+	login := &LoginOptions{
+		Common:        common,
+		Remote:        options.Remote{PlainHTTP: true},
+		RemoteAuth:    options.RemoteAuth{Configs: []string{configPath}},
+		Username:      "user",
+		PasswordStdin: true,
+	}
+	require.NoError(t, login.Complete([]string{"http://" + u.Host}))
+	require.NoError(t, login.Run(context.TODO()))
+	config, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.Contains(t, string(config), u.Host)
+
+	logout := &LogoutOptions{
+		Common:     common,
+		RemoteAuth: options.RemoteAuth{Configs: []string{configPath}},
+	}
+	require.NoError(t, logout.Complete([]string{u.Host}))
+	require.NoError(t, logout.Run(context.TODO()))
+	config, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.NotContains(t, string(config), u.Host)
+}
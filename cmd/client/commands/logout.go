@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// LogoutOptions describe configuration options that can
+// be set using the logout subcommand.
+type LogoutOptions struct {
+	*options.Common
+	options.RemoteAuth
+	Registry string
+}
+
+var clientLogoutExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "logout localhost:5001",
+		Descriptions: []string{
+			"Remove the stored credentials for a registry.",
+		},
+	},
+}
+
+// NewLogoutCmd creates a new cobra.Command for the logout subcommand.
+func NewLogoutCmd(common *options.Common) *cobra.Command {
+	o := LogoutOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "logout REGISTRY",
+		Short:         "Log out of a registry",
+		Example:       examples.FormatExamples(clientLogoutExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.RemoteAuth.BindFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *LogoutOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting one argument")
+	}
+	o.Registry = registryHost(args[0])
+	return nil
+}
+
+func (o *LogoutOptions) Validate() error {
+	return nil
+}
+
+func (o *LogoutOptions) Run(_ context.Context) error {
+	if err := orasclient.Logout(o.Registry, orasclient.WithAuthConfigs(o.Configs)); err != nil {
+		return err
+	}
+	o.Logger.Infof("Removed login credentials for %s", o.Registry)
+	return nil
+}
@@ -0,0 +1,281 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/nodes/descriptor"
+	"github.com/emporous/emporous-go/registryclient"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/schema"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// ListOptions describe configuration options that can
+// be set using the ls subcommand.
+type ListOptions struct {
+	*options.Common
+	options.Remote
+	options.RemoteAuth
+	Target          string
+	Last            string
+	Limit           int
+	PrintAttributes bool
+}
+
+var clientListExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "ls localhost:5001/test",
+		Descriptions: []string{
+			"List the tags of a repository.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "ls localhost:5001/test --print-attributes --format json",
+		Descriptions: []string{
+			"List the tags of a repository with the attributes, schema ID, and link count of each collection as JSON.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "ls localhost:5001 --limit 100 --last team/app",
+		Descriptions: []string{
+			"List up to 100 repositories of a registry after team/app.",
+		},
+	},
+}
+
+// NewListCmd creates a new cobra.Command for the ls subcommand.
+func NewListCmd(common *options.Common) *cobra.Command {
+	o := ListOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "ls REPO|REGISTRY",
+		Short:         "List the tags of a repository or the repositories of a registry",
+		Long:          "List the tags of a remote repository or the repositories of a remote registry with the catalog API. Use inspect for the build cache.",
+		Example:       examples.FormatExamples(clientListExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+
+	cmd.Flags().StringVar(&o.Last, "last", o.Last, "List the tags or repositories after this one")
+	cmd.Flags().IntVar(&o.Limit, "limit", o.Limit, "Maximum number of tags or repositories to list, all if zero")
+	cmd.Flags().BoolVarP(&o.PrintAttributes, "print-attributes", "p", o.PrintAttributes, "Print the attributes, schema ID, and link count of the collection of each tag")
+
+	return cmd
+}
+
+func (o *ListOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting one argument")
+	}
+	o.Target = args[0]
+	return nil
+}
+
+func (o *ListOptions) Validate() error {
+	if o.Limit < 0 {
+		return errors.New("limit must not be negative")
+	}
+	if o.PrintAttributes && o.isRegistry() {
+		return errors.New("--print-attributes requires a repository")
+	}
+	return nil
+}
+
+func (o *ListOptions) Run(ctx context.Context) error {
+	registryConfig, err := o.Remote.RegistryConfiguration()
+	if err != nil {
+		return err
+	}
+
+	client, err := orasclient.NewClient(
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithCAFile(o.CAFile),
+		orasclient.WithClientCertificate(o.CertFile, o.KeyFile),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+		orasclient.WithRetryPolicy(o.Remote.RetryPolicy()),
+		orasclient.WithLogger(o.Logger),
+		orasclient.WithRegistryConfig(registryConfig),
+	)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	if o.isRegistry() {
+		repos, err := client.Repositories(ctx, o.Target, o.Last, o.Limit)
+		if err != nil {
+			return err
+		}
+		return o.printNames("Repository", repos)
+	}
+
+	tags, err := client.Tags(ctx, o.Target, o.Last, o.Limit)
+	if err != nil {
+		return err
+	}
+	if !o.PrintAttributes {
+		return o.printNames("Tag", tags)
+	}
+
+	infos := []TagResult{}
+	for _, tag := range tags {
+		info, err := o.tagInfo(ctx, client, tag)
+		if err != nil {
+			return err
+		}
+		infos = append(infos, info)
+	}
+	return o.PrintResult(infos, func(w io.Writer) error {
+		return formatTags(w, infos)
+	})
+}
+
+// isRegistry returns whether the target is a registry
+// rather than a repository.
+func (o *ListOptions) isRegistry() bool {
+	return !strings.Contains(o.Target, "/")
+}
+
+// TagResult describes the collection a tag refers to.
+type TagResult struct {
+	Tag        string          `json:"tag"`
+	Digest     string          `json:"digest"`
+	SchemaID   string          `json:"schemaID,omitempty"`
+	Links      int             `json:"links"`
+	Attributes json.RawMessage `json:"attributes,omitempty"`
+}
+
+// tagInfo fetches the manifest of the tag to read the collection-level attributes,
+// the schema ID, and the number of linked collections. The schema ID of a schema
+// collection is read from its core-schema attributes. For other collections, the
+// schema IDs the file attributes are stored under are listed.
+func (o *ListOptions) tagInfo(ctx context.Context, client registryclient.Remote, tag string) (TagResult, error) {
+	reference := fmt.Sprintf("%s:%s", o.Target, tag)
+	desc, rc, err := client.GetManifest(ctx, reference)
+	if err != nil {
+		return TagResult{}, err
+	}
+	defer rc.Close()
+	var manifest ocispec.Manifest
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		return TagResult{}, fmt.Errorf("manifest %s: %w", reference, err)
+	}
+
+	info := TagResult{Tag: tag, Digest: desc.Digest.String()}
+	if attr, ok := manifest.Annotations[empspec.AnnotationEmporousAttributes]; ok && json.Valid([]byte(attr)) {
+		info.Attributes = json.RawMessage(attr)
+	}
+	if link, ok := manifest.Annotations[empspec.AnnotationLink]; ok {
+		var links []ocispec.Descriptor
+		if err := json.Unmarshal([]byte(link), &links); err != nil {
+			return TagResult{}, fmt.Errorf("manifest %s: links: %w", reference, err)
+		}
+		info.Links = len(links)
+	}
+
+	props, err := properties(manifest.Annotations)
+	if err != nil {
+		return TagResult{}, fmt.Errorf("manifest %s: %w", reference, err)
+	}
+	if props.IsASchema() {
+		info.SchemaID = props.Schema.ID
+		return info, nil
+	}
+	schemaIDs := map[string]struct{}{}
+	for _, layer := range manifest.Layers {
+		props, err := properties(layer.Annotations)
+		if err != nil {
+			return TagResult{}, fmt.Errorf("manifest %s: layer %s: %w", reference, layer.Digest, err)
+		}
+		for id := range props.Others {
+			// Skip attributes that are not stored under a schema.
+			if id != schema.UnknownSchemaID && id != schema.ConvertedSchemaID {
+				schemaIDs[id] = struct{}{}
+			}
+		}
+	}
+	var ids []string
+	for id := range schemaIDs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	info.SchemaID = strings.Join(ids, ",")
+	return info, nil
+}
+
+// properties parses the Emporous attributes in the annotations.
+func properties(annotations map[string]string) (*descriptor.Properties, error) {
+	attr, err := descriptor.AnnotationsToAttributes(annotations)
+	if err != nil {
+		return nil, err
+	}
+	return descriptor.Parse(attr)
+}
+
+func (o *ListOptions) printNames(header string, names []string) error {
+	if names == nil {
+		names = []string{}
+	}
+	return o.PrintResult(names, func(w io.Writer) error {
+		if _, err := fmt.Fprintln(w, header); err != nil {
+			return err
+		}
+		for _, name := range names {
+			if _, err := fmt.Fprintln(w, name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func formatTags(w io.Writer, infos []TagResult) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "Tag\tDigest\tSchema\tLinks\tAttributes"); err != nil {
+		return err
+	}
+	for _, info := range infos {
+		schemaID, attributes := info.SchemaID, string(info.Attributes)
+		if schemaID == "" {
+			schemaID = "None"
+		}
+		if attributes == "" {
+			attributes = "None"
+		}
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", info.Tag, info.Digest, schemaID, info.Links, attributes); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/log"
+)
+
+func TestListValidate(t *testing.T) {
+	type spec struct {
+		name     string
+		opts     *ListOptions
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/Repository",
+			opts: &ListOptions{Target: "localhost:5001/test", PrintAttributes: true},
+		},
+		{
+			name: "Valid/Registry",
+			opts: &ListOptions{Target: "localhost:5001"},
+		},
+		{
+			name:     "Invalid/Limit",
+			opts:     &ListOptions{Target: "localhost:5001/test", Limit: -1},
+			expError: "limit must not be negative",
+		},
+		{
+			name:     "Invalid/RegistryAttributes",
+			opts:     &ListOptions{Target: "localhost:5001", PrintAttributes: true},
+			expError: "--print-attributes requires a repository",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.opts.Validate()
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestListRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(io.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	common := &options.Common{
+		IOStreams: genericclioptions.IOStreams{
+			Out:    out,
+			In:     os.Stdin,
+			ErrOut: os.Stderr,
+		},
+		Logger:   testlogr,
+		CacheDir: t.TempDir(),
+	}
+	remote := options.Remote{PlainHTTP: true}
+	repo := fmt.Sprintf("%s/list", u.Host)
+
+	for tag, config := range map[string]string{
+		"basic":      "./testdata/configs/dataset-config-basic.yaml",
+		"components": "./testdata/configs/dataset-config-components.yaml",
+	} {
+		reference := fmt.Sprintf("%s:%s", repo, tag)
+		build := &BuildCollectionOptions{
+			BuildOptions: &BuildOptions{Common: common, Destination: reference},
+			Remote:       remote,
+			RootDir:      "./testdata/flatworkspace",
+			DSConfig:     config,
+		}
+		require.NoError(t, build.Run(context.TODO()))
+		push := &PushOptions{Common: common, Remote: remote, Destination: reference}
+		require.NoError(t, push.Run(context.TODO()))
+	}
+
+	run := func(t *testing.T, format string, o *ListOptions) {
+		out.Reset()
+		formatCommon := *common
+		formatCommon.Format = format
+		o.Common = &formatCommon
+		o.Remote = remote
+		require.NoError(t, o.Validate())
+		require.NoError(t, o.Run(context.TODO()))
+	}
+
+	t.Run("Success/Tags", func(t *testing.T) {
+		run(t, options.FormatTable, &ListOptions{Target: repo})
+		require.Equal(t, "Tag\nbasic\ncomponents\n", out.String())
+	})
+
+	t.Run("Success/TagsWithAttributes", func(t *testing.T) {
+		run(t, options.FormatJSON, &ListOptions{Target: repo, PrintAttributes: true})
+		var infos []TagResult
+		require.NoError(t, json.Unmarshal(out.Bytes(), &infos))
+		require.Len(t, infos, 2)
+		require.Equal(t, "basic", infos[0].Tag)
+		require.Equal(t, "components", infos[1].Tag)
+		require.Contains(t, string(infos[1].Attributes), "test-app")
+		for _, info := range infos {
+			require.Contains(t, info.Digest, "sha256:")
+			require.Equal(t, 0, info.Links)
+		}
+	})
+
+	t.Run("Success/TagsWithAttributesTable", func(t *testing.T) {
+		run(t, options.FormatTable, &ListOptions{Target: repo, PrintAttributes: true})
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 3)
+		require.Equal(t, []string{"Tag", "Digest", "Schema", "Links", "Attributes"}, strings.Fields(lines[0]))
+		require.True(t, strings.HasPrefix(lines[2], "components"))
+	})
+
+	t.Run("Success/Repositories", func(t *testing.T) {
+		run(t, options.FormatYAML, &ListOptions{Target: u.Host})
+		var repos []string
+		require.NoError(t, yaml.Unmarshal(out.Bytes(), &repos))
+		require.Contains(t, repos, "list")
+	})
+}
@@ -23,6 +23,8 @@ type Common struct {
 	LogLevel  string
 	Logger    log.LoggerWithInterceptor
 	CacheDir  string
+	// Format is the output format of command results.
+	Format string
 	EnvConfig
 }
 
@@ -30,11 +32,17 @@ type Common struct {
 func (o *Common) BindFlags(fs *pflag.FlagSet) {
 	fs.StringVarP(&o.LogLevel, "loglevel", "l", "info",
 		"Log level (debug, info, warn, error, fatal)")
+	fs.StringVar(&o.Format, "format", FormatTable,
+		"Output format of command results (table, json, yaml)")
 }
 
 // Init initializes default values for Common options.
 func (o *Common) Init() error {
-	logger, err := log.NewLogrusLogger(o.IOStreams.Out, o.LogLevel)
+	if err := o.ValidateFormat(); err != nil {
+		return err
+	}
+
+	logger, err := log.NewLogrusLogger(o.LogWriter(), o.LogLevel)
 	if err != nil {
 		return err
 	}
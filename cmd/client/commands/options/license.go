@@ -0,0 +1,40 @@
+package options
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/pflag"
+
+	"github.com/emporous/emporous-go/config"
+	"github.com/emporous/emporous-go/license"
+)
+
+// License describes license policy options that can be set.
+type License struct {
+	LicensePolicy string
+}
+
+// BindFlags binds options from a flag set to License options.
+func (o *License) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.LicensePolicy, "license-policy", o.LicensePolicy, "Path to the license policy of allowed and denied component licenses. "+
+		"Defaults to $XDG_CONFIG_HOME/emporous/license-policy.yaml if it exists.")
+}
+
+// Policy returns the configured license policy. The policy is nil
+// if no path is set and the default policy does not exist.
+func (o *License) Policy() (*license.Policy, error) {
+	policyPath := o.LicensePolicy
+	if policyPath == "" {
+		policyPath = filepath.Join(xdg.ConfigHome, "emporous", "license-policy.yaml")
+		if _, err := os.Stat(policyPath); err != nil {
+			return nil, nil
+		}
+	}
+	policy, err := config.ReadLicensePolicy(policyPath)
+	if err != nil {
+		return nil, err
+	}
+	return license.NewPolicy(policy)
+}
@@ -0,0 +1,66 @@
+package options
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Output formats of command results.
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+)
+
+// Formats are the supported output formats.
+var Formats = []string{FormatTable, FormatJSON, FormatYAML}
+
+// ValidateFormat returns an error if the output format is not supported.
+// An empty format is the table format.
+func (o *Common) ValidateFormat() error {
+	switch o.Format {
+	case "", FormatTable, FormatJSON, FormatYAML:
+		return nil
+	}
+	return fmt.Errorf("unsupported output format %q, must be one of %v", o.Format, Formats)
+}
+
+// Structured returns whether results are written in a machine-readable format.
+func (o *Common) Structured() bool {
+	return o.Format == FormatJSON || o.Format == FormatYAML
+}
+
+// LogWriter returns the writer for logs and progress. When results are written
+// in a machine-readable format, the error stream is used so the output can be parsed.
+func (o *Common) LogWriter() io.Writer {
+	if o.Structured() {
+		return o.IOStreams.ErrOut
+	}
+	return o.IOStreams.Out
+}
+
+// PrintResult writes the result to the output stream in the output format.
+// The table function writes the table format. If it is nil, nothing is
+// written for the table format and the logs describe the result.
+func (o *Common) PrintResult(result interface{}, table func(io.Writer) error) error {
+	switch o.Format {
+	case FormatJSON:
+		enc := json.NewEncoder(o.IOStreams.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	case FormatYAML:
+		data, err := yaml.Marshal(result)
+		if err != nil {
+			return err
+		}
+		_, err = o.IOStreams.Out.Write(data)
+		return err
+	}
+	if table == nil {
+		return nil
+	}
+	return table(o.IOStreams.Out)
+}
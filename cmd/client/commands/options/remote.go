@@ -1,17 +1,67 @@
 package options
 
-import "github.com/spf13/pflag"
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/pflag"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/config"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+)
 
 // Remote describes remote configuration options that can be set.
 type Remote struct {
-	Insecure  bool
-	PlainHTTP bool
+	Insecure        bool
+	PlainHTTP       bool
+	RetryAttempts   int
+	RetryBackoff    time.Duration
+	RetryMaxBackoff time.Duration
+	RegistryConfig  string
+	CAFile          string
+	CertFile        string
+	KeyFile         string
 }
 
 // BindFlags binds options from a flag set to Remote options.
 func (o *Remote) BindFlags(fs *pflag.FlagSet) {
 	fs.BoolVarP(&o.Insecure, "insecure", "", o.Insecure, "Allow connections to registries SSL registry without certs")
 	fs.BoolVarP(&o.PlainHTTP, "plain-http", "", o.PlainHTTP, "Use plain http and not https when contacting registries")
+	fs.StringVar(&o.CAFile, "ca-file", o.CAFile, "Path to a PEM encoded CA bundle to trust in addition to the system CAs when contacting registries")
+	fs.StringVar(&o.CertFile, "cert-file", o.CertFile, "Path to a PEM encoded client certificate for mutual TLS with registries")
+	fs.StringVar(&o.KeyFile, "key-file", o.KeyFile, "Path to the PEM encoded private key of the client certificate")
+
+	defaults := orasclient.DefaultRetryPolicy()
+	fs.IntVar(&o.RetryAttempts, "retry-attempts", defaults.MaxAttempts, "Maximum attempts for registry requests that fail with a transient error")
+	fs.DurationVar(&o.RetryBackoff, "retry-backoff", defaults.MinBackoff, "Wait time before the first retry of a registry request, doubled for each retry")
+	fs.DurationVar(&o.RetryMaxBackoff, "retry-max-backoff", defaults.MaxBackoff, "Maximum wait time between retries of a registry request")
+	fs.StringVar(&o.RegistryConfig, "registry-config", o.RegistryConfig, "Path to the registry mirror configuration. "+
+		"Defaults to $XDG_CONFIG_HOME/emporous/registries.yaml if it exists.")
+}
+
+// RetryPolicy returns the retry policy for registry requests.
+func (o *Remote) RetryPolicy() orasclient.RetryPolicy {
+	policy := orasclient.DefaultRetryPolicy()
+	policy.MaxAttempts = o.RetryAttempts
+	policy.MinBackoff = o.RetryBackoff
+	policy.MaxBackoff = o.RetryMaxBackoff
+	return policy
+}
+
+// RegistryConfiguration returns the configured registry mirrors. The configuration
+// is empty if no path is set and the default configuration does not exist.
+func (o *Remote) RegistryConfiguration() (clientapi.RegistryConfiguration, error) {
+	configPath := o.RegistryConfig
+	if configPath == "" {
+		configPath = filepath.Join(xdg.ConfigHome, "emporous", "registries.yaml")
+		if _, err := os.Stat(configPath); err != nil {
+			return clientapi.RegistryConfiguration{}, nil
+		}
+	}
+	return config.ReadRegistryConfig(configPath)
 }
 
 // RemoteAuth describes remote authentication configuration options that can be set.
@@ -24,3 +74,13 @@ func (o *RemoteAuth) BindFlags(fs *pflag.FlagSet) {
 	fs.StringArrayVarP(&o.Configs, "configs", "c", o.Configs, "Path(s) to your registry credentials. Defaults to well-known "+
 		"auth locations ~/.docker/config.json and $XDG_RUNTIME_DIR/container/auth.json, in respective order.")
 }
+
+// Progress describes copy progress reporting options that can be set.
+type Progress struct {
+	NoProgress bool
+}
+
+// BindFlags binds options from a flag set to Progress options.
+func (o *Progress) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVarP(&o.NoProgress, "no-progress", "", o.NoProgress, "Disable copy progress reporting")
+}
@@ -0,0 +1,105 @@
+package options
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/pflag"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/config"
+)
+
+const (
+	// DefaultRekorURL is the URL of the public Sigstore transparency log.
+	DefaultRekorURL = "https://rekor.sigstore.dev"
+	// DefaultFulcioURL is the URL of the public Sigstore certificate authority.
+	DefaultFulcioURL = "https://fulcio.sigstore.dev"
+	// DefaultOIDCIssuer is the URL of the public Sigstore OIDC issuer.
+	DefaultOIDCIssuer = "https://oauth2.sigstore.dev/auth"
+)
+
+// Sigstore describes collection signing and verification options that can be set.
+type Sigstore struct {
+	Key                string
+	PublicKey          string
+	VerificationPolicy string
+	RekorURL           string
+	FulcioURL          string
+	OIDCIssuer         string
+	NoTlogUpload       bool
+	VerifyOffline      bool
+}
+
+// BindFlags binds the options shared by signing and verification
+// from a flag set to Sigstore options.
+func (o *Sigstore) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.RekorURL, "rekor-url", DefaultRekorURL, "Address of the Rekor transparency log")
+}
+
+// BindSignFlags binds signing options from a flag set to Sigstore options.
+func (o *Sigstore) BindSignFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Key, "key", o.Key, "Path to a private key to sign with instead of keyless OIDC signing. "+
+		"The key password is read from COSIGN_PASSWORD or prompted for.")
+	fs.StringVar(&o.FulcioURL, "fulcio-url", DefaultFulcioURL, "Address of the Fulcio certificate authority for keyless signing")
+	fs.StringVar(&o.OIDCIssuer, "oidc-issuer", DefaultOIDCIssuer, "OIDC issuer for keyless signing")
+	fs.BoolVar(&o.NoTlogUpload, "no-tlog-upload", o.NoTlogUpload, "Do not upload the signature of a key-based signing to the transparency log")
+}
+
+// BindVerifyFlags binds verification options from a flag set to Sigstore options.
+func (o *Sigstore) BindVerifyFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.PublicKey, "public-key", o.PublicKey, "Path to a public key to verify signatures with instead of keyless verification")
+	fs.BoolVar(&o.VerifyOffline, "verify-offline", o.VerifyOffline, "Verify signatures with the transparency log entries bundled "+
+		"with them instead of contacting the transparency log. Signatures without a bundled entry are rejected")
+	fs.StringVar(&o.VerificationPolicy, "verification-policy", o.VerificationPolicy, "Path to the verification policy of trusted signers. "+
+		"Defaults to $XDG_CONFIG_HOME/emporous/verification-policy.yaml if it exists.")
+}
+
+// Policy returns the configured verification policy. If no path is set and the default
+// policy does not exist, the policy trusts the public key if set or any keyless signer.
+// Otherwise only the configured policy is returned, so references that no policy matches
+// are rejected.
+func (o *Sigstore) Policy() (clientapi.VerificationPolicy, error) {
+	policyPath := o.policyPath()
+	if policyPath != "" {
+		return config.ReadVerificationPolicy(policyPath)
+	}
+
+	authority := clientapi.Authority{
+		Name: "keyless",
+		// Any identity issued by the Fulcio certificate authority is trusted.
+		Keyless: &clientapi.KeylessAuthority{IssuerRegExp: ".*"},
+	}
+	if o.PublicKey != "" {
+		authority = clientapi.Authority{Name: o.PublicKey, PublicKey: o.PublicKey}
+	}
+	policy := clientapi.VerificationPolicy{
+		Policies: []clientapi.ReferencePolicy{
+			{
+				Pattern:     "**",
+				Authorities: []clientapi.Authority{authority},
+			},
+		},
+	}
+	return policy, nil
+}
+
+// HasSigners returns whether the trusted signers are configured
+// with a verification policy or a public key.
+func (o *Sigstore) HasSigners() bool {
+	return o.PublicKey != "" || o.policyPath() != ""
+}
+
+// policyPath returns the path of the verification policy or, if not
+// set, the default policy path if it exists.
+func (o *Sigstore) policyPath() string {
+	if o.VerificationPolicy != "" {
+		return o.VerificationPolicy
+	}
+	policyPath := filepath.Join(xdg.ConfigHome, "emporous", "verification-policy.yaml")
+	if _, err := os.Stat(policyPath); err != nil {
+		return ""
+	}
+	return policyPath
+}
@@ -0,0 +1,78 @@
+package options
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/verification"
+)
+
+func TestSigstorePolicy(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "verification-policy.yaml")
+	policyData := []byte(`kind: VerificationPolicy
+apiVersion: client.emporous.io/v1alpha1
+policies:
+  - pattern: registry.example.com/**
+    authorities:
+      - publicKey: release.pub
+`)
+	require.NoError(t, os.WriteFile(policyPath, policyData, 0600))
+
+	type spec struct {
+		name       string
+		opts       Sigstore
+		exp        []clientapi.ReferencePolicy
+		expSigners bool
+	}
+
+	cases := []spec{
+		{
+			name: "Success/DefaultKeyless",
+			opts: Sigstore{},
+			exp: []clientapi.ReferencePolicy{
+				{
+					Pattern: "**",
+					Authorities: []clientapi.Authority{
+						{Name: "keyless", Keyless: &clientapi.KeylessAuthority{IssuerRegExp: ".*"}},
+					},
+				},
+			},
+		},
+		{
+			name: "Success/DefaultPublicKey",
+			opts: Sigstore{PublicKey: "cosign.pub"},
+			exp: []clientapi.ReferencePolicy{
+				{
+					Pattern:     "**",
+					Authorities: []clientapi.Authority{{Name: "cosign.pub", PublicKey: "cosign.pub"}},
+				},
+			},
+			expSigners: true,
+		},
+		{
+			name: "Success/PolicyFile",
+			opts: Sigstore{PublicKey: "cosign.pub", VerificationPolicy: policyPath},
+			exp: []clientapi.ReferencePolicy{
+				{
+					Pattern:     "registry.example.com/**",
+					Authorities: []clientapi.Authority{{PublicKey: "release.pub"}},
+				},
+			},
+			expSigners: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			policy, err := c.opts.Policy()
+			require.NoError(t, err)
+			require.Equal(t, c.exp, policy.Policies)
+			require.NoError(t, verification.Validate(policy))
+			require.Equal(t, c.expSigners, c.opts.HasSigners())
+		})
+	}
+}
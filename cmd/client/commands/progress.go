@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/term"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+)
+
+const (
+	// progressRefresh is the minimum time between
+	// progress bar renders.
+	progressRefresh = 100 * time.Millisecond
+	// progressLogInterval is the minimum time between progress
+	// log lines for a blob when the output is not a terminal.
+	progressLogInterval = 5 * time.Second
+	progressBarWidth    = 30
+	progressLabelWidth  = 24
+)
+
+// progressClientOptions returns the client options to report copy progress to the
+// command output. The returned function renders any pending progress and should
+// be called after the copy operations have completed.
+func progressClientOptions(common *options.Common, progress options.Progress) ([]orasclient.ClientOption, func()) {
+	if progress.NoProgress {
+		return nil, func() {}
+	}
+	writer := newProgressWriter(common.LogWriter(), common.Logger)
+	return []orasclient.ClientOption{orasclient.WithProgress(writer.Update)}, writer.Flush
+}
+
+// progressWriter renders copy progress events as per-blob progress bars
+// when the output is a terminal and as periodic log lines otherwise.
+type progressWriter struct {
+	mu     sync.Mutex
+	out    io.Writer
+	logger log.Logger
+	tty    bool
+	now    func() time.Time
+	// active stores the blobs being copied in the
+	// order they were started.
+	active   []*blobProgress
+	byDigest map[digest.Digest]*blobProgress
+	// finished stores the completed blobs that
+	// have not been rendered to the terminal.
+	finished []*blobProgress
+	lines    int
+	lastDraw time.Time
+}
+
+// blobProgress tracks the copy progress of a single blob.
+type blobProgress struct {
+	desc    ocispec.Descriptor
+	current int64
+	state   orasclient.ProgressEventType
+	start   time.Time
+	lastLog time.Time
+}
+
+// newProgressWriter returns a progressWriter that renders progress bars to out
+// if out is a terminal and logs progress using the logger otherwise.
+func newProgressWriter(out io.Writer, logger log.Logger) *progressWriter {
+	return &progressWriter{
+		out:      out,
+		logger:   logger,
+		tty:      isTerminal(out),
+		now:      time.Now,
+		byDigest: map[digest.Digest]*blobProgress{},
+	}
+}
+
+// Update records a progress event. It is safe for concurrent use
+// and can be used as an orasclient.ProgressFunc.
+func (p *progressWriter) Update(event orasclient.ProgressEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	blob, ok := p.byDigest[event.Descriptor.Digest]
+	if !ok {
+		blob = &blobProgress{desc: event.Descriptor, start: now, lastLog: now}
+		p.byDigest[event.Descriptor.Digest] = blob
+		p.active = append(p.active, blob)
+	}
+	blob.state = event.Type
+	blob.current = event.Bytes
+
+	switch event.Type {
+	case orasclient.ProgressDone, orasclient.ProgressSkipped:
+		p.finish(blob)
+		if !p.tty {
+			p.logFinished(blob, now)
+			return
+		}
+		p.finished = append(p.finished, blob)
+	case orasclient.ProgressTransferring:
+		if !p.tty {
+			if now.Sub(blob.lastLog) >= progressLogInterval {
+				blob.lastLog = now
+				p.logger.Infof("Copying %s: %s/%s (%s/s)", progressLabel(blob.desc), humanize.Bytes(uint64(blob.current)),
+					humanize.Bytes(uint64(blob.desc.Size)), humanize.Bytes(throughput(blob, now)))
+			}
+			return
+		}
+		if now.Sub(p.lastDraw) < progressRefresh {
+			return
+		}
+	default:
+		if !p.tty {
+			return
+		}
+	}
+	p.render(now)
+}
+
+// Flush renders any pending progress. It should be called
+// after the copy operations have completed.
+func (p *progressWriter) Flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.tty {
+		p.render(p.now())
+	}
+}
+
+// finish removes a blob from the active blobs.
+func (p *progressWriter) finish(blob *blobProgress) {
+	delete(p.byDigest, blob.desc.Digest)
+	for i, b := range p.active {
+		if b == blob {
+			p.active = append(p.active[:i], p.active[i+1:]...)
+			break
+		}
+	}
+}
+
+// logFinished logs a completed blob. Blobs with logged progress
+// are logged at the info level to mark completion.
+func (p *progressWriter) logFinished(blob *blobProgress, now time.Time) {
+	logf := p.logger.Debugf
+	if blob.lastLog != blob.start {
+		logf = p.logger.Infof
+	}
+	if blob.state == orasclient.ProgressSkipped {
+		logf("Skipped %s: already exists", progressLabel(blob.desc))
+		return
+	}
+	logf("Copied %s (%s) in %s", progressLabel(blob.desc), humanize.Bytes(uint64(blob.desc.Size)),
+		now.Sub(blob.start).Round(time.Millisecond))
+}
+
+// render redraws the progress bars. Finished blobs are written once above
+// the bars of the active blobs.
+func (p *progressWriter) render(now time.Time) {
+	var sb strings.Builder
+	if p.lines > 0 {
+		// Move the cursor to the start of the active bars
+		// and clear them.
+		fmt.Fprintf(&sb, "\033[%dA\033[J", p.lines)
+	}
+	for _, blob := range p.finished {
+		sb.WriteString(progressLine(blob, now))
+	}
+	p.finished = nil
+	for _, blob := range p.active {
+		sb.WriteString(progressLine(blob, now))
+	}
+	p.lines = len(p.active)
+	p.lastDraw = now
+	// Progress rendering is best effort.
+	_, _ = io.WriteString(p.out, sb.String())
+}
+
+// progressLine formats the progress bar for a blob.
+func progressLine(blob *blobProgress, now time.Time) string {
+	label := progressLabel(blob.desc)
+	switch blob.state {
+	case orasclient.ProgressSkipped:
+		return fmt.Sprintf("%-*s exists\n", progressLabelWidth, label)
+	case orasclient.ProgressDone:
+		return fmt.Sprintf("%-*s done    %s\n", progressLabelWidth, label, humanize.Bytes(uint64(blob.desc.Size)))
+	}
+
+	var ratio float64
+	if blob.desc.Size > 0 {
+		ratio = float64(blob.current) / float64(blob.desc.Size)
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+	filled := int(ratio * progressBarWidth)
+	bar := strings.Repeat("=", filled)
+	if filled < progressBarWidth {
+		bar += ">" + strings.Repeat(" ", progressBarWidth-filled-1)
+	}
+	return fmt.Sprintf("%-*s [%s] %3.0f%% %s/%s %s/s\n", progressLabelWidth, label, bar, ratio*100,
+		humanize.Bytes(uint64(blob.current)), humanize.Bytes(uint64(blob.desc.Size)), humanize.Bytes(throughput(blob, now)))
+}
+
+// progressLabel returns the file name of the blob if it is known
+// or a shortened digest.
+func progressLabel(desc ocispec.Descriptor) string {
+	label := desc.Annotations[ocispec.AnnotationTitle]
+	if label == "" {
+		label = desc.Digest.String()
+		if err := desc.Digest.Validate(); err == nil && len(desc.Digest.Encoded()) > 12 {
+			label = fmt.Sprintf("%s:%s", desc.Digest.Algorithm(), desc.Digest.Encoded()[:12])
+		}
+	}
+	if len(label) > progressLabelWidth {
+		label = "..." + label[len(label)-progressLabelWidth+3:]
+	}
+	return label
+}
+
+// throughput returns the average bytes per second transferred for a blob.
+func throughput(blob *blobProgress, now time.Time) uint64 {
+	elapsed := now.Sub(blob.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return uint64(float64(blob.current) / elapsed)
+}
+
+// isTerminal returns whether the writer is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
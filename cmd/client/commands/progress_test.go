@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+)
+
+func TestProgressWriter(t *testing.T) {
+	desc := ocispec.Descriptor{
+		MediaType:   "text/plain",
+		Digest:      digest.FromString("progress"),
+		Size:        2000,
+		Annotations: map[string]string{ocispec.AnnotationTitle: "hello.txt"},
+	}
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type spec struct {
+		name   string
+		tty    bool
+		events []orasclient.ProgressEvent
+		// elapsed is the time between each event.
+		elapsed   time.Duration
+		expOut    string
+		expLogged []string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/Terminal",
+			tty:  true,
+			events: []orasclient.ProgressEvent{
+				{Type: orasclient.ProgressStarted, Descriptor: desc},
+				{Type: orasclient.ProgressTransferring, Descriptor: desc, Bytes: 1000},
+				{Type: orasclient.ProgressDone, Descriptor: desc, Bytes: 2000},
+			},
+			elapsed: time.Second,
+			expOut: "hello.txt                [>                             ]   0% 0 B/2.0 kB 0 B/s\n" +
+				"\033[1A\033[Jhello.txt                [===============>              ]  50% 1.0 kB/2.0 kB 1.0 kB/s\n" +
+				"\033[1A\033[Jhello.txt                done    2.0 kB\n",
+		},
+		{
+			name: "Success/TerminalThrottled",
+			tty:  true,
+			events: []orasclient.ProgressEvent{
+				{Type: orasclient.ProgressStarted, Descriptor: desc},
+				{Type: orasclient.ProgressTransferring, Descriptor: desc, Bytes: 1000},
+				{Type: orasclient.ProgressSkipped, Descriptor: desc, Bytes: 2000},
+			},
+			elapsed: time.Millisecond,
+			expOut: "hello.txt                [>                             ]   0% 0 B/2.0 kB 0 B/s\n" +
+				"\033[1A\033[Jhello.txt                exists\n",
+		},
+		{
+			name: "Success/Log",
+			events: []orasclient.ProgressEvent{
+				{Type: orasclient.ProgressStarted, Descriptor: desc},
+				{Type: orasclient.ProgressTransferring, Descriptor: desc, Bytes: 1000},
+				{Type: orasclient.ProgressDone, Descriptor: desc, Bytes: 2000},
+			},
+			elapsed: 5 * time.Second,
+			expLogged: []string{
+				`msg="Copying hello.txt: 1.0 kB/2.0 kB (200 B/s)"`,
+				`msg="Copied hello.txt (2.0 kB) in 10s"`,
+			},
+		},
+		{
+			name: "Success/LogShortCopy",
+			events: []orasclient.ProgressEvent{
+				{Type: orasclient.ProgressStarted, Descriptor: desc},
+				{Type: orasclient.ProgressTransferring, Descriptor: desc, Bytes: 1000},
+				{Type: orasclient.ProgressDone, Descriptor: desc, Bytes: 2000},
+			},
+			elapsed: time.Second,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var out, logged bytes.Buffer
+			logger, err := log.NewLogrusLogger(&logged, "info")
+			require.NoError(t, err)
+
+			now := start
+			p := newProgressWriter(&out, logger)
+			p.tty = c.tty
+			p.now = func() time.Time {
+				return now
+			}
+			for _, event := range c.events {
+				p.Update(event)
+				now = now.Add(c.elapsed)
+			}
+			p.Flush()
+
+			require.Equal(t, c.expOut, out.String())
+			if len(c.expLogged) == 0 {
+				require.Empty(t, logged.String())
+			}
+			for _, line := range c.expLogged {
+				require.Contains(t, logged.String(), line)
+			}
+		})
+	}
+}
+
+func TestProgressLabel(t *testing.T) {
+	dgst := digest.FromString("progress")
+	require.Equal(t, "hello.txt", progressLabel(ocispec.Descriptor{
+		Digest:      dgst,
+		Annotations: map[string]string{ocispec.AnnotationTitle: "hello.txt"},
+	}))
+	require.Equal(t, "sha256:"+dgst.Encoded()[:12], progressLabel(ocispec.Descriptor{Digest: dgst}))
+	require.Equal(t, "...ctory/nested/file.txt", progressLabel(ocispec.Descriptor{
+		Digest:      dgst,
+		Annotations: map[string]string{ocispec.AnnotationTitle: "a/very/long/directory/nested/file.txt"},
+	}))
+}
@@ -8,13 +8,13 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
-	"oras.land/oras-go/v2/content/file"
 
 	"github.com/emporous/emporous-go/cmd/client/commands/options"
 	"github.com/emporous/emporous-go/config"
+	"github.com/emporous/emporous-go/content/file"
 	"github.com/emporous/emporous-go/content/layout"
 	"github.com/emporous/emporous-go/manager/defaultmanager"
-	"github.com/emporous/emporous-go/nodes/descriptor"
+	"github.com/emporous/emporous-go/registryclient"
 	"github.com/emporous/emporous-go/registryclient/orasclient"
 	"github.com/emporous/emporous-go/util/examples"
 )
@@ -25,11 +25,17 @@ type PullOptions struct {
 	*options.Common
 	options.Remote
 	options.RemoteAuth
+	options.Progress
+	options.Sigstore
+	options.License
 	Source         string
 	Output         string
 	PullAll        bool
 	AttributeQuery string
 	NoVerify       bool
+	PreserveOwner  bool
+	Offline        bool
+	Concurrency    int
 }
 
 var clientPullExamples = []examples.Example{
@@ -54,11 +60,25 @@ var clientPullExamples = []examples.Example{
 			"Pull all content from reference that satisfies the attribute query.",
 		},
 	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "pull localhost:5001/test:latest --preserve-owner",
+		Descriptions: []string{
+			"Pull collection reference and restore the recorded file ownership.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "pull localhost:5001/test:latest --offline --no-verify",
+		Descriptions: []string{
+			"Pull collection reference from the cache without network access.",
+		},
+	},
 }
 
 // NewPullCmd creates a new cobra.Command for the pull subcommand.
 func NewPullCmd(common *options.Common) *cobra.Command {
-	o := PullOptions{Common: common}
+	o := PullOptions{Common: common, Concurrency: orasclient.DefaultConcurrency}
 
 	cmd := &cobra.Command{
 		Use:           "pull SRC",
@@ -76,11 +96,18 @@ func NewPullCmd(common *options.Common) *cobra.Command {
 
 	o.Remote.BindFlags(cmd.Flags())
 	o.RemoteAuth.BindFlags(cmd.Flags())
+	o.Progress.BindFlags(cmd.Flags())
+	o.Sigstore.BindFlags(cmd.Flags())
+	o.Sigstore.BindVerifyFlags(cmd.Flags())
+	o.License.BindFlags(cmd.Flags())
 
 	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output location for artifacts")
 	cmd.Flags().StringVar(&o.AttributeQuery, "attributes", o.AttributeQuery, "Attribute query config path")
 	cmd.Flags().BoolVar(&o.PullAll, "pull-all", o.PullAll, "Pull all linked collections")
 	cmd.Flags().BoolVar(&o.NoVerify, "no-verify", o.NoVerify, "Skip collection signature verification")
+	cmd.Flags().BoolVar(&o.Offline, "offline", o.Offline, "Resolve the collection from the cache without network access")
+	cmd.Flags().BoolVar(&o.PreserveOwner, "preserve-owner", o.PreserveOwner, "Restore the recorded file ownership (default when running as root)")
+	cmd.Flags().IntVar(&o.Concurrency, "concurrency", o.Concurrency, "Maximum number of blobs to copy concurrently, including the blobs of linked collections")
 
 	return cmd
 }
@@ -97,6 +124,12 @@ func (o *PullOptions) Complete(args []string) error {
 }
 
 func (o *PullOptions) Validate() error {
+	if o.Concurrency < 0 {
+		return errors.New("concurrency must not be negative")
+	}
+	if o.Offline && !o.NoVerify {
+		return errors.New("signature verification requires network access, use --no-verify with --offline")
+	}
 	if _, err := os.Stat(o.Output); err != nil {
 		if err := os.MkdirAll(o.Output, 0750); err != nil {
 			return err
@@ -111,11 +144,22 @@ func (o *PullOptions) Run(ctx context.Context) error {
 		return err
 	}
 
+	registryConfig, err := o.Remote.RegistryConfiguration()
+	if err != nil {
+		return err
+	}
 	var clientOpts = []orasclient.ClientOption{
 		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithCAFile(o.CAFile),
+		orasclient.WithClientCertificate(o.CertFile, o.KeyFile),
 		orasclient.WithAuthConfigs(o.Configs),
 		orasclient.WithPlainHTTP(o.PlainHTTP),
+		orasclient.WithRetryPolicy(o.Remote.RetryPolicy()),
+		orasclient.WithLogger(o.Logger),
+		orasclient.WithRegistryConfig(registryConfig),
 		orasclient.WithCache(cache),
+		orasclient.WithOffline(o.Offline),
+		orasclient.WithConcurrency(o.Concurrency),
 	}
 
 	if o.AttributeQuery != "" {
@@ -123,23 +167,54 @@ func (o *PullOptions) Run(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
-		matcher := descriptor.JSONSubsetMatcher(query.Attributes)
+		matcher, err := config.ConvertToMatcher(query)
+		if err != nil {
+			return err
+		}
 		clientOpts = append(clientOpts, orasclient.WithPullableAttributes(matcher))
 	}
 
+	var client registryclient.Client
+	var prePullFns []func(context.Context, string) error
 	if !o.NoVerify {
-		verificationFn := func(ctx context.Context, reference string) error {
-			o.Logger.Debugf("Checking signature of %s", reference)
-			err = verifyCollection(ctx, reference, o.RemoteAuth.Configs, o.Remote)
+		verifier, err := newVerifier(o.Common, o.RemoteAuth.Configs, o.Remote, o.Sigstore)
+		if err != nil {
+			return err
+		}
+		prePullFns = append(prePullFns, verifier.Verify)
+	}
+
+	licensePolicy, err := o.License.Policy()
+	if err != nil {
+		return err
+	}
+	if licensePolicy != nil {
+		// The collection is loaded with the client before it is copied and
+		// the loaded collection is reused for the copy.
+		prePullFns = append(prePullFns, func(ctx context.Context, reference string) error {
+			co, err := client.LoadCollection(ctx, reference)
 			if err != nil {
-				return fmt.Errorf("collection %q: %v", reference, err)
+				return err
+			}
+			return licensePolicy.CheckCollection(reference, co)
+		})
+	}
+	if len(prePullFns) != 0 {
+		clientOpts = append(clientOpts, orasclient.WithPrePullFunc(func(ctx context.Context, reference string) error {
+			for _, fn := range prePullFns {
+				if err := fn(ctx, reference); err != nil {
+					return err
+				}
 			}
 			return nil
-		}
-		clientOpts = append(clientOpts, orasclient.WithPrePullFunc(verificationFn))
+		}))
 	}
 
-	client, err := orasclient.NewClient(clientOpts...)
+	progressOpts, flushProgress := progressClientOptions(o.Common, o.Progress)
+	defer flushProgress()
+	clientOpts = append(clientOpts, progressOpts...)
+
+	client, err = orasclient.NewClient(clientOpts...)
 	if err != nil {
 		return fmt.Errorf("error configuring client: %v", err)
 	}
@@ -151,11 +226,16 @@ func (o *PullOptions) Run(ctx context.Context) error {
 
 	manager := defaultmanager.New(cache, o.Logger)
 
+	// File permissions are always restored. Ownership can only be
+	// restored with privileges, so it is opt-in for unprivileged users.
+	destination := file.New(o.Output)
+	destination.PreserveOwner = o.PreserveOwner || os.Geteuid() == 0
+
 	var digests []string
 	if !o.PullAll {
-		digests, err = manager.Pull(ctx, o.Source, client, file.New(o.Output))
+		digests, err = manager.Pull(ctx, o.Source, client, destination)
 	} else {
-		digests, err = manager.PullAll(ctx, o.Source, client, file.New(o.Output))
+		digests, err = manager.PullAll(ctx, o.Source, client, destination)
 	}
 	if err != nil {
 		return err
@@ -163,10 +243,27 @@ func (o *PullOptions) Run(ctx context.Context) error {
 
 	if len(digests) == 0 {
 		o.Logger.Infof("No matching collections found for %s", o.Source)
-		return nil
+	} else {
+		o.Logger.Infof("Copied collection(s) to %s", o.Output)
 	}
 
-	o.Logger.Infof("Copied collection(s) to %s", o.Output)
+	result := PullResult{
+		Reference: o.Source,
+		Output:    o.Output,
+		Digests:   []string{},
+		Files:     destination.Files(),
+	}
+	result.Digests = append(result.Digests, digests...)
+	return o.PrintResult(result, nil)
+}
 
-	return nil
+// PullResult describes the content pulled
+// from a collection to the output location.
+type PullResult struct {
+	Reference string `json:"reference"`
+	Output    string `json:"output"`
+	// Digests are the digests of the pulled descriptors.
+	Digests []string `json:"digests"`
+	// Files are the paths of the written files.
+	Files []string `json:"files"`
 }
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -90,6 +91,23 @@ func TestPullValidate(t *testing.T) {
 				Output: filepath.Join(tmp, "fake"),
 			},
 		},
+		{
+			name: "Invalid/OfflineWithVerification",
+			opts: &PullOptions{
+				Output:  "testdata",
+				Offline: true,
+			},
+			expError: "signature verification requires network access, use --no-verify with --offline",
+		},
+		{
+			name: "Invalid/NegativeConcurrency",
+			opts: &PullOptions{
+				Output:      "testdata",
+				NoVerify:    true,
+				Concurrency: -1,
+			},
+			expError: "concurrency must not be negative",
+		},
 	}
 
 	for _, c := range cases {
@@ -276,6 +294,147 @@ func TestPullRun(t *testing.T) {
 	}
 }
 
+func TestPullRunOffline(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(io.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	source := fmt.Sprintf("%s/client-offline:latest", u.Host)
+	prepTestArtifact(t, source)
+	cache := t.TempDir()
+
+	newOpts := func(offline bool) *PullOptions {
+		return &PullOptions{
+			Common: &options.Common{
+				IOStreams: genericclioptions.IOStreams{
+					Out:    os.Stdout,
+					In:     os.Stdin,
+					ErrOut: os.Stderr,
+				},
+				Logger:   testlogr,
+				CacheDir: cache,
+			},
+			Remote: options.Remote{
+				PlainHTTP: true,
+			},
+			Source:   source,
+			Output:   t.TempDir(),
+			PullAll:  true,
+			NoVerify: true,
+			Offline:  offline,
+		}
+	}
+
+	// Populate the cache and stop the registry.
+	require.NoError(t, newOpts(false).Run(context.TODO()))
+	server.Close()
+
+	o := newOpts(true)
+	out := new(bytes.Buffer)
+	o.IOStreams.Out = out
+	o.Format = options.FormatJSON
+	require.NoError(t, o.Run(context.TODO()))
+	var result PullResult
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	require.Equal(t, source, result.Reference)
+	require.NotEmpty(t, result.Digests)
+	var expFiles []string
+	for _, name := range []string{"aggregate.txt", "aggregate2.txt", "hello.txt"} {
+		_, err = os.Stat(filepath.Join(o.Output, name))
+		require.NoError(t, err)
+		expFiles = append(expFiles, filepath.Join(o.Output, name))
+	}
+	require.Equal(t, expFiles, result.Files)
+
+	o = newOpts(true)
+	o.Source = fmt.Sprintf("%s/client-offline:notstored", u.Host)
+	err = o.Run(context.TODO())
+	require.EqualError(t, err, fmt.Sprintf("descriptor for reference %s is not stored", o.Source))
+}
+
+func TestPullRunLicensePolicy(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(io.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	common := &options.Common{
+		IOStreams: genericclioptions.IOStreams{
+			Out:    os.Stdout,
+			In:     os.Stdin,
+			ErrOut: os.Stderr,
+		},
+		Logger:   testlogr,
+		CacheDir: t.TempDir(),
+	}
+	remote := options.Remote{PlainHTTP: true}
+	reference := fmt.Sprintf("%s/client-license:latest", u.Host)
+	expError := fmt.Sprintf("collection %q violates the license policy: "+
+		"fish.jpg (component test-app): license \"Apache-2.0\" is not allowed", reference)
+
+	build := &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{Common: common, Destination: reference},
+		Remote:       remote,
+		RootDir:      "./testdata/flatworkspace",
+		DSConfig:     "./testdata/configs/dataset-config-components.yaml",
+	}
+
+	// The build is blocked by the policy.
+	build.LicensePolicy = "./testdata/configs/license-policy.yaml"
+	require.EqualError(t, build.Run(context.TODO()), expError)
+
+	build.LicensePolicy = ""
+	require.NoError(t, build.Run(context.TODO()))
+	push := &PushOptions{Common: common, Remote: remote, Destination: reference}
+	require.NoError(t, push.Run(context.TODO()))
+
+	type spec struct {
+		name     string
+		policy   string
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/NoPolicy",
+		},
+		{
+			name:     "Failure/DisallowedLicense",
+			policy:   "./testdata/configs/license-policy.yaml",
+			expError: expError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o := &PullOptions{
+				Common:   common,
+				Remote:   remote,
+				License:  options.License{LicensePolicy: c.policy},
+				Source:   reference,
+				Output:   t.TempDir(),
+				NoVerify: true,
+			}
+			err := o.Run(context.TODO())
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+				_, err = os.Stat(filepath.Join(o.Output, "fish.jpg"))
+				require.ErrorIs(t, err, os.ErrNotExist)
+			} else {
+				require.NoError(t, err)
+				_, err = os.Stat(filepath.Join(o.Output, "fish.jpg"))
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 // prepTestArtifact will push a hello.txt artifact into the
 // registry for retrieval. Uses methods from oras-go.
 func prepTestArtifact(t *testing.T, ref string) {
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	godigest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 	"oras.land/oras-go/v2/registry"
 
@@ -24,8 +26,11 @@ type PushOptions struct {
 	*options.Common
 	options.Remote
 	options.RemoteAuth
+	options.Progress
+	options.Sigstore
 	Destination string
 	Sign        bool
+	Concurrency int
 }
 
 var clientPushExamples = examples.Example{
@@ -36,7 +41,7 @@ var clientPushExamples = examples.Example{
 
 // NewPushCmd creates a new cobra.Command for the push subcommand.
 func NewPushCmd(common *options.Common) *cobra.Command {
-	o := PushOptions{Common: common}
+	o := PushOptions{Common: common, Concurrency: orasclient.DefaultConcurrency}
 
 	cmd := &cobra.Command{
 		Use:           "push DST",
@@ -54,8 +59,12 @@ func NewPushCmd(common *options.Common) *cobra.Command {
 
 	o.Remote.BindFlags(cmd.Flags())
 	o.RemoteAuth.BindFlags(cmd.Flags())
+	o.Progress.BindFlags(cmd.Flags())
+	o.Sigstore.BindFlags(cmd.Flags())
+	o.Sigstore.BindSignFlags(cmd.Flags())
 
-	cmd.Flags().BoolVarP(&o.Sign, "sign", "s", o.Sign, "keyless OIDC signing of emporous Collections with Sigstore")
+	cmd.Flags().BoolVarP(&o.Sign, "sign", "s", o.Sign, "Sign emporous Collections with Sigstore, keyless OIDC signing unless --key is set")
+	cmd.Flags().IntVar(&o.Concurrency, "concurrency", o.Concurrency, "Maximum number of blobs to copy concurrently")
 
 	return cmd
 }
@@ -69,6 +78,12 @@ func (o *PushOptions) Complete(args []string) error {
 }
 
 func (o *PushOptions) Validate() error {
+	if o.Concurrency < 0 {
+		return errors.New("concurrency must not be negative")
+	}
+	if o.NoTlogUpload && o.Key == "" {
+		return errors.New("--no-tlog-upload requires --key")
+	}
 	return nil
 }
 
@@ -78,12 +93,27 @@ func (o *PushOptions) Run(ctx context.Context) error {
 		return err
 	}
 
-	client, err := orasclient.NewClient(
+	registryConfig, err := o.Remote.RegistryConfiguration()
+	if err != nil {
+		return err
+	}
+	var clientOpts = []orasclient.ClientOption{
 		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithCAFile(o.CAFile),
+		orasclient.WithClientCertificate(o.CertFile, o.KeyFile),
 		orasclient.WithAuthConfigs(o.Configs),
 		orasclient.WithPlainHTTP(o.PlainHTTP),
-	)
+		orasclient.WithRetryPolicy(o.Remote.RetryPolicy()),
+		orasclient.WithLogger(o.Logger),
+		orasclient.WithRegistryConfig(registryConfig),
+		orasclient.WithConcurrency(o.Concurrency),
+	}
+
+	progressOpts, flushProgress := progressClientOptions(o.Common, o.Progress)
+	defer flushProgress()
+	clientOpts = append(clientOpts, progressOpts...)
 
+	client, err := orasclient.NewClient(clientOpts...)
 	if err != nil {
 		return fmt.Errorf("error configuring client: %v", err)
 	}
@@ -99,6 +129,19 @@ func (o *PushOptions) Run(ctx context.Context) error {
 		return err
 	}
 
+	// Push the SBOM attached to the collection during the build, if any.
+	// It is pushed untagged and discovered through its subject.
+	sbomRef, err := sbomReference(o.Destination, godigest.Digest(digest))
+	if err != nil {
+		return err
+	}
+	if sbomDesc, err := cache.Resolve(ctx, sbomRef); err == nil {
+		o.Logger.Infof("Pushing SBOM")
+		if err := client.PushGraph(ctx, cache, o.Destination, sbomDesc); err != nil {
+			return fmt.Errorf("error publishing SBOM to %s: %v", o.Destination, err)
+		}
+	}
+
 	destination := o.Destination
 	if !strings.Contains(destination, "@") {
 		reference, err := registry.ParseReference(o.Destination)
@@ -110,11 +153,22 @@ func (o *PushOptions) Run(ctx context.Context) error {
 
 	if o.Sign {
 		o.Logger.Infof("Signing collection")
-		err = signCollection(ctx, destination, o.RemoteAuth.Configs, o.Remote)
+		err = signCollection(ctx, destination, o.RemoteAuth.Configs, o.Remote, o.Sigstore)
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	descs, err := cache.ResolveAll(ctx, o.Destination)
+	if err != nil {
+		return err
+	}
+	return o.PrintResult(PushResult{Reference: o.Destination, Digest: digest, Descriptors: descs}, nil)
+}
+
+// PushResult describes a collection pushed to a registry.
+type PushResult struct {
+	Reference   string               `json:"reference"`
+	Digest      string               `json:"digest"`
+	Descriptors []ocispec.Descriptor `json:"descriptors"`
 }
@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http/httptest"
@@ -68,9 +70,10 @@ func TestPushRun(t *testing.T) {
 	require.NoError(t, err)
 
 	type spec struct {
-		name     string
-		opts     *PushOptions
-		expError string
+		name       string
+		opts       *PushOptions
+		assertFunc func(t *testing.T, out []byte)
+		expError   string
 	}
 
 	cases := []spec{
@@ -91,6 +94,31 @@ func TestPushRun(t *testing.T) {
 				Destination: fmt.Sprintf("%s/success:latest", u.Host),
 			},
 		},
+		{
+			name: "Success/JSONResult",
+			opts: &PushOptions{
+				Common: &options.Common{
+					IOStreams: genericclioptions.IOStreams{
+						Out:    os.Stdout,
+						In:     os.Stdin,
+						ErrOut: os.Stderr,
+					},
+					Logger: testlogr,
+					Format: options.FormatJSON,
+				},
+				Remote: options.Remote{
+					PlainHTTP: true,
+				},
+				Destination: fmt.Sprintf("%s/result:latest", u.Host),
+			},
+			assertFunc: func(t *testing.T, out []byte) {
+				var result PushResult
+				require.NoError(t, json.Unmarshal(out, &result))
+				require.Equal(t, fmt.Sprintf("%s/result:latest", u.Host), result.Reference)
+				require.Len(t, result.Descriptors, 3)
+				require.Equal(t, result.Digest, result.Descriptors[0].Digest.String())
+			},
+		},
 		{
 			name: "Failure/NotStored",
 			opts: &PushOptions{
@@ -124,11 +152,17 @@ func TestPushRun(t *testing.T) {
 				require.NoError(t, err)
 			}
 
+			out := new(bytes.Buffer)
+			c.opts.IOStreams.Out = out
+
 			err := c.opts.Run(context.TODO())
 			if c.expError != "" {
 				require.EqualError(t, err, c.expError)
 			} else {
 				require.NoError(t, err)
+				if c.assertFunc != nil {
+					c.assertFunc(t, out.Bytes())
+				}
 			}
 		})
 	}
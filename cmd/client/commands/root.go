@@ -60,9 +60,21 @@ func NewRootCmd() *cobra.Command {
 	o.BindFlags(cmd.PersistentFlags())
 
 	cmd.AddCommand(NewInspectCmd(&o))
+	cmd.AddCommand(NewListCmd(&o))
 	cmd.AddCommand(NewBuildCmd(&o))
 	cmd.AddCommand(NewPushCmd(&o))
 	cmd.AddCommand(NewPullCmd(&o))
+	cmd.AddCommand(NewCopyCmd(&o))
+	cmd.AddCommand(NewAttachCmd(&o))
+	cmd.AddCommand(NewDiscoverCmd(&o))
+	cmd.AddCommand(NewSBOMCmd(&o))
+	cmd.AddCommand(NewSaveCmd(&o))
+	cmd.AddCommand(NewLoadCmd(&o))
+	cmd.AddCommand(NewLoginCmd(&o))
+	cmd.AddCommand(NewLogoutCmd(&o))
+	cmd.AddCommand(NewCacheCmd(&o))
+	cmd.AddCommand(NewSchemaCmd(&o))
+	cmd.AddCommand(NewValidateCmd(&o))
 	cmd.AddCommand(NewServeCmd(&o))
 	cmd.AddCommand(NewVersionCmd(&o))
 
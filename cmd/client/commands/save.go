@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// SaveOptions describe configuration options that can
+// be set using the save subcommand.
+type SaveOptions struct {
+	*options.Common
+	References []string
+	Output     string
+	SaveAll    bool
+}
+
+var clientSaveExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "save localhost:5001/test:latest -o bundle.tar",
+		Descriptions: []string{
+			"Save a collection reference from the cache to an OCI layout archive.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "save localhost:5001/test:latest -o bundle.tar --save-all",
+		Descriptions: []string{
+			"Save a collection reference and all stored linked collections and schemas to an OCI layout archive.",
+		},
+	},
+}
+
+// NewSaveCmd creates a new cobra.Command for the save subcommand.
+func NewSaveCmd(common *options.Common) *cobra.Command {
+	o := SaveOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "save REF...",
+		Short:         "Save Emporous collections from the cache to an OCI layout archive",
+		Example:       examples.FormatExamples(clientSaveExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output location for the archive")
+	cmd.Flags().BoolVar(&o.SaveAll, "save-all", o.SaveAll, "Save all stored linked collections and schemas")
+
+	return cmd
+}
+
+func (o *SaveOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting at least one argument")
+	}
+	o.References = args
+	return nil
+}
+
+func (o *SaveOptions) Validate() error {
+	if o.Output == "" {
+		return errors.New("output location must be set")
+	}
+	return nil
+}
+
+func (o *SaveOptions) Run(ctx context.Context) (err error) {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	archive, err := os.Create(o.Output)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := archive.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+		// Do not leave a partial archive behind.
+		if err != nil {
+			if removeErr := os.Remove(o.Output); removeErr != nil {
+				o.Logger.Errorf(removeErr.Error())
+			}
+		}
+	}()
+
+	if err := cache.Export(ctx, archive, o.SaveAll, o.References...); err != nil {
+		return fmt.Errorf("error saving archive: %w", err)
+	}
+
+	o.Logger.Infof("Saved %d reference(s) to %s", len(o.References), o.Output)
+	return o.PrintResult(SaveResult{Output: o.Output, References: o.References}, nil)
+}
+
+// SaveResult describes the references
+// saved to an archive.
+type SaveResult struct {
+	Output     string   `json:"output"`
+	References []string `json:"references"`
+}
@@ -0,0 +1,235 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+	orascontent "oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/sbom"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// SBOMOptions describe configuration options that can
+// be set using the sbom subcommand.
+type SBOMOptions struct {
+	*options.Common
+	options.Remote
+	options.RemoteAuth
+	Source  string
+	Format  string
+	Output  string
+	Offline bool
+}
+
+var clientSBOMExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "sbom localhost:5001/test:latest",
+		Descriptions: []string{
+			"Write an SPDX SBOM of a collection to stdout.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "sbom localhost:5001/test:latest --format cyclonedx-json --output sbom.cdx.json --offline",
+		Descriptions: []string{
+			"Write a CycloneDX SBOM of a cached collection to a file.",
+		},
+	},
+}
+
+// NewSBOMCmd creates a new cobra.Command for the sbom subcommand.
+func NewSBOMCmd(common *options.Common) *cobra.Command {
+	o := SBOMOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "sbom REF",
+		Short:         "Generate an SBOM from the component attributes of a Emporous collection",
+		Long:          "Generate an SBOM listing each file of a collection with its digest and the component information in its core-descriptor attributes",
+		Example:       examples.FormatExamples(clientSBOMExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+
+	cmd.Flags().StringVarP(&o.Format, "format", "f", sbom.FormatSPDXJSON, fmt.Sprintf("SBOM format, one of %v", sbom.Formats))
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Path to write the SBOM to instead of stdout")
+	cmd.Flags().BoolVar(&o.Offline, "offline", o.Offline, "Resolve the collection from the cache without network access")
+
+	return cmd
+}
+
+func (o *SBOMOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting one argument")
+	}
+	o.Source = args[0]
+	return nil
+}
+
+func (o *SBOMOptions) Validate() error {
+	_, err := sbom.MediaType(o.Format)
+	return err
+}
+
+func (o *SBOMOptions) Run(ctx context.Context) error {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	registryConfig, err := o.Remote.RegistryConfiguration()
+	if err != nil {
+		return err
+	}
+
+	client, err := orasclient.NewClient(
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithCAFile(o.CAFile),
+		orasclient.WithClientCertificate(o.CertFile, o.KeyFile),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+		orasclient.WithRetryPolicy(o.Remote.RetryPolicy()),
+		orasclient.WithLogger(o.Logger),
+		orasclient.WithRegistryConfig(registryConfig),
+		orasclient.WithCache(cache),
+		orasclient.WithOffline(o.Offline),
+	)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	co, err := client.LoadCollection(ctx, o.Source)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = o.IOStreams.Out
+	if o.Output != "" {
+		f, err := os.Create(o.Output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return sbom.Generate(w, o.Format, o.Source, co)
+}
+
+// attachCachedSBOM generates an SBOM in the format for the collection stored in the cache
+// at the reference and stores it in the cache as an artifact whose subject is the collection
+// manifest. The artifact is tagged in the cache with the reference returned by sbomReference
+// so it is pushed along with the collection. The tag is not listed by inspect, cache ls, or
+// cache du, is not pushed, and is removed with the collection by cache rm.
+func attachCachedSBOM(ctx context.Context, cache *layout.Layout, reference, format string, logger log.Logger) error {
+	mediaType, err := sbom.MediaType(format)
+	if err != nil {
+		return err
+	}
+
+	client, err := orasclient.NewClient(
+		orasclient.WithCache(cache),
+		orasclient.WithOffline(true),
+		orasclient.WithLogger(logger),
+	)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			logger.Errorf(err.Error())
+		}
+	}()
+
+	co, err := client.LoadCollection(ctx, reference)
+	if err != nil {
+		return err
+	}
+	doc := new(bytes.Buffer)
+	if err := sbom.Generate(doc, format, reference, co); err != nil {
+		return err
+	}
+
+	subject, err := cache.Resolve(ctx, reference)
+	if err != nil {
+		return err
+	}
+	blob := orascontent.NewDescriptorFromBytes(mediaType, doc.Bytes())
+	blob.Annotations = map[string]string{ocispec.AnnotationTitle: "sbom." + format}
+	if err := cache.Push(ctx, blob, bytes.NewReader(doc.Bytes())); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+		return err
+	}
+	packOpts := orasclient.PackOptions{
+		Subject:           &ocispec.Descriptor{MediaType: subject.MediaType, Digest: subject.Digest, Size: subject.Size},
+		PackImageManifest: true,
+	}
+	desc, err := orasclient.Pack(ctx, cache, mediaType, []ocispec.Descriptor{blob}, packOpts)
+	if err != nil {
+		return err
+	}
+
+	sbomRef, err := sbomReference(reference, subject.Digest)
+	if err != nil {
+		return err
+	}
+	if err := cache.Tag(ctx, desc, sbomRef); err != nil {
+		return err
+	}
+	logger.Infof("SBOM %s attached to %s", desc.Digest, reference)
+	return nil
+}
+
+// sbomReference returns the reference an SBOM attached to the collection
+// manifest with the digest is tagged with in the repository of the collection reference.
+func sbomReference(reference string, dgst digest.Digest) (string, error) {
+	ref, err := registry.ParseReference(reference)
+	if err != nil {
+		return "", err
+	}
+	ref.Reference = fmt.Sprintf("%s-%s.sbom", dgst.Algorithm(), dgst.Encoded())
+	return ref.String(), nil
+}
+
+// isSBOMReference returns whether the reference is the
+// cache tag of an SBOM returned by sbomReference.
+func isSBOMReference(reference string) bool {
+	ref, err := registry.ParseReference(reference)
+	if err != nil {
+		return false
+	}
+	tag := strings.TrimSuffix(ref.Reference, ".sbom")
+	if tag == ref.Reference {
+		return false
+	}
+	alg, encoded, ok := strings.Cut(tag, "-")
+	return ok && digest.NewDigestFromEncoded(digest.Algorithm(alg), encoded).Validate() == nil
+}
@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/sbom"
+)
+
+func TestSBOMValidate(t *testing.T) {
+	type spec struct {
+		name     string
+		opts     *SBOMOptions
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/SPDX",
+			opts: &SBOMOptions{Format: sbom.FormatSPDXJSON},
+		},
+		{
+			name: "Valid/CycloneDX",
+			opts: &SBOMOptions{Format: sbom.FormatCycloneDXJSON},
+		},
+		{
+			name:     "Invalid/Format",
+			opts:     &SBOMOptions{Format: "spdx-tag-value"},
+			expError: "unsupported SBOM format \"spdx-tag-value\", must be one of [spdx-json cyclonedx-json]",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.opts.Validate()
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSBOMRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(io.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	common := &options.Common{
+		IOStreams: genericclioptions.IOStreams{
+			Out:    out,
+			In:     os.Stdin,
+			ErrOut: os.Stderr,
+		},
+		Logger:   testlogr,
+		CacheDir: t.TempDir(),
+	}
+	remote := options.Remote{PlainHTTP: true}
+	reference := fmt.Sprintf("%s/sbom:latest", u.Host)
+
+	// Build a collection with an attached SBOM and push
+	// it with the collection.
+	build := &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{Common: common, Destination: reference},
+		Remote:       remote,
+		RootDir:      "./testdata/flatworkspace",
+		DSConfig:     "./testdata/configs/dataset-config-components.yaml",
+		SBOM:         sbom.FormatSPDXJSON,
+	}
+	require.NoError(t, build.Validate())
+	require.NoError(t, build.Run(context.TODO()))
+	push := &PushOptions{Common: common, Remote: remote, Destination: reference}
+	require.NoError(t, push.Run(context.TODO()))
+
+	discover := &DiscoverOptions{Common: common, Remote: remote, Subject: reference, ArtifactType: sbom.MediaTypeSPDXJSON}
+	require.NoError(t, discover.Run(context.TODO()))
+	require.Len(t, bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n")), 2)
+
+	// The SBOM is not tagged in the registry, other than with the referrers
+	// tag schema of its subject, and is not listed in the cache.
+	out.Reset()
+	jsonCommon := *common
+	jsonCommon.Format = options.FormatJSON
+	list := &ListOptions{Common: &jsonCommon, Remote: remote, Target: fmt.Sprintf("%s/sbom", u.Host)}
+	require.NoError(t, list.Run(context.TODO()))
+	var tags []string
+	require.NoError(t, json.Unmarshal(out.Bytes(), &tags))
+	require.Contains(t, tags, "latest")
+	for _, tag := range tags {
+		require.NotContains(t, tag, ".sbom")
+	}
+
+	out.Reset()
+	inspect := &InspectOptions{Common: &jsonCommon}
+	require.NoError(t, inspect.Run(context.TODO()))
+	var references ReferencesResult
+	require.NoError(t, json.Unmarshal(out.Bytes(), &references))
+	require.Equal(t, []string{reference}, references.References)
+
+	type spec struct {
+		name    string
+		format  string
+		offline bool
+		expKey  string
+	}
+
+	cases := []spec{
+		{
+			name:    "Success/SPDXOffline",
+			format:  sbom.FormatSPDXJSON,
+			offline: true,
+			expKey:  "spdxVersion",
+		},
+		{
+			name:   "Success/CycloneDXRemote",
+			format: sbom.FormatCycloneDXJSON,
+			expKey: "bomFormat",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out.Reset()
+			o := &SBOMOptions{
+				Common:  common,
+				Remote:  remote,
+				Source:  reference,
+				Format:  c.format,
+				Offline: c.offline,
+			}
+			require.NoError(t, o.Run(context.TODO()))
+
+			var doc map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(out.Bytes(), &doc))
+			require.Contains(t, doc, c.expKey)
+			require.Contains(t, out.String(), "test-app")
+			require.Contains(t, out.String(), "fish.jpg")
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	load "github.com/emporous/emporous-go/config"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/registryclient"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/schema"
+)
+
+// SchemaOptions describe configuration options that can
+// be set using the schema subcommand.
+type SchemaOptions struct {
+	*options.Common
+	options.Remote
+	options.RemoteAuth
+	Offline bool
+}
+
+// NewSchemaCmd creates a new cobra.Command for the schema subcommand.
+func NewSchemaCmd(common *options.Common) *cobra.Command {
+	o := SchemaOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:           "schema",
+		Short:         "Compare and check the compatibility of Emporous schemas",
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	o.Remote.BindFlags(cmd.PersistentFlags())
+	o.RemoteAuth.BindFlags(cmd.PersistentFlags())
+	cmd.PersistentFlags().BoolVar(&o.Offline, "offline", o.Offline, "Resolve schema references from the cache without network access")
+
+	cmd.AddCommand(NewSchemaDiffCmd(&o))
+	cmd.AddCommand(NewSchemaCheckCompatCmd(&o))
+
+	return cmd
+}
+
+// loadSchemas loads the schema for each source. A source is either a path to a
+// schema configuration file or a schema reference.
+func (o *SchemaOptions) loadSchemas(ctx context.Context, sources ...string) ([]schema.Loader, error) {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	registryConfig, err := o.Remote.RegistryConfiguration()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := orasclient.NewClient(
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithCAFile(o.CAFile),
+		orasclient.WithClientCertificate(o.CertFile, o.KeyFile),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+		orasclient.WithRetryPolicy(o.Remote.RetryPolicy()),
+		orasclient.WithLogger(o.Logger),
+		orasclient.WithRegistryConfig(registryConfig),
+		orasclient.WithCache(cache),
+		orasclient.WithOffline(o.Offline),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	var loaders []schema.Loader
+	for _, source := range sources {
+		loader, _, err := resolveSchema(ctx, client, source)
+		if err != nil {
+			return nil, err
+		}
+		loaders = append(loaders, loader)
+	}
+	return loaders, nil
+}
+
+// resolveSchema loads the schema and schema ID for a source. A source is either a
+// path to a schema configuration file or a schema reference.
+func resolveSchema(ctx context.Context, client registryclient.Remote, source string) (schema.Loader, string, error) {
+	if info, err := os.Stat(source); err == nil && info.Mode().IsRegular() {
+		config, err := load.ReadSchemaConfig(source)
+		if err != nil {
+			return schema.Loader{}, "", fmt.Errorf("schema configuration %q: %w", source, err)
+		}
+		loader, err := schemaFromConfig(config)
+		if err != nil {
+			return schema.Loader{}, "", fmt.Errorf("schema configuration %q: %w", source, err)
+		}
+		return loader, config.Schema.ID, nil
+	}
+	return fetchSchema(ctx, client, source)
+}
+
+// fetchSchema retrieves the JSON Schema stored in the schema
+// artifact at the reference and the schema ID set in its attributes.
+func fetchSchema(ctx context.Context, client registryclient.Remote, reference string) (schema.Loader, string, error) {
+	_, manifestReader, err := client.GetManifest(ctx, reference)
+	if err != nil {
+		return schema.Loader{}, "", err
+	}
+	defer manifestReader.Close()
+
+	var manifest ocispec.Manifest
+	if err := json.NewDecoder(manifestReader).Decode(&manifest); err != nil {
+		return schema.Loader{}, "", err
+	}
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != empspec.MediaTypeSchemaDescriptor {
+			continue
+		}
+		return fetchSchemaDescriptor(ctx, client, reference, layer)
+	}
+	return schema.Loader{}, "", fmt.Errorf("reference %s is not a schema address", reference)
+}
+
+// fetchSchemaDescriptor retrieves the JSON Schema content of the schema
+// descriptor and the schema ID set in its attributes.
+func fetchSchemaDescriptor(ctx context.Context, client registryclient.Remote, reference string, desc ocispec.Descriptor) (schema.Loader, string, error) {
+	props, err := properties(desc.Annotations)
+	if err != nil {
+		return schema.Loader{}, "", fmt.Errorf("schema %s: %w", reference, err)
+	}
+	var schemaID string
+	if props.IsASchema() {
+		schemaID = props.Schema.ID
+	}
+
+	schemaBytes, err := client.GetContent(ctx, reference, desc)
+	if err != nil {
+		return schema.Loader{}, "", fmt.Errorf("error fetching schema %s: %w", reference, err)
+	}
+	loader, err := schema.FromBytes(schemaBytes)
+	if err != nil {
+		return schema.Loader{}, "", fmt.Errorf("schema %s: %w", reference, err)
+	}
+	return loader, schemaID, nil
+}
+
+func formatChanges(w io.Writer, changes []schema.Change) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "Key\tChange\tBackward\tForward\tDescription"); err != nil {
+		return err
+	}
+	for _, c := range changes {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", c.Key, c.Type, yesNo(c.Backward), yesNo(c.Forward), c.Description); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
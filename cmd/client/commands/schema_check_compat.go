@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/schema"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// SchemaCheckCompatOptions describe configuration options that can
+// be set using the schema check-compat subcommand.
+type SchemaCheckCompatOptions struct {
+	*SchemaOptions
+	Schema        string
+	Published     string
+	Compatibility string
+}
+
+var clientSchemaCheckCompatExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "schema check-compat schema-config.yaml localhost:5001/myschema:v1",
+		Descriptions: []string{
+			"Check that a schema configuration is backward compatible with a published schema.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "schema check-compat localhost:5001/myschema:v2 localhost:5001/myschema:v1 --compatibility full",
+		Descriptions: []string{
+			"Check that a schema is backward and forward compatible with a previous version.",
+		},
+	},
+}
+
+// NewSchemaCheckCompatCmd creates a new cobra.Command for the schema check-compat subcommand.
+func NewSchemaCheckCompatCmd(schemaOpts *SchemaOptions) *cobra.Command {
+	o := SchemaCheckCompatOptions{SchemaOptions: schemaOpts, Compatibility: string(schema.CompatibilityBackward)}
+
+	cmd := &cobra.Command{
+		Use:   "check-compat SCHEMA PUBLISHED",
+		Short: "Check that a schema is compatible with a published schema",
+		Long: "Check that a schema is compatible with a published schema under a compatibility mode and exit with an error " +
+			"if there are breaking changes. Each schema is a schema reference or the path to a schema configuration.",
+		Example:       examples.FormatExamples(clientSchemaCheckCompatExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Compatibility, "compatibility", o.Compatibility,
+		fmt.Sprintf("Compatibility mode to check, options are %v", schema.Compatibilities))
+
+	return cmd
+}
+
+func (o *SchemaCheckCompatOptions) Complete(args []string) error {
+	if len(args) < 2 {
+		return errors.New("bug: expecting two arguments")
+	}
+	o.Schema = args[0]
+	o.Published = args[1]
+	return nil
+}
+
+func (o *SchemaCheckCompatOptions) Validate() error {
+	_, err := schema.ParseCompatibility(o.Compatibility)
+	return err
+}
+
+func (o *SchemaCheckCompatOptions) Run(ctx context.Context) error {
+	mode, err := schema.ParseCompatibility(o.Compatibility)
+	if err != nil {
+		return err
+	}
+
+	loaders, err := o.loadSchemas(ctx, o.Published, o.Schema)
+	if err != nil {
+		return err
+	}
+	changes, err := schema.Diff(loaders[0], loaders[1])
+	if err != nil {
+		return err
+	}
+
+	breaking := schema.Breaking(changes, mode)
+	result := SchemaCheckCompatResult{
+		Schema:        o.Schema,
+		Published:     o.Published,
+		Compatibility: mode,
+		Compatible:    len(breaking) == 0,
+		Breaking:      breaking,
+	}
+	if result.Breaking == nil {
+		result.Breaking = []schema.Change{}
+	}
+	err = o.PrintResult(result, func(w io.Writer) error {
+		if result.Compatible {
+			_, err := fmt.Fprintf(w, "Schema %s is %s compatible with %s\n", o.Schema, mode, o.Published)
+			return err
+		}
+		return formatChanges(w, breaking)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !result.Compatible {
+		return fmt.Errorf("schema %s is not %s compatible with %s: %d breaking change(s)", o.Schema, mode, o.Published, len(breaking))
+	}
+	return nil
+}
+
+// SchemaCheckCompatResult describes the result of a
+// schema compatibility check.
+type SchemaCheckCompatResult struct {
+	Schema        string               `json:"schema"`
+	Published     string               `json:"published"`
+	Compatibility schema.Compatibility `json:"compatibility"`
+	Compatible    bool                 `json:"compatible"`
+	Breaking      []schema.Change      `json:"breaking"`
+}
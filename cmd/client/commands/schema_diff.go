@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/schema"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// SchemaDiffOptions describe configuration options that can
+// be set using the schema diff subcommand.
+type SchemaDiffOptions struct {
+	*SchemaOptions
+	Previous string
+	New      string
+}
+
+var clientSchemaDiffExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "schema diff localhost:5001/myschema:v1 localhost:5001/myschema:v2",
+		Descriptions: []string{
+			"Show the changes between two published schemas.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "schema diff localhost:5001/myschema:v1 schema-config.yaml",
+		Descriptions: []string{
+			"Show the changes between a published schema and a schema configuration.",
+		},
+	},
+}
+
+// NewSchemaDiffCmd creates a new cobra.Command for the schema diff subcommand.
+func NewSchemaDiffCmd(schemaOpts *SchemaOptions) *cobra.Command {
+	o := SchemaDiffOptions{SchemaOptions: schemaOpts}
+
+	cmd := &cobra.Command{
+		Use:           "diff PREVIOUS NEW",
+		Short:         "Show the attribute changes between two schemas",
+		Long:          "Show the attribute changes between two schemas and whether each change is backward and forward compatible. Each schema is a schema reference or the path to a schema configuration.",
+		Example:       examples.FormatExamples(clientSchemaDiffExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	return cmd
+}
+
+func (o *SchemaDiffOptions) Complete(args []string) error {
+	if len(args) < 2 {
+		return errors.New("bug: expecting two arguments")
+	}
+	o.Previous = args[0]
+	o.New = args[1]
+	return nil
+}
+
+func (o *SchemaDiffOptions) Validate() error {
+	return nil
+}
+
+func (o *SchemaDiffOptions) Run(ctx context.Context) error {
+	loaders, err := o.loadSchemas(ctx, o.Previous, o.New)
+	if err != nil {
+		return err
+	}
+	changes, err := schema.Diff(loaders[0], loaders[1])
+	if err != nil {
+		return err
+	}
+
+	result := SchemaDiffResult{Previous: o.Previous, New: o.New, Changes: changes}
+	if result.Changes == nil {
+		result.Changes = []schema.Change{}
+	}
+	return o.PrintResult(result, func(w io.Writer) error {
+		if len(changes) == 0 {
+			_, err := fmt.Fprintln(w, "No changes")
+			return err
+		}
+		return formatChanges(w, changes)
+	})
+}
+
+// SchemaDiffResult describes the changes between two schemas.
+type SchemaDiffResult struct {
+	Previous string          `json:"previous"`
+	New      string          `json:"new"`
+	Changes  []schema.Change `json:"changes"`
+}
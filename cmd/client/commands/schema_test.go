@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/schema"
+)
+
+func TestSchemaCheckCompatValidate(t *testing.T) {
+	type spec struct {
+		name     string
+		opts     *SchemaCheckCompatOptions
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Valid/Backward",
+			opts: &SchemaCheckCompatOptions{Compatibility: "backward"},
+		},
+		{
+			name:     "Invalid/Compatibility",
+			opts:     &SchemaCheckCompatOptions{Compatibility: "transitive"},
+			expError: "unsupported compatibility mode \"transitive\", must be one of [backward forward full none]",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.opts.Validate()
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSchemaRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(io.Discard, "debug")
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	common := &options.Common{
+		IOStreams: genericclioptions.IOStreams{
+			Out:    out,
+			In:     os.Stdin,
+			ErrOut: os.Stderr,
+		},
+		Logger:   testlogr,
+		CacheDir: t.TempDir(),
+	}
+
+	published := "localhost:5001/myschema:v1"
+	build := &BuildSchemaOptions{
+		BuildOptions: &BuildOptions{Common: common, Destination: published},
+		SchemaConfig: "testdata/configs/schema-config.yaml",
+	}
+	require.NoError(t, build.Run(context.TODO()))
+
+	newOpts := func(format string) *SchemaOptions {
+		out.Reset()
+		formatCommon := *common
+		formatCommon.Format = format
+		return &SchemaOptions{Common: &formatCommon, Offline: true}
+	}
+
+	t.Run("Success/Diff", func(t *testing.T) {
+		o := &SchemaDiffOptions{
+			SchemaOptions: newOpts(options.FormatJSON),
+			Previous:      published,
+			New:           "testdata/configs/schema-config-v2.yaml",
+		}
+		require.NoError(t, o.Run(context.TODO()))
+		var result SchemaDiffResult
+		require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+		require.Equal(t, []schema.Change{
+			{
+				Key:         "size",
+				Type:        schema.ChangeAddedOptional,
+				Description: "optional attribute added with type integer",
+				Backward:    false,
+				Forward:     true,
+			},
+		}, result.Changes)
+	})
+
+	t.Run("Success/DiffNoChanges", func(t *testing.T) {
+		o := &SchemaDiffOptions{
+			SchemaOptions: newOpts(options.FormatTable),
+			Previous:      published,
+			New:           "testdata/configs/schema-config.yaml",
+		}
+		require.NoError(t, o.Run(context.TODO()))
+		require.Equal(t, "No changes\n", out.String())
+	})
+
+	t.Run("Success/CheckCompatCompatible", func(t *testing.T) {
+		o := &SchemaCheckCompatOptions{
+			SchemaOptions: newOpts(options.FormatTable),
+			Schema:        "testdata/configs/schema-config-v2.yaml",
+			Published:     published,
+			Compatibility: "forward",
+		}
+		require.NoError(t, o.Run(context.TODO()))
+		require.Equal(t, "Schema testdata/configs/schema-config-v2.yaml is forward compatible with localhost:5001/myschema:v1\n", out.String())
+	})
+
+	t.Run("Failure/CheckCompatBreaking", func(t *testing.T) {
+		o := &SchemaCheckCompatOptions{
+			SchemaOptions: newOpts(options.FormatJSON),
+			Schema:        "testdata/configs/schema-config-constraints.yaml",
+			Published:     published,
+			Compatibility: "backward",
+		}
+		err := o.Run(context.TODO())
+		require.EqualError(t, err, "schema testdata/configs/schema-config-constraints.yaml is not backward compatible "+
+			"with localhost:5001/myschema:v1: 4 breaking change(s)")
+		var result SchemaCheckCompatResult
+		require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+		require.False(t, result.Compatible)
+		var changes []string
+		for _, change := range result.Breaking {
+			changes = append(changes, fmt.Sprintf("%s %s", change.Key, change.Type))
+		}
+		require.Equal(t, []string{"name added-required", "size added-optional", "stage added-required", "version added-required"}, changes)
+	})
+
+	t.Run("Success/CheckCompatNone", func(t *testing.T) {
+		o := &SchemaCheckCompatOptions{
+			SchemaOptions: newOpts(options.FormatTable),
+			Schema:        "testdata/configs/schema-config-constraints.yaml",
+			Published:     published,
+			Compatibility: "none",
+		}
+		require.NoError(t, o.Run(context.TODO()))
+	})
+
+	t.Run("Failure/NotASchema", func(t *testing.T) {
+		collection := "localhost:5001/collection:latest"
+		build := &BuildCollectionOptions{
+			BuildOptions: &BuildOptions{Common: common, Destination: collection},
+			RootDir:      "./testdata/flatworkspace",
+			DSConfig:     "./testdata/configs/dataset-config-basic.yaml",
+		}
+		require.NoError(t, build.Run(context.TODO()))
+		o := &SchemaDiffOptions{
+			SchemaOptions: newOpts(options.FormatTable),
+			Previous:      published,
+			New:           collection,
+		}
+		require.EqualError(t, o.Run(context.TODO()), "reference localhost:5001/collection:latest is not a schema address")
+	})
+}
@@ -14,12 +14,14 @@ import (
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 
+	"github.com/emporous/emporous-go/api/client/v1alpha1"
 	managerapi "github.com/emporous/emporous-go/api/services/collectionmanager/v1alpha1"
 	"github.com/emporous/emporous-go/cmd/client/commands/options"
 	"github.com/emporous/emporous-go/content/layout"
 	"github.com/emporous/emporous-go/manager/defaultmanager"
 	"github.com/emporous/emporous-go/services/collectionmanager"
 	"github.com/emporous/emporous-go/util/examples"
+	"github.com/emporous/emporous-go/verification"
 )
 
 // ServeOptions describe configuration options that can
@@ -28,6 +30,8 @@ type ServeOptions struct {
 	*options.Common
 	SocketLocation string
 	options.Remote
+	options.Sigstore
+	NoVerify bool
 }
 
 var clientServeExamples = examples.Example{
@@ -55,6 +59,11 @@ func NewServeCmd(common *options.Common) *cobra.Command {
 	}
 
 	o.Remote.BindFlags(cmd.Flags())
+	o.Sigstore.BindFlags(cmd.Flags())
+	o.Sigstore.BindVerifyFlags(cmd.Flags())
+
+	cmd.Flags().BoolVar(&o.NoVerify, "no-verify", o.NoVerify, "Skip signature verification of pulled collections when "+
+		"a verification policy or public key is configured")
 
 	return cmd
 }
@@ -83,12 +92,42 @@ func (o *ServeOptions) Run(ctx context.Context) error {
 		return err
 	}
 
+	registryConfig, err := o.Remote.RegistryConfiguration()
+	if err != nil {
+		return err
+	}
+
+	// Retrieved collections are only verified when the trusted
+	// signers are configured.
+	var policy v1alpha1.VerificationPolicy
+	if !o.NoVerify && o.Sigstore.HasSigners() {
+		policy, err = o.Sigstore.Policy()
+		if err != nil {
+			return err
+		}
+		if err := verification.Validate(policy); err != nil {
+			return err
+		}
+	}
+
 	manager := defaultmanager.New(cache, o.Logger)
 
 	opts := collectionmanager.ServiceOptions{
-		Insecure:  o.Insecure,
-		PlainHTTP: o.PlainHTTP,
-		PullCache: cache,
+		Insecure:           o.Insecure,
+		PlainHTTP:          o.PlainHTTP,
+		CAFile:             o.CAFile,
+		CertFile:           o.CertFile,
+		KeyFile:            o.KeyFile,
+		PullCache:          cache,
+		RetryPolicy:        o.Remote.RetryPolicy(),
+		RegistryConfig:     registryConfig,
+		VerificationPolicy: policy,
+		RekorURL:           o.RekorURL,
+		VerifyOffline:      o.VerifyOffline,
+		Logger:             o.Logger,
+		// Progress is always logged since requests
+		// are served concurrently.
+		Progress: newProgressWriter(nil, o.Logger).Update,
 	}
 	service := collectionmanager.FromManager(manager, opts)
 
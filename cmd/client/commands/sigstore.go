@@ -1,99 +1,213 @@
 package commands
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
+	"net/http"
 	"os"
 	"sync"
-	"time"
 
 	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/config/credentials"
 	"github.com/docker/cli/cli/config/types"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/cmd/cosign/cli/generate"
 	cosignopts "github.com/sigstore/cosign/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/cmd/cosign/cli/rekor"
 	"github.com/sigstore/cosign/cmd/cosign/cli/sign"
-	"github.com/sigstore/cosign/cmd/cosign/cli/verify"
+	"github.com/sigstore/cosign/pkg/cosign"
+	"github.com/sigstore/cosign/pkg/cosign/bundle"
+	cremote "github.com/sigstore/cosign/pkg/cosign/remote"
+	"github.com/sigstore/cosign/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/pkg/oci/remote"
+	"github.com/sigstore/cosign/pkg/oci/static"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	signatureoptions "github.com/sigstore/sigstore/pkg/signature/options"
+	sigpayload "github.com/sigstore/sigstore/pkg/signature/payload"
 
 	// Loads OIDC providers
 	_ "github.com/sigstore/cosign/pkg/providers/all"
 
 	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/verification"
 )
 
-// Sign applies keyless OIDC signatures to sign Emporous Collections
-func signCollection(_ context.Context, reference string, authConfigs []string, remoteOpts options.Remote) error {
-
+// signCollection signs an Emporous Collection with the private key or,
+// if no key is set, with keyless OIDC signing.
+func signCollection(ctx context.Context, reference string, authConfigs []string, remoteOpts options.Remote, sigstoreOpts options.Sigstore) error {
 	ko := cosignopts.KeyOpts{
-		RekorURL:        "https://rekor.sigstore.dev",
-		OIDCClientID:    "sigstore",
-		OIDCRedirectURL: "",
-		OIDCIssuer:      "https://oauth2.sigstore.dev/auth",
-		FulcioURL:       "https://fulcio.sigstore.dev",
+		KeyRef:       sigstoreOpts.Key,
+		PassFunc:     generate.GetPass,
+		RekorURL:     sigstoreOpts.RekorURL,
+		OIDCClientID: "sigstore",
+		OIDCIssuer:   sigstoreOpts.OIDCIssuer,
+		FulcioURL:    sigstoreOpts.FulcioURL,
+	}
+	noTlogUpload := sigstoreOpts.Key != "" && sigstoreOpts.NoTlogUpload
+
+	regopts, transport, err := cosignRegistryOptions(authConfigs, remoteOpts)
+	if err != nil {
+		return err
 	}
+	return signReference(ctx, reference, regopts, transport, ko, noTlogUpload)
+}
 
-	// Required by sigstore / cosign for keyless signing at the time of writing
-	os.Setenv("COSIGN_EXPERIMENTAL", "1")
+// signReference signs the reference as the cosign sign command does, but
+// connects to the registry with the transport, which the sign command does not
+// accept. Unless noTlogUpload is set, the signature is uploaded to the
+// transparency log and the entry is bundled with the signature.
+func signReference(ctx context.Context, reference string, regopts cosignopts.RegistryOptions, transport http.RoundTripper, ko cosignopts.KeyOpts, noTlogUpload bool) error {
+	ctx, cancel := context.WithTimeout(ctx, cosignopts.DefaultTimeout)
+	defer cancel()
 
-	regopts := cosignopts.RegistryOptions{
-		Keychain: authn.DefaultKeychain,
+	clientOpts, err := cosignClientOptions(ctx, regopts, transport)
+	if err != nil {
+		return err
 	}
-	if remoteOpts.PlainHTTP || remoteOpts.Insecure {
-		regopts.AllowInsecure = true
+
+	var nameOpts []name.Option
+	if regopts.AllowInsecure {
+		nameOpts = append(nameOpts, name.Insecure)
+	}
+	ref, err := name.ParseReference(reference, nameOpts...)
+	if err != nil {
+		return err
 	}
+	se, err := ociremote.SignedEntity(ref, clientOpts...)
+	if err != nil {
+		return fmt.Errorf("accessing collection: %w", err)
+	}
+	hash, err := se.(interface{ Digest() (v1.Hash, error) }).Digest()
+	if err != nil {
+		return fmt.Errorf("computing digest: %w", err)
+	}
+	digest := ref.Context().Digest(hash.String())
 
-	if len(authConfigs) != 0 {
-		var err error
-		regopts.Keychain, err = buildKeychain(authConfigs)
+	sv, err := sign.SignerFromKeyOpts(ctx, "", "", ko)
+	if err != nil {
+		return fmt.Errorf("getting signer: %w", err)
+	}
+	defer sv.Close()
+
+	payload, err := (&sigpayload.Cosign{Image: digest}).MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("payload: %w", err)
+	}
+	sig, err := sv.SignMessage(bytes.NewReader(payload), signatureoptions.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("signing payload: %w", err)
+	}
+
+	var sigOpts []static.Option
+	rekorBytes := sv.Cert
+	if sv.Cert != nil {
+		sigOpts = append(sigOpts, static.WithCertChain(sv.Cert, sv.Chain))
+	} else {
+		pub, err := sv.PublicKey()
 		if err != nil {
 			return err
 		}
+		if rekorBytes, err = cryptoutils.MarshalPublicKeyToPEM(pub); err != nil {
+			return err
+		}
 	}
-
-	opts := cosignopts.RootOptions{
-		Timeout: 100 * time.Second,
+	if !noTlogUpload {
+		rekorClient, err := rekor.NewClient(ko.RekorURL)
+		if err != nil {
+			return err
+		}
+		entry, err := cosign.TLogUpload(ctx, rekorClient, sig, payload, rekorBytes)
+		if err != nil {
+			return fmt.Errorf("uploading to transparency log: %w", err)
+		}
+		sigOpts = append(sigOpts, static.WithBundle(bundle.EntryToBundle(entry)))
 	}
-	err := sign.SignCmd(&opts, ko, regopts, map[string]interface{}{},
-		[]string{reference}, "", "", true, "", "",
-		"", true, false, "", false)
+	ociSig, err := static.NewSignature(payload, base64.StdEncoding.EncodeToString(sig), sigOpts...)
 	if err != nil {
-		return fmt.Errorf("getting signer: %w", err)
+		return err
+	}
+
+	signed, err := mutate.AttachSignatureToEntity(se, ociSig, mutate.WithDupeDetector(cremote.NewDupeDetector(sv)))
+	if err != nil {
+		return err
+	}
+	if err := ociremote.WriteSignatures(digest.Repository, signed, clientOpts...); err != nil {
+		return fmt.Errorf("writing signature: %w", err)
 	}
 	return nil
 }
 
-// Verify performs signature verification of keyless signatures
-func verifyCollection(ctx context.Context, reference string, authConfigs []string, remoteOpts options.Remote) error {
+// newVerifier returns a verifier for the collections pulled by a
+// command per the verification policy and options.
+func newVerifier(o *options.Common, authConfigs []string, remoteOpts options.Remote, sigstoreOpts options.Sigstore) (*verification.Verifier, error) {
+	policy, err := sigstoreOpts.Policy()
+	if err != nil {
+		return nil, err
+	}
+	regopts, transport, err := cosignRegistryOptions(authConfigs, remoteOpts)
+	if err != nil {
+		return nil, err
+	}
+	return verification.NewVerifier(policy,
+		verification.WithKeychain(regopts.Keychain),
+		verification.WithTransport(transport),
+		verification.WithInsecure(regopts.AllowInsecure),
+		verification.WithRekorURL(sigstoreOpts.RekorURL),
+		verification.WithOffline(sigstoreOpts.VerifyOffline),
+		verification.WithLogger(o.Logger),
+	)
+}
 
+// cosignRegistryOptions returns the sigstore / cosign registry options for
+// the registry authentication and remote options. Sigstore connects to
+// registries with the returned transport, which has the TLS settings of the
+// remote options, so the shared go-containerregistry transport is not modified.
+func cosignRegistryOptions(authConfigs []string, remoteOpts options.Remote) (cosignopts.RegistryOptions, *http.Transport, error) {
 	regopts := cosignopts.RegistryOptions{
 		Keychain: authn.DefaultKeychain,
 	}
-
 	if remoteOpts.PlainHTTP || remoteOpts.Insecure {
 		regopts.AllowInsecure = true
 	}
-
 	if len(authConfigs) != 0 {
 		var err error
 		regopts.Keychain, err = buildKeychain(authConfigs)
 		if err != nil {
-			return err
+			return regopts, nil, err
 		}
 	}
 
-	v := verify.VerifyCommand{
-		RekorURL:        "https://rekor.sigstore.dev",
-		RegistryOptions: regopts,
+	registryConfig, err := remoteOpts.RegistryConfiguration()
+	if err != nil {
+		return regopts, nil, err
 	}
+	transport, err := orasclient.NewTransport(
+		orasclient.SkipTLSVerify(remoteOpts.Insecure),
+		orasclient.WithCAFile(remoteOpts.CAFile),
+		orasclient.WithClientCertificate(remoteOpts.CertFile, remoteOpts.KeyFile),
+		orasclient.WithRegistryConfig(registryConfig),
+	)
+	if err != nil {
+		return regopts, nil, err
+	}
+	return regopts, transport, nil
+}
 
-	// Required by sigstore / cosign for keyless signing at the time of writing
-	os.Setenv("COSIGN_EXPERIMENTAL", "1")
-
-	if err := v.Exec(ctx, []string{reference}); err != nil {
-		return err
+// cosignClientOptions returns the sigstore / cosign registry
+// client options connecting to registries with the transport.
+func cosignClientOptions(ctx context.Context, regopts cosignopts.RegistryOptions, transport http.RoundTripper) ([]ociremote.Option, error) {
+	opts, err := regopts.ClientOpts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("constructing client options: %w", err)
 	}
-	return nil
+	remoteOpts := append(regopts.GetRegistryClientOpts(ctx), remote.WithTransport(transport))
+	return append(opts, ociremote.WithRemoteOptions(remoteOpts...)), nil
 }
 
 type KeyChainFunc func(authn.Resource) (authn.Authenticator, error)
@@ -0,0 +1,258 @@
+package commands
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	cosignopts "github.com/sigstore/cosign/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/pkg/cosign"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/util/testutils"
+	"github.com/emporous/emporous-go/verification"
+)
+
+func TestSignVerifyCollectionWithKey(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(io.Discard, "debug")
+	require.NoError(t, err)
+
+	// The password of the signing key is read from the environment.
+	t.Setenv("COSIGN_PASSWORD", "test")
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	keyDir := t.TempDir()
+	writeKeyPair := func(name string) (string, string) {
+		keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return []byte("test"), nil })
+		require.NoError(t, err)
+		privateKey := filepath.Join(keyDir, name+".key")
+		publicKey := filepath.Join(keyDir, name+".pub")
+		require.NoError(t, os.WriteFile(privateKey, keys.PrivateBytes, 0600))
+		require.NoError(t, os.WriteFile(publicKey, keys.PublicBytes, 0600))
+		return privateKey, publicKey
+	}
+	privateKey, publicKey := writeKeyPair("cosign")
+	_, otherPublicKey := writeKeyPair("other")
+
+	rekor, rekorPub := testutils.NewRekor(t)
+	rekorServer := httptest.NewServer(rekor)
+	t.Cleanup(rekorServer.Close)
+	rekorKey := filepath.Join(keyDir, "rekor.pub")
+	require.NoError(t, os.WriteFile(rekorKey, rekorPub, 0600))
+	t.Setenv("SIGSTORE_REKOR_PUBLIC_KEY", rekorKey)
+
+	common := &options.Common{
+		IOStreams: genericclioptions.IOStreams{
+			Out:    os.Stdout,
+			In:     os.Stdin,
+			ErrOut: os.Stderr,
+		},
+		Logger:   testlogr,
+		CacheDir: t.TempDir(),
+	}
+	remote := options.Remote{PlainHTTP: true}
+	reference := fmt.Sprintf("%s/signed:latest", u.Host)
+	unbundled := fmt.Sprintf("%s/unbundled:latest", u.Host)
+	for _, destination := range []string{reference, unbundled} {
+		pushCommon := *common
+		pushCommon.CacheDir = t.TempDir()
+		require.NoError(t, prepCache(destination, pushCommon.CacheDir, nil))
+		push := &PushOptions{
+			Common:      &pushCommon,
+			Remote:      remote,
+			Destination: destination,
+			Sign:        true,
+			Sigstore: options.Sigstore{
+				Key:          privateKey,
+				RekorURL:     rekorServer.URL,
+				NoTlogUpload: destination == unbundled,
+			},
+		}
+		require.NoError(t, push.Validate())
+		require.NoError(t, push.Run(context.TODO()))
+	}
+
+	type spec struct {
+		name      string
+		reference string
+		sigstore  options.Sigstore
+		expError  string
+	}
+
+	cases := []spec{
+		{
+			name:      "Success/PublicKeyOffline",
+			reference: reference,
+			sigstore: options.Sigstore{
+				PublicKey:     publicKey,
+				VerifyOffline: true,
+			},
+		},
+		{
+			name:      "Failure/WrongPublicKey",
+			reference: reference,
+			sigstore: options.Sigstore{
+				PublicKey:     otherPublicKey,
+				VerifyOffline: true,
+			},
+			expError: "no matching signatures",
+		},
+		{
+			name:      "Failure/NoBundleOffline",
+			reference: unbundled,
+			sigstore: options.Sigstore{
+				PublicKey:     publicKey,
+				VerifyOffline: true,
+			},
+			expError: "no signature bundles a verified transparency log entry",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pull := &PullOptions{
+				Common:   common,
+				Remote:   remote,
+				Source:   c.reference,
+				Output:   t.TempDir(),
+				Sigstore: c.sigstore,
+			}
+			err := pull.Run(context.TODO())
+			if c.expError != "" {
+				require.ErrorContains(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.FileExists(t, filepath.Join(pull.Output, "hello.txt"))
+			}
+		})
+	}
+}
+
+func TestSignReference(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(io.Discard, "debug")
+	require.NoError(t, err)
+
+	// The registry certificate is only trusted through the
+	// CA file, which signing and verification must use.
+	server := httptest.NewTLSServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	keyDir := t.TempDir()
+	caFile := filepath.Join(keyDir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0600))
+	remote := options.Remote{CAFile: caFile}
+
+	keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return []byte("test"), nil })
+	require.NoError(t, err)
+	privateKey := filepath.Join(keyDir, "cosign.key")
+	publicKey := filepath.Join(keyDir, "cosign.pub")
+	require.NoError(t, os.WriteFile(privateKey, keys.PrivateBytes, 0600))
+	require.NoError(t, os.WriteFile(publicKey, keys.PublicBytes, 0600))
+
+	rekor, rekorPub := testutils.NewRekor(t)
+	rekorServer := httptest.NewServer(rekor)
+	t.Cleanup(rekorServer.Close)
+	rekorKey := filepath.Join(keyDir, "rekor.pub")
+	require.NoError(t, os.WriteFile(rekorKey, rekorPub, 0600))
+	t.Setenv("SIGSTORE_REKOR_PUBLIC_KEY", rekorKey)
+
+	fulcio, fulcioRoot := testutils.NewFulcio(t)
+	fulcioServer := httptest.NewServer(fulcio)
+	t.Cleanup(fulcioServer.Close)
+	fulcioRootFile := filepath.Join(keyDir, "fulcio.pem")
+	require.NoError(t, os.WriteFile(fulcioRootFile, fulcioRoot, 0600))
+	t.Setenv("SIGSTORE_ROOT_FILE", fulcioRootFile)
+
+	common := &options.Common{
+		IOStreams: genericclioptions.IOStreams{
+			Out:    os.Stdout,
+			In:     os.Stdin,
+			ErrOut: os.Stderr,
+		},
+		Logger: testlogr,
+	}
+
+	type spec struct {
+		name      string
+		ko        cosignopts.KeyOpts
+		authority clientapi.Authority
+	}
+
+	cases := []spec{
+		{
+			name: "Success/Key",
+			ko: cosignopts.KeyOpts{
+				KeyRef:   privateKey,
+				PassFunc: func(bool) ([]byte, error) { return []byte("test"), nil },
+				RekorURL: rekorServer.URL,
+			},
+			authority: clientapi.Authority{PublicKey: publicKey},
+		},
+		{
+			name: "Success/Keyless",
+			ko: cosignopts.KeyOpts{
+				IDToken:                  testutils.NewIDToken(t, "https://issuer.example.com", "signer@example.com"),
+				FulcioURL:                fulcioServer.URL,
+				InsecureSkipFulcioVerify: true,
+				RekorURL:                 rekorServer.URL,
+			},
+			authority: clientapi.Authority{
+				Keyless: &clientapi.KeylessAuthority{
+					Subject: "signer@example.com",
+					Issuer:  "https://issuer.example.com",
+				},
+			},
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			reference := fmt.Sprintf("%s/signed%d:latest", u.Host, i)
+			pushCommon := *common
+			pushCommon.CacheDir = t.TempDir()
+			require.NoError(t, prepCache(reference, pushCommon.CacheDir, nil))
+			push := &PushOptions{
+				Common:      &pushCommon,
+				Remote:      remote,
+				Destination: reference,
+			}
+			require.NoError(t, push.Validate())
+			require.NoError(t, push.Run(context.TODO()))
+
+			regopts, transport, err := cosignRegistryOptions(nil, remote)
+			require.NoError(t, err)
+			require.NoError(t, signReference(context.TODO(), reference, regopts, transport, c.ko, false))
+
+			policy := clientapi.VerificationPolicy{
+				Policies: []clientapi.ReferencePolicy{
+					{Pattern: "**", Authorities: []clientapi.Authority{c.authority}},
+				},
+			}
+			verifier, err := verification.NewVerifier(policy,
+				verification.WithKeychain(regopts.Keychain),
+				verification.WithTransport(transport),
+				verification.WithOffline(true),
+			)
+			require.NoError(t, err)
+			require.NoError(t, verifier.Verify(context.TODO(), reference))
+		})
+	}
+}
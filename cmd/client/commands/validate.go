@@ -0,0 +1,331 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/content/layout"
+	"github.com/emporous/emporous-go/nodes/collection"
+	v2 "github.com/emporous/emporous-go/nodes/descriptor/v2"
+	"github.com/emporous/emporous-go/registryclient"
+	"github.com/emporous/emporous-go/registryclient/orasclient"
+	"github.com/emporous/emporous-go/schema"
+	"github.com/emporous/emporous-go/util/examples"
+)
+
+// ValidateOptions describe configuration options that can
+// be set using the validate subcommand.
+type ValidateOptions struct {
+	*options.Common
+	options.Remote
+	options.RemoteAuth
+	Reference string
+	Schema    string
+	Offline   bool
+}
+
+var clientValidateExamples = []examples.Example{
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "validate localhost:5001/test:latest",
+		Descriptions: []string{
+			"Validate the attributes of a collection against the schema it was built with.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "validate localhost:5001/test:latest --schema localhost:5001/myschema:v2",
+		Descriptions: []string{
+			"Validate the attributes of a collection against a newer version of its schema.",
+		},
+	},
+	{
+		RootCommand:   filepath.Base(os.Args[0]),
+		CommandString: "validate localhost:5001/test:latest --schema schema-config.yaml --offline",
+		Descriptions: []string{
+			"Validate the attributes of a cached collection against a schema configuration.",
+		},
+	},
+}
+
+// NewValidateCmd creates a new cobra.Command for the validate subcommand.
+func NewValidateCmd(common *options.Common) *cobra.Command {
+	o := ValidateOptions{Common: common}
+
+	cmd := &cobra.Command{
+		Use:   "validate REF",
+		Short: "Validate the attributes of a Emporous collection against a schema",
+		Long: "Validate the attributes of each file in a collection against a schema and report the descriptors that do not conform. " +
+			"The schema is resolved from the collection unless set with --schema.",
+		Example:       examples.FormatExamples(clientValidateExamples...),
+		SilenceErrors: false,
+		SilenceUsage:  false,
+		Args:          cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cobra.CheckErr(o.Complete(args))
+			cobra.CheckErr(o.Validate())
+			cobra.CheckErr(o.Run(cmd.Context()))
+		},
+	}
+
+	o.Remote.BindFlags(cmd.Flags())
+	o.RemoteAuth.BindFlags(cmd.Flags())
+
+	cmd.Flags().StringVar(&o.Schema, "schema", o.Schema, "Schema reference or path to a schema configuration to validate against")
+	cmd.Flags().BoolVar(&o.Offline, "offline", o.Offline, "Resolve the collection and schema from the cache without network access")
+
+	return cmd
+}
+
+func (o *ValidateOptions) Complete(args []string) error {
+	if len(args) < 1 {
+		return errors.New("bug: expecting one argument")
+	}
+	o.Reference = args[0]
+	return nil
+}
+
+func (o *ValidateOptions) Validate() error {
+	return nil
+}
+
+func (o *ValidateOptions) Run(ctx context.Context) error {
+	cache, err := layout.NewWithContext(ctx, o.CacheDir)
+	if err != nil {
+		return err
+	}
+
+	registryConfig, err := o.Remote.RegistryConfiguration()
+	if err != nil {
+		return err
+	}
+
+	client, err := orasclient.NewClient(
+		orasclient.SkipTLSVerify(o.Insecure),
+		orasclient.WithCAFile(o.CAFile),
+		orasclient.WithClientCertificate(o.CertFile, o.KeyFile),
+		orasclient.WithAuthConfigs(o.Configs),
+		orasclient.WithPlainHTTP(o.PlainHTTP),
+		orasclient.WithRetryPolicy(o.Remote.RetryPolicy()),
+		orasclient.WithLogger(o.Logger),
+		orasclient.WithRegistryConfig(registryConfig),
+		orasclient.WithCache(cache),
+		orasclient.WithOffline(o.Offline),
+	)
+	if err != nil {
+		return fmt.Errorf("error configuring client: %v", err)
+	}
+	defer func() {
+		if err := client.Destroy(); err != nil {
+			o.Logger.Errorf(err.Error())
+		}
+	}()
+
+	co, err := client.LoadCollection(ctx, o.Reference)
+	if err != nil {
+		return err
+	}
+
+	source := o.Schema
+	var (
+		loader   schema.Loader
+		schemaID string
+	)
+	if source != "" {
+		loader, schemaID, err = resolveSchema(ctx, client, source)
+	} else {
+		source, loader, schemaID, err = o.collectionSchema(ctx, client, co)
+	}
+	if err != nil {
+		return err
+	}
+	// Attributes of collections built with a schema without
+	// an ID are stored under the unknown schema ID.
+	if schemaID == "" {
+		schemaID = schema.UnknownSchemaID
+	}
+	o.Logger.Debugf("Validating collection %s against schema %s with ID %s", o.Reference, source, schemaID)
+
+	sc, err := schema.New(loader)
+	if err != nil {
+		return fmt.Errorf("schema %s: %w", source, err)
+	}
+
+	result := ValidateResult{
+		Reference:     o.Reference,
+		Schema:        source,
+		SchemaID:      schemaID,
+		NonConforming: []DescriptorViolations{},
+	}
+	for _, node := range co.Nodes() {
+		desc, ok := node.(*v2.Node)
+		if !ok {
+			continue
+		}
+		// Only file descriptors carry user attributes, so manifests, configs,
+		// schemas, and links to other collections are skipped.
+		if len(co.From(node.ID())) != 0 || desc.Properties.IsALink() {
+			continue
+		}
+		// Files without attributes are stored with an empty set under the
+		// schema ID and skipped, as they are not validated when building either.
+		if set, ok := desc.Properties.Others[schemaID]; ok && set.Len() == 0 {
+			continue
+		}
+		switch desc.Descriptor().MediaType {
+		case empspec.MediaTypeSchemaDescriptor, empspec.MediaTypeConfiguration, ocispec.MediaTypeImageConfig:
+			continue
+		}
+		result.Validated++
+
+		location := desc.Descriptor().Annotations[ocispec.AnnotationTitle]
+		violations, err := validateDescriptor(sc, schemaID, desc)
+		if err != nil {
+			return fmt.Errorf("descriptor %s: %w", desc.Descriptor().Digest, err)
+		}
+		if len(violations) == 0 {
+			continue
+		}
+		for i := range violations {
+			violations[i].Location = location
+		}
+		result.NonConforming = append(result.NonConforming, DescriptorViolations{
+			Location:   location,
+			Digest:     desc.Descriptor().Digest.String(),
+			Violations: violations,
+		})
+	}
+	sort.Slice(result.NonConforming, func(i, j int) bool {
+		a, b := result.NonConforming[i], result.NonConforming[j]
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		return a.Digest < b.Digest
+	})
+	result.Valid = len(result.NonConforming) == 0
+
+	err = o.PrintResult(result, func(w io.Writer) error {
+		if result.Valid {
+			_, err := fmt.Fprintf(w, "Collection %s conforms to schema %s (%d descriptor(s) validated)\n", o.Reference, source, result.Validated)
+			return err
+		}
+		return formatViolations(w, result.NonConforming)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !result.Valid {
+		return fmt.Errorf("collection %s does not conform to schema %s: %d non-conforming descriptor(s)", o.Reference, source, len(result.NonConforming))
+	}
+	return nil
+}
+
+// collectionSchema resolves the schema of the collection from its schema
+// descriptor or from the schema address in its stored dataset configuration.
+// It returns the schema source, loader, and ID.
+func (o *ValidateOptions) collectionSchema(ctx context.Context, client registryclient.Remote, co collection.Collection) (string, schema.Loader, string, error) {
+	var configDesc *ocispec.Descriptor
+	for _, node := range co.Nodes() {
+		desc, ok := node.(*v2.Node)
+		if !ok {
+			continue
+		}
+		switch desc.Descriptor().MediaType {
+		case empspec.MediaTypeSchemaDescriptor:
+			loader, schemaID, err := fetchSchemaDescriptor(ctx, client, o.Reference, desc.Descriptor())
+			return o.Reference, loader, schemaID, err
+		case empspec.MediaTypeConfiguration:
+			d := desc.Descriptor()
+			configDesc = &d
+		}
+	}
+
+	if configDesc != nil {
+		configBytes, err := client.GetContent(ctx, o.Reference, *configDesc)
+		if err != nil {
+			return "", schema.Loader{}, "", fmt.Errorf("error fetching dataset configuration: %w", err)
+		}
+		var config v1alpha1.DataSetConfiguration
+		if err := json.Unmarshal(configBytes, &config); err != nil {
+			return "", schema.Loader{}, "", fmt.Errorf("error decoding dataset configuration: %w", err)
+		}
+		if address := config.Collection.SchemaAddress; address != "" {
+			loader, schemaID, err := fetchSchema(ctx, client, address)
+			return address, loader, schemaID, err
+		}
+	}
+	return "", schema.Loader{}, "", fmt.Errorf("collection %s does not reference a schema, set one with --schema", o.Reference)
+}
+
+// validateDescriptor validates the attributes of the descriptor
+// stored under the schema ID.
+func validateDescriptor(sc schema.Schema, schemaID string, desc *v2.Node) ([]schema.Violation, error) {
+	set, ok := desc.Properties.Others[schemaID]
+	if !ok {
+		return []schema.Violation{{
+			Rule:    "schema",
+			Message: fmt.Sprintf("attributes are not set for schema %s", schemaID),
+		}}, nil
+	}
+	valid, err := sc.Validate(set)
+	if valid {
+		return nil, nil
+	}
+	var validationErr *schema.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Violations, nil
+	}
+	return nil, err
+}
+
+func formatViolations(w io.Writer, descs []DescriptorViolations) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "Location\tDigest\tPointer\tRule\tMessage"); err != nil {
+		return err
+	}
+	for _, desc := range descs {
+		for _, v := range desc.Violations {
+			pointer := v.Pointer
+			if pointer == "" {
+				pointer = "(root)"
+			}
+			if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", desc.Location, desc.Digest, pointer, v.Rule, v.Message); err != nil {
+				return err
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+// ValidateResult describes the result of validating
+// the attributes of a collection against a schema.
+type ValidateResult struct {
+	Reference     string                 `json:"reference"`
+	Schema        string                 `json:"schema"`
+	SchemaID      string                 `json:"schemaID"`
+	Valid         bool                   `json:"valid"`
+	Validated     int                    `json:"validated"`
+	NonConforming []DescriptorViolations `json:"nonConforming"`
+}
+
+// DescriptorViolations lists the schema violations
+// found in the attributes of a descriptor.
+type DescriptorViolations struct {
+	Location   string             `json:"location,omitempty"`
+	Digest     string             `json:"digest"`
+	Violations []schema.Violation `json:"violations"`
+}
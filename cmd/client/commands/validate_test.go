@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/emporous/emporous-go/cmd/client/commands/options"
+	"github.com/emporous/emporous-go/log"
+)
+
+func TestValidateRun(t *testing.T) {
+	testlogr, err := log.NewLogrusLogger(io.Discard, "debug")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	common := &options.Common{
+		IOStreams: genericclioptions.IOStreams{
+			Out:    out,
+			In:     os.Stdin,
+			ErrOut: os.Stderr,
+		},
+		Logger:   testlogr,
+		CacheDir: t.TempDir(),
+	}
+	remote := options.Remote{PlainHTTP: true}
+
+	templateValues := prepCollectionArtifacts(t, u.Host)
+	schemaRef := templateValues["schemaAddress"]
+	initialConfig, err := ioutil.ReadFile("./testdata/configs/dataset-config-schema.yaml")
+	require.NoError(t, err)
+	tpl, err := template.New("validate").Parse(string(initialConfig))
+	require.NoError(t, err)
+	configPath := filepath.Join(t.TempDir(), "dataset-config.yaml")
+	config, err := os.Create(configPath)
+	require.NoError(t, err)
+	require.NoError(t, tpl.Execute(config, templateValues))
+	require.NoError(t, config.Close())
+
+	reference := fmt.Sprintf("%s/validate:latest", u.Host)
+	build := &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{Common: common, Destination: reference},
+		Remote:       remote,
+		RootDir:      "./testdata/multi-level-workspace",
+		DSConfig:     configPath,
+		NoVerify:     true,
+	}
+	require.NoError(t, build.Run(context.TODO()))
+	push := &PushOptions{Common: common, Remote: remote, Destination: reference}
+	require.NoError(t, push.Run(context.TODO()))
+
+	// Build a collection linking another collection.
+	linksConfig, err := ioutil.ReadFile("./testdata/configs/dataset-config-links.yaml")
+	require.NoError(t, err)
+	tpl, err = template.New("links").Parse(string(linksConfig))
+	require.NoError(t, err)
+	linksConfigPath := filepath.Join(t.TempDir(), "dataset-config-links.yaml")
+	config, err = os.Create(linksConfigPath)
+	require.NoError(t, err)
+	require.NoError(t, tpl.Execute(config, templateValues))
+	require.NoError(t, config.Close())
+
+	links := fmt.Sprintf("%s/validate-links:latest", u.Host)
+	build = &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{Common: common, Destination: links},
+		Remote:       remote,
+		RootDir:      "./testdata/multi-level-workspace",
+		DSConfig:     linksConfigPath,
+		NoVerify:     true,
+	}
+	require.NoError(t, build.Run(context.TODO()))
+	push = &PushOptions{Common: common, Remote: remote, Destination: links}
+	require.NoError(t, push.Run(context.TODO()))
+
+	// Build a collection where only some files have attributes.
+	partial := fmt.Sprintf("%s/validate-partial:latest", u.Host)
+	build = &BuildCollectionOptions{
+		BuildOptions: &BuildOptions{Common: common, Destination: partial},
+		Remote:       remote,
+		RootDir:      "./testdata/multi-level-workspace",
+		DSConfig:     "./testdata/configs/dataset-config-partial.yaml",
+		NoVerify:     true,
+	}
+	require.NoError(t, build.Run(context.TODO()))
+	push = &PushOptions{Common: common, Remote: remote, Destination: partial}
+	require.NoError(t, push.Run(context.TODO()))
+
+	run := func(format string, o *ValidateOptions) error {
+		out.Reset()
+		formatCommon := *common
+		formatCommon.Format = format
+		o.Common = &formatCommon
+		o.Remote = remote
+		require.NoError(t, o.Validate())
+		return o.Run(context.TODO())
+	}
+
+	t.Run("Success/CollectionSchema", func(t *testing.T) {
+		require.NoError(t, run(options.FormatTable, &ValidateOptions{Reference: reference}))
+		require.Equal(t, fmt.Sprintf("Collection %s conforms to schema %s (4 descriptor(s) validated)\n", reference, schemaRef), out.String())
+	})
+
+	t.Run("Success/Offline", func(t *testing.T) {
+		require.NoError(t, run(options.FormatJSON, &ValidateOptions{Reference: reference, Offline: true}))
+		var result ValidateResult
+		require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+		require.True(t, result.Valid)
+		require.Equal(t, schemaRef, result.Schema)
+		require.Equal(t, "unknown", result.SchemaID)
+		require.Equal(t, 4, result.Validated)
+		require.Empty(t, result.NonConforming)
+	})
+
+	t.Run("Success/Links", func(t *testing.T) {
+		schemaConfig := "testdata/configs/schema-config.yaml"
+		require.NoError(t, run(options.FormatJSON, &ValidateOptions{Reference: links, Schema: schemaConfig}))
+		var result ValidateResult
+		require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+		require.True(t, result.Valid)
+		require.Equal(t, 4, result.Validated)
+		require.Empty(t, result.NonConforming)
+	})
+
+	t.Run("Success/SchemaDescriptor", func(t *testing.T) {
+		require.NoError(t, run(options.FormatTable, &ValidateOptions{Reference: schemaRef}))
+		require.Equal(t, fmt.Sprintf("Collection %s conforms to schema %s (0 descriptor(s) validated)\n", schemaRef, schemaRef), out.String())
+	})
+
+	t.Run("Failure/Violations", func(t *testing.T) {
+		schemaConfig := "testdata/configs/schema-config-strict.yaml"
+		err := run(options.FormatJSON, &ValidateOptions{Reference: reference, Schema: schemaConfig})
+		require.EqualError(t, err, fmt.Sprintf("collection %s does not conform to schema %s: 4 non-conforming descriptor(s)", reference, schemaConfig))
+
+		var result ValidateResult
+		require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+		require.False(t, result.Valid)
+		require.Len(t, result.NonConforming, 4)
+		require.Equal(t, "images/fish.jpg", result.NonConforming[0].Location)
+		require.Contains(t, result.NonConforming[0].Digest, "sha256:")
+		var rules []string
+		for _, v := range result.NonConforming[0].Violations {
+			require.Equal(t, "images/fish.jpg", v.Location)
+			rules = append(rules, v.Pointer+" "+v.Rule)
+		}
+		require.Equal(t, []string{"/size number_lte", "/test enum"}, rules)
+	})
+
+	t.Run("Failure/SchemaID", func(t *testing.T) {
+		schemaConfig := "testdata/configs/schema-config-constraints.yaml"
+		err := run(options.FormatJSON, &ValidateOptions{Reference: reference, Schema: schemaConfig})
+		require.Error(t, err)
+
+		var result ValidateResult
+		require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+		require.Equal(t, "myschema", result.SchemaID)
+		require.Len(t, result.NonConforming, 4)
+		for _, desc := range result.NonConforming {
+			require.Len(t, desc.Violations, 1)
+			require.Equal(t, "schema", desc.Violations[0].Rule)
+			require.Equal(t, "attributes are not set for schema myschema", desc.Violations[0].Message)
+		}
+	})
+
+	t.Run("Success/FileWithoutAttributes", func(t *testing.T) {
+		schemaConfig := "testdata/configs/schema-config.yaml"
+		require.NoError(t, run(options.FormatJSON, &ValidateOptions{Reference: partial, Schema: schemaConfig}))
+
+		var result ValidateResult
+		require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+		require.True(t, result.Valid)
+		require.Equal(t, 3, result.Validated)
+		require.Empty(t, result.NonConforming)
+	})
+
+	t.Run("Failure/NoSchema", func(t *testing.T) {
+		collection := templateValues["linkedCollection"]
+		err := run(options.FormatTable, &ValidateOptions{Reference: collection})
+		require.EqualError(t, err, fmt.Sprintf("collection %s does not reference a schema, set one with --schema", collection))
+	})
+}
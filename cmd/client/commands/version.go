@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"io"
+
 	"github.com/spf13/cobra"
 
 	"github.com/emporous/emporous-go/cmd/client/commands/options"
@@ -14,7 +16,9 @@ func NewVersionCmd(common *options.Common) *cobra.Command {
 		Short: "Print the version",
 		Args:  cobra.NoArgs,
 		RunE: func(_ *cobra.Command, _ []string) error {
-			return version.WriteVersion(common.IOStreams.Out)
+			return common.PrintResult(version.GetVersionInfo(), func(w io.Writer) error {
+				return version.WriteVersion(w)
+			})
 		},
 	}
 }
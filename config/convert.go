@@ -1,11 +1,14 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/emporous/emporous-go/api/client/v1alpha1"
 	"github.com/emporous/emporous-go/attributes"
+	"github.com/emporous/emporous-go/attributes/matchers"
 	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/nodes/descriptor"
 )
 
 // ConvertToModel converts v1alpha1.Attributes to an model.AttributeSet.
@@ -20,3 +23,36 @@ func ConvertToModel(input v1alpha1.Attributes) (model.AttributeSet, error) {
 	}
 	return set, nil
 }
+
+// ConvertToMatcher converts a v1alpha1.AttributeQuery to a model.Matcher.
+// If both attributes and a query expression are set, a node must satisfy both.
+func ConvertToMatcher(query v1alpha1.AttributeQuery) (model.Matcher, error) {
+	var all []model.Matcher
+	if len(query.Attributes) != 0 {
+		all = append(all, descriptor.JSONSubsetMatcher(query.Attributes))
+	}
+	if query.Query != "" {
+		expr, err := matchers.ParseExpression(query.Query)
+		if err != nil {
+			return nil, fmt.Errorf("error converting query to matcher: %w", err)
+		}
+		all = append(all, expr)
+	}
+
+	switch len(all) {
+	case 0:
+		return nil, errors.New("attribute query must set attributes or a query expression")
+	case 1:
+		return all[0], nil
+	}
+
+	return model.MatcherFunc(func(node model.Node) (bool, error) {
+		for _, m := range all {
+			match, err := m.Matches(node)
+			if err != nil || !match {
+				return false, err
+			}
+		}
+		return true, nil
+	}), nil
+}
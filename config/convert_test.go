@@ -8,6 +8,7 @@ import (
 	"github.com/emporous/emporous-go/api/client/v1alpha1"
 	"github.com/emporous/emporous-go/attributes"
 	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/util/testutils"
 )
 
 func TestConvertToModel(t *testing.T) {
@@ -75,6 +76,28 @@ func TestConvertToModel(t *testing.T) {
 				return stringExists && boolExists && numExists && nullExists && intExists
 			},
 		},
+		{
+			name: "Success/ListAndMapAttributes",
+			attributes: v1alpha1.Attributes{
+				"tags":  []interface{}{"gpu", "fp16"},
+				"owner": map[string]interface{}{"team": "x"},
+			},
+			asserFunc: func(set model.AttributeSet) bool {
+				listExists, err := set.Exists(attributes.NewString("tags", "gpu"))
+				if err != nil {
+					t.Log(err)
+					return false
+				}
+				mapExists, err := set.Exists(attributes.NewMap("owner", map[string]model.Attribute{
+					"team": attributes.NewString("team", "x"),
+				}))
+				if err != nil {
+					t.Log(err)
+					return false
+				}
+				return listExists && mapExists
+			},
+		},
 		{
 			name: "Failure/InvalidAttributeType",
 			attributes: v1alpha1.Attributes{
@@ -96,3 +119,73 @@ func TestConvertToModel(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertToMatcher(t *testing.T) {
+	type spec struct {
+		name       string
+		query      v1alpha1.AttributeQuery
+		attributes model.AttributeSet
+		expRes     bool
+		expError   string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/Attributes",
+			query: v1alpha1.AttributeQuery{
+				Attributes: []byte(`{"size":"small"}`),
+			},
+			attributes: attributes.Attributes{
+				"size": attributes.NewString("size", "small"),
+			},
+			expRes: true,
+		},
+		{
+			name: "Success/Query",
+			query: v1alpha1.AttributeQuery{
+				Query: `size == "small" || size == "medium"`,
+			},
+			attributes: attributes.Attributes{
+				"size": attributes.NewString("size", "medium"),
+			},
+			expRes: true,
+		},
+		{
+			name: "Success/AttributesAndQuery",
+			query: v1alpha1.AttributeQuery{
+				Attributes: []byte(`{"size":"small"}`),
+				Query:      "!fiction",
+			},
+			attributes: attributes.Attributes{
+				"size":    attributes.NewString("size", "small"),
+				"fiction": attributes.NewBool("fiction", true),
+			},
+			expRes: false,
+		},
+		{
+			name:     "Failure/Empty",
+			query:    v1alpha1.AttributeQuery{},
+			expError: "attribute query must set attributes or a query expression",
+		},
+		{
+			name: "Failure/InvalidQuery",
+			query: v1alpha1.AttributeQuery{
+				Query: "size >",
+			},
+			expError: "error converting query to matcher: invalid expression at position 6: expected literal value, got \"\"",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			matcher, err := ConvertToMatcher(c.query)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				match, err := matcher.Matches(&testutils.FakeNode{A: c.attributes})
+				require.NoError(t, err)
+				require.Equal(t, c.expRes, match)
+			}
+		})
+	}
+}
@@ -96,6 +96,90 @@ func LoadAttributeQuery(data []byte) (configuration v1alpha1.AttributeQuery, err
 	return configuration, err
 }
 
+// ReadRegistryConfig reads the specified config into a RegistryConfiguration type.
+func ReadRegistryConfig(configPath string) (v1alpha1.RegistryConfiguration, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(configPath))
+	if err != nil {
+		return v1alpha1.RegistryConfiguration{}, err
+	}
+
+	return LoadRegistryConfig(data)
+}
+
+// LoadRegistryConfig loads a RegistryConfiguration type from input.
+func LoadRegistryConfig(data []byte) (configuration v1alpha1.RegistryConfiguration, err error) {
+	if data, err = yaml.YAMLToJSON(data); err != nil {
+		return configuration, err
+	}
+
+	if err = checkMeta(data, v1alpha1.RegistryConfigurationKind); err != nil {
+		return configuration, err
+	}
+
+	dec := json.NewDecoder(bytes.NewBuffer(data))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&configuration); err != nil {
+		return configuration, err
+	}
+	return configuration, err
+}
+
+// ReadVerificationPolicy reads the specified config into a VerificationPolicy type.
+func ReadVerificationPolicy(configPath string) (v1alpha1.VerificationPolicy, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(configPath))
+	if err != nil {
+		return v1alpha1.VerificationPolicy{}, err
+	}
+
+	return LoadVerificationPolicy(data)
+}
+
+// LoadVerificationPolicy loads a VerificationPolicy type from input.
+func LoadVerificationPolicy(data []byte) (configuration v1alpha1.VerificationPolicy, err error) {
+	if data, err = yaml.YAMLToJSON(data); err != nil {
+		return configuration, err
+	}
+
+	if err = checkMeta(data, v1alpha1.VerificationPolicyKind); err != nil {
+		return configuration, err
+	}
+
+	dec := json.NewDecoder(bytes.NewBuffer(data))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&configuration); err != nil {
+		return configuration, err
+	}
+	return configuration, err
+}
+
+// ReadLicensePolicy reads the specified config into a LicensePolicy type.
+func ReadLicensePolicy(configPath string) (v1alpha1.LicensePolicy, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(configPath))
+	if err != nil {
+		return v1alpha1.LicensePolicy{}, err
+	}
+
+	return LoadLicensePolicy(data)
+}
+
+// LoadLicensePolicy loads a LicensePolicy type from input.
+func LoadLicensePolicy(data []byte) (configuration v1alpha1.LicensePolicy, err error) {
+	if data, err = yaml.YAMLToJSON(data); err != nil {
+		return configuration, err
+	}
+
+	if err = checkMeta(data, v1alpha1.LicensePolicyKind); err != nil {
+		return configuration, err
+	}
+
+	dec := json.NewDecoder(bytes.NewBuffer(data))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&configuration); err != nil {
+		return configuration, err
+	}
+	return configuration, err
+}
+
 func checkMeta(data []byte, kind string) error {
 	var typeMeta v1alpha1.TypeMeta
 	if err := json.Unmarshal(data, &typeMeta); err != nil {
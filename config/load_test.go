@@ -30,6 +30,17 @@ func TestReadAttributeQuery(t *testing.T) {
 				Attributes: []byte(`{"size":"small"}`),
 			},
 		},
+		{
+			name: "Success/ValidQuery",
+			path: "testdata/valid-query.yaml",
+			exp: v1alpha1.AttributeQuery{
+				TypeMeta: v1alpha1.TypeMeta{
+					Kind:       v1alpha1.AttributeQueryKind,
+					APIVersion: v1alpha1.GroupVersion,
+				},
+				Query: "size > 1024 && !deprecated",
+			},
+		},
 		{
 			name:     "Failure/InvalidConfig",
 			path:     "testdata/valid-ds.yaml",
@@ -122,8 +133,38 @@ func TestReadSchemaConfiguration(t *testing.T) {
 					APIVersion: v1alpha1.GroupVersion,
 				},
 				Schema: v1alpha1.SchemaConfigurationSpec{
-					AttributeTypes: map[string]schema.Type{
-						"test": schema.TypeString,
+					AttributeTypes: schema.Properties{
+						"test": {Type: schema.TypeString},
+					},
+				},
+			},
+		},
+		{
+			name: "Success/ValidConfigWithConstraints",
+			path: "testdata/valid-schema-constraints.yaml",
+			exp: v1alpha1.SchemaConfiguration{
+				TypeMeta: v1alpha1.TypeMeta{
+					Kind:       v1alpha1.SchemaConfigurationKind,
+					APIVersion: v1alpha1.GroupVersion,
+				},
+				Schema: v1alpha1.SchemaConfigurationSpec{
+					AttributeTypes: schema.Properties{
+						"name": {Type: schema.TypeString},
+						"version": {
+							Type:        schema.TypeString,
+							Format:      schema.FormatSemver,
+							Description: "The model version",
+						},
+						"size": {
+							Type:     schema.TypeInteger,
+							Minimum:  func(f float64) *float64 { return &f }(0),
+							Optional: true,
+						},
+						"stage": {
+							Type:    schema.TypeString,
+							Enum:    []interface{}{"dev", "prod"},
+							Default: "dev",
+						},
 					},
 				},
 			},
@@ -147,3 +188,161 @@ func TestReadSchemaConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestReadRegistryConfig(t *testing.T) {
+	type spec struct {
+		name     string
+		path     string
+		exp      v1alpha1.RegistryConfiguration
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/ValidConfig",
+			path: "testdata/valid-registries.yaml",
+			exp: v1alpha1.RegistryConfiguration{
+				TypeMeta: v1alpha1.TypeMeta{
+					Kind:       v1alpha1.RegistryConfigurationKind,
+					APIVersion: v1alpha1.GroupVersion,
+				},
+				Registries: []v1alpha1.Registry{
+					{
+						Location: "registry.example.com",
+						Mirrors: []v1alpha1.Mirror{
+							{
+								Location: "mirror.us-east.example.com",
+							},
+							{
+								Location:       "mirror.local:5000/example",
+								PullFromMirror: v1alpha1.PullFromMirrorDigestOnly,
+								PlainHTTP:      true,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "Failure/InvalidConfig",
+			path:     "testdata/valid-ds.yaml",
+			expError: "config kind DataSetConfiguration, does not match expected RegistryConfiguration",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg, err := ReadRegistryConfig(c.path)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.exp, cfg)
+			}
+		})
+	}
+}
+
+func TestReadVerificationPolicy(t *testing.T) {
+	type spec struct {
+		name     string
+		path     string
+		exp      v1alpha1.VerificationPolicy
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/ValidConfig",
+			path: "testdata/valid-verification-policy.yaml",
+			exp: v1alpha1.VerificationPolicy{
+				TypeMeta: v1alpha1.TypeMeta{
+					Kind:       v1alpha1.VerificationPolicyKind,
+					APIVersion: v1alpha1.GroupVersion,
+				},
+				Policies: []v1alpha1.ReferencePolicy{
+					{
+						Pattern:   "registry.example.com/release/**",
+						Mode:      v1alpha1.VerificationModeEnforce,
+						Threshold: 2,
+						Authorities: []v1alpha1.Authority{
+							{
+								Name:      "release-key",
+								PublicKey: "/etc/emporous/keys/release.pub",
+							},
+							{
+								Name: "release-workflow",
+								Keyless: &v1alpha1.KeylessAuthority{
+									Subject: "https://github.com/example/app/.github/workflows/release.yaml@refs/heads/main",
+									Issuer:  "https://token.actions.githubusercontent.com",
+								},
+							},
+						},
+					},
+					{
+						Pattern: "localhost:5000/**",
+						Mode:    v1alpha1.VerificationModeSkip,
+					},
+				},
+			},
+		},
+		{
+			name:     "Failure/InvalidConfig",
+			path:     "testdata/valid-registries.yaml",
+			expError: "config kind RegistryConfiguration, does not match expected VerificationPolicy",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg, err := ReadVerificationPolicy(c.path)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.exp, cfg)
+			}
+		})
+	}
+}
+
+func TestReadLicensePolicy(t *testing.T) {
+	type spec struct {
+		name     string
+		path     string
+		exp      v1alpha1.LicensePolicy
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/ValidConfig",
+			path: "testdata/valid-license-policy.yaml",
+			exp: v1alpha1.LicensePolicy{
+				TypeMeta: v1alpha1.TypeMeta{
+					Kind:       v1alpha1.LicensePolicyKind,
+					APIVersion: v1alpha1.GroupVersion,
+				},
+				Allow: []string{"Apache-2.0", "MIT", "BSD-*"},
+				Deny:  []string{"BSD-4-Clause"},
+			},
+		},
+		{
+			name:     "Failure/InvalidConfig",
+			path:     "testdata/valid-verification-policy.yaml",
+			expError: "config kind VerificationPolicy, does not match expected LicensePolicy",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg, err := ReadLicensePolicy(c.path)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.exp, cfg)
+			}
+		})
+	}
+}
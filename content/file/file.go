@@ -0,0 +1,200 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	orascontent "oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/errdef"
+
+	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+)
+
+var _ content.Store = &Store{}
+
+// Store implements the storage interface by wrapping the oras
+// file store. Named blobs are written to the path recorded in the
+// title annotation and the file information recorded in the
+// core-file property is restored after the content is written.
+// Only files written by the store are modified.
+type Store struct {
+	*file.Store
+	// PreserveOwner controls whether the recorded UID and GID
+	// are applied to the written files.
+	PreserveOwner bool
+	workingDir    string
+	// files stores the paths of the written files.
+	mu    sync.Mutex
+	files map[string]struct{}
+}
+
+// New initializes a new file store rooted at the working directory.
+func New(workingDir string) *Store {
+	store := file.New(workingDir)
+	// Files with duplicate content are restored by the Store, so
+	// every written file is recorded.
+	store.ForceCAS = true
+	return &Store{
+		Store:      store,
+		workingDir: workingDir,
+		files:      map[string]struct{}{},
+	}
+}
+
+// Push pushes the content, matching the expected descriptor, and
+// restores the recorded file information on the written file.
+func (s *Store) Push(ctx context.Context, expected ocispec.Descriptor, content io.Reader) error {
+	if err := s.push(ctx, expected, content); err != nil {
+		return err
+	}
+	return s.restoreDuplicates(ctx, expected)
+}
+
+// push writes the content and restores the recorded file
+// information if the descriptor is named.
+func (s *Store) push(ctx context.Context, expected ocispec.Descriptor, content io.Reader) error {
+	name := expected.Annotations[ocispec.AnnotationTitle]
+	if name == "" {
+		return s.Store.Push(ctx, expected, content)
+	}
+
+	path, err := s.targetPath(name)
+	if err != nil {
+		return fmt.Errorf("file %q: %w", name, err)
+	}
+	if err := s.Store.Push(ctx, expected, content); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.files[path] = struct{}{}
+	s.mu.Unlock()
+	return s.restore(path, expected)
+}
+
+// restoreDuplicates writes the successor files with the same content as a
+// written file, but a different name. Successors with content that was not
+// written (e.g. filtered by attributes) are skipped.
+func (s *Store) restoreDuplicates(ctx context.Context, desc ocispec.Descriptor) error {
+	successors, err := orascontent.Successors(ctx, s.Store, desc)
+	if err != nil {
+		return err
+	}
+	for _, successor := range successors {
+		name := successor.Annotations[ocispec.AnnotationTitle]
+		if name == "" || s.written(name) {
+			continue
+		}
+		if err := func() error {
+			rc, err := s.Store.Fetch(ctx, ocispec.Descriptor{
+				MediaType: successor.MediaType,
+				Digest:    successor.Digest,
+				Size:      successor.Size,
+			})
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+			return s.push(ctx, successor, rc)
+		}(); err != nil && !errors.Is(err, errdef.ErrNotFound) {
+			return fmt.Errorf("file %q: error restoring duplicate content: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// written returns whether the file with the name was written by the store.
+func (s *Store) written(name string) bool {
+	path, err := s.targetPath(name)
+	if err != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.files[path]
+	return ok
+}
+
+// restore applies the permissions and ownership recorded in the
+// descriptor properties to the written file at the path.
+func (s *Store) restore(path string, desc ocispec.Descriptor) error {
+	name := desc.Annotations[ocispec.AnnotationTitle]
+	node, err := v2.NewNode(desc.Digest.String(), desc)
+	if err != nil {
+		return fmt.Errorf("file %q: %w", name, err)
+	}
+	if !node.Properties.HasFileInfo() {
+		return nil
+	}
+
+	// Symlinks are not followed so only the written file is modified.
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return fmt.Errorf("file %q: %w", name, err)
+	}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		return nil
+	}
+
+	info := node.Properties.File
+	if info.Permissions != 0 {
+		if err := os.Chmod(path, os.FileMode(info.Permissions).Perm()); err != nil {
+			return fmt.Errorf("file %q: error setting permissions: %w", name, err)
+		}
+	}
+
+	if s.PreserveOwner && (info.UID != -1 || info.GID != -1) {
+		if err := os.Lchown(path, info.UID, info.GID); err != nil {
+			return fmt.Errorf("file %q: error setting ownership: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Files returns the sorted paths of the files written to the working directory.
+func (s *Store) Files() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files := make([]string, 0, len(s.files))
+	for path := range s.files {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// targetPath returns the location of the file in the working directory. Absolute
+// names and names outside the working directory are rejected unless
+// AllowPathTraversalOnWrite is set.
+func (s *Store) targetPath(name string) (string, error) {
+	path := name
+	if !filepath.IsAbs(name) {
+		path = filepath.Join(s.workingDir, name)
+	}
+	if s.AllowPathTraversalOnWrite {
+		return path, nil
+	}
+
+	base, err := filepath.Abs(s.workingDir)
+	if err != nil {
+		return "", err
+	}
+	target, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(base, target)
+	if err != nil || filepath.IsAbs(name) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", file.ErrPathTraversalDisallowed
+	}
+	return path, nil
+}
@@ -0,0 +1,189 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content"
+)
+
+func TestPush(t *testing.T) {
+	type spec struct {
+		name          string
+		title         string
+		properties    string
+		preserveOwner bool
+		expMode       os.FileMode
+		expError      string
+	}
+
+	cases := []spec{
+		{
+			name:       "Success/RestorePermissions",
+			title:      "hello.txt",
+			properties: `{"core-file":{"permissions":448,"uid":-1,"gid":-1}}`,
+			expMode:    0700,
+		},
+		{
+			name:       "Success/NestedTargetPath",
+			title:      "subdir/hello.txt",
+			properties: `{"core-file":{"permissions":292,"uid":-1,"gid":-1}}`,
+			expMode:    0444,
+		},
+		{
+			name:          "Success/PreserveOwner",
+			title:         "hello.txt",
+			properties:    `{"core-file":{"permissions":384,"uid":` + strconv.Itoa(os.Getuid()) + `,"gid":` + strconv.Itoa(os.Getgid()) + `}}`,
+			preserveOwner: true,
+			expMode:       0600,
+		},
+		{
+			name:       "Success/NoFileInfo",
+			title:      "hello.txt",
+			properties: `{"converted":{"test":"annotation"}}`,
+		},
+		{
+			name:       "Failure/AbsoluteTitle",
+			title:      "/etc/hello.txt",
+			properties: `{"core-file":{"permissions":511,"uid":-1,"gid":-1}}`,
+			expError:   "file \"/etc/hello.txt\": path traversal disallowed",
+		},
+		{
+			name:       "Failure/PathTraversal",
+			title:      "../hello.txt",
+			properties: `{"core-file":{"permissions":511,"uid":-1,"gid":-1}}`,
+			expError:   "file \"../hello.txt\": path traversal disallowed",
+		},
+		{
+			name:       "Failure/InvalidFileInfo",
+			title:      "hello.txt",
+			properties: `{"core-file":{"permissions":"rwx"}}`,
+			expError:   "parse property key \"core-file\"",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.TODO()
+			tmp := t.TempDir()
+			store := New(tmp)
+			store.PreserveOwner = c.preserveOwner
+			defer store.Close()
+
+			data := []byte("Hello World!\n")
+			desc := content.NewDescriptorFromBytes("application/vnd.oci.image.layer.v1.tar", data)
+			desc.Annotations = map[string]string{
+				ocispec.AnnotationTitle:              c.title,
+				empspec.AnnotationEmporousAttributes: c.properties,
+			}
+
+			err := store.Push(ctx, desc, bytes.NewReader(data))
+			if c.expError != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.expError)
+				return
+			}
+			require.NoError(t, err)
+
+			fi, err := os.Stat(filepath.Join(tmp, c.title))
+			require.NoError(t, err)
+			require.True(t, fi.Mode().IsRegular())
+			require.Equal(t, []string{filepath.Join(tmp, c.title)}, store.Files())
+			// Without file information the mode is determined by the umask.
+			if c.expMode != 0 {
+				require.Equal(t, c.expMode, fi.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestPushManifest(t *testing.T) {
+	ctx := context.TODO()
+	tmp := t.TempDir()
+	store := New(filepath.Join(tmp, "output"))
+	defer store.Close()
+
+	// A file outside the working directory that must not be modified.
+	hostFile := filepath.Join(tmp, "host.txt")
+	require.NoError(t, os.WriteFile(hostFile, []byte("host"), 0600))
+
+	fileInfo := `{"core-file":{"permissions":511,"uid":-1,"gid":-1}}`
+	data := []byte("Hello World!\n")
+	newLayer := func(title string, data []byte) ocispec.Descriptor {
+		desc := content.NewDescriptorFromBytes("application/vnd.oci.image.layer.v1.tar", data)
+		desc.Annotations = map[string]string{
+			ocispec.AnnotationTitle:              title,
+			empspec.AnnotationEmporousAttributes: fileInfo,
+		}
+		return desc
+	}
+	written := newLayer("hello.txt", data)
+	duplicate := newLayer("copy.txt", data)
+	// The layer is filtered and never written. Its title points outside
+	// the working directory.
+	unwritten := newLayer(hostFile, []byte("filtered"))
+
+	require.NoError(t, store.Push(ctx, written, bytes.NewReader(data)))
+
+	config := content.NewDescriptorFromBytes(ocispec.MediaTypeImageConfig, []byte("{}"))
+	require.NoError(t, store.Push(ctx, config, bytes.NewReader([]byte("{}"))))
+	manifestJSON, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    config,
+		Layers:    []ocispec.Descriptor{written, duplicate, unwritten},
+	})
+	require.NoError(t, err)
+	manifest := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, manifestJSON)
+	require.NoError(t, store.Push(ctx, manifest, bytes.NewReader(manifestJSON)))
+
+	require.Equal(t, []string{
+		filepath.Join(tmp, "output", "copy.txt"),
+		filepath.Join(tmp, "output", "hello.txt"),
+	}, store.Files())
+	for _, path := range store.Files() {
+		fi, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0777), fi.Mode().Perm())
+	}
+	fi, err := os.Stat(hostFile)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+}
+
+func TestPushSymlink(t *testing.T) {
+	ctx := context.TODO()
+	tmp := t.TempDir()
+	output := filepath.Join(tmp, "output")
+	require.NoError(t, os.Mkdir(output, 0750))
+	store := New(output)
+	defer store.Close()
+
+	target := filepath.Join(tmp, "target.txt")
+	require.NoError(t, os.WriteFile(target, []byte("target"), 0600))
+	require.NoError(t, os.Symlink(target, filepath.Join(output, "link.txt")))
+
+	data := []byte("Hello World!\n")
+	desc := content.NewDescriptorFromBytes("application/vnd.oci.image.layer.v1.tar", data)
+	desc.Annotations = map[string]string{
+		ocispec.AnnotationTitle:              "link.txt",
+		empspec.AnnotationEmporousAttributes: `{"core-file":{"permissions":511,"uid":-1,"gid":-1}}`,
+	}
+	require.NoError(t, store.Push(ctx, desc, bytes.NewReader(data)))
+
+	fi, err := os.Lstat(filepath.Join(output, "link.txt"))
+	require.NoError(t, err)
+	require.True(t, fi.Mode()&os.ModeSymlink != 0)
+	fi, err = os.Stat(target)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+}
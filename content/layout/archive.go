@@ -0,0 +1,300 @@
+package layout
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	orascontent "oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/model"
+	"github.com/emporous/emporous-go/model/traversal"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+)
+
+// Export writes the content stored for the references to w as an OCI image layout
+// tar archive. If withLinks is set, stored linked collections and the schemas
+// referenced by the collections are included.
+func (l *Layout) Export(ctx context.Context, w io.Writer, withLinks bool, references ...string) error {
+	index := ocispec.Index{
+		Versioned: specs.Versioned{
+			SchemaVersion: 2,
+		},
+	}
+	blobs := map[digest.Digest]ocispec.Descriptor{}
+	seen := map[string]struct{}{}
+
+	for i := 0; i < len(references); i++ {
+		reference := references[i]
+		if _, ok := seen[reference]; ok {
+			continue
+		}
+		seen[reference] = struct{}{}
+
+		desc, err := l.Resolve(ctx, reference)
+		if err != nil {
+			return err
+		}
+		schemas, err := l.collect(ctx, desc, withLinks, blobs)
+		if err != nil {
+			return fmt.Errorf("reference %s: %w", reference, err)
+		}
+
+		// Schemas are only included if they are stored
+		// in the layout.
+		for _, schemaAddress := range schemas {
+			if _, err := l.Resolve(ctx, schemaAddress); err == nil {
+				references = append(references, schemaAddress)
+			}
+		}
+
+		desc.Annotations = map[string]string{ocispec.AnnotationRefName: reference}
+		index.Manifests = append(index.Manifests, desc)
+	}
+
+	tw := tar.NewWriter(w)
+
+	layoutJSON, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, ocispec.ImageLayoutFile, int64(len(layoutJSON)), bytes.NewReader(layoutJSON)); err != nil {
+		return err
+	}
+	indexJSON, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, indexFile, int64(len(indexJSON)), bytes.NewReader(indexJSON)); err != nil {
+		return err
+	}
+
+	// Keep the archive order deterministic.
+	digests := make([]digest.Digest, 0, len(blobs))
+	for dgst := range blobs {
+		digests = append(digests, dgst)
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		return digests[i] < digests[j]
+	})
+
+	for _, dgst := range digests {
+		desc := blobs[dgst]
+		if err := func() error {
+			rc, err := l.Fetch(ctx, desc)
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+			name := path.Join(blobsDir, dgst.Algorithm().String(), dgst.Encoded())
+			return writeTarFile(tw, name, desc.Size, rc)
+		}(); err != nil {
+			return fmt.Errorf("blob %s: %w", dgst, err)
+		}
+	}
+
+	return tw.Close()
+}
+
+// Import reads an OCI image layout tar archive from r into the layout and tags
+// the references recorded in the archive index. Each blob is verified against its
+// digest before it is stored. The imported references are returned.
+func (l *Layout) Import(ctx context.Context, r io.Reader) ([]string, error) {
+	var index *ocispec.Index
+	var layoutFound bool
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("error reading archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(strings.TrimPrefix(hdr.Name, "./"))
+		switch {
+		case name == ocispec.ImageLayoutFile:
+			var imageLayout ocispec.ImageLayout
+			if err := json.NewDecoder(tr).Decode(&imageLayout); err != nil {
+				return nil, fmt.Errorf("failed to decode OCI layout file: %w", err)
+			}
+			if imageLayout.Version != ocispec.ImageLayoutVersion {
+				return nil, errdef.ErrUnsupportedVersion
+			}
+			layoutFound = true
+		case name == indexFile:
+			if err := json.NewDecoder(tr).Decode(&index); err != nil {
+				return nil, fmt.Errorf("failed to decode index: %w", err)
+			}
+		case strings.HasPrefix(name, blobsDir+"/"):
+			parts := strings.Split(name, "/")
+			if len(parts) != 3 {
+				return nil, fmt.Errorf("archive entry %q: invalid blob path", hdr.Name)
+			}
+			dgst := digest.NewDigestFromEncoded(digest.Algorithm(parts[1]), parts[2])
+			if err := dgst.Validate(); err != nil {
+				return nil, fmt.Errorf("archive entry %q: %w", hdr.Name, err)
+			}
+			desc := ocispec.Descriptor{
+				Digest: dgst,
+				Size:   hdr.Size,
+			}
+			if err := l.internal.Push(ctx, desc, tr); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+				return nil, fmt.Errorf("blob %s: %w", dgst, err)
+			}
+		}
+	}
+
+	if !layoutFound {
+		return nil, fmt.Errorf("archive is not an OCI image layout: missing %s", ocispec.ImageLayoutFile)
+	}
+	if index == nil {
+		return nil, fmt.Errorf("archive is not an OCI image layout: missing %s", indexFile)
+	}
+
+	fetcherFn := func(ctx context.Context, desc ocispec.Descriptor) ([]byte, error) {
+		return orascontent.FetchAll(ctx, l, desc)
+	}
+
+	var references []string
+	for _, d := range index.Manifests {
+		exists, err := l.Exists(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("%s: %s: %w", d.Digest, d.MediaType, errdef.ErrNotFound)
+		}
+
+		// Ensure the annotations for the root node of the reference are
+		// from the manifest and not the descriptor in the index manifest.
+		var manifest ocispec.Manifest
+		manifestBytes, err := fetcherFn(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+			return nil, err
+		}
+		root := d
+		root.Annotations = manifest.Annotations
+		if err := l.loadReference(ctx, fetcherFn, root); err != nil {
+			return nil, err
+		}
+
+		reference, ok := d.Annotations[ocispec.AnnotationRefName]
+		if !ok {
+			continue
+		}
+		if err := l.Tag(ctx, d, reference); err != nil {
+			return nil, err
+		}
+		references = append(references, reference)
+	}
+
+	return references, nil
+}
+
+// collect adds all stored blobs reachable from the root descriptor to blobs. If withLinks is set,
+// stored linked collections are traversed and the schema addresses found in the collection configurations
+// are returned.
+func (l *Layout) collect(ctx context.Context, root ocispec.Descriptor, withLinks bool, blobs map[digest.Digest]ocispec.Descriptor) ([]string, error) {
+	l.mu.Lock()
+	rootNode := l.graph.NodeByID(root.Digest.String())
+	l.mu.Unlock()
+	if rootNode == nil {
+		return nil, fmt.Errorf("node %q does not exist in graph", root.Digest)
+	}
+
+	fetcherFn := func(ctx context.Context, desc ocispec.Descriptor) ([]byte, error) {
+		return orascontent.FetchAll(ctx, l, desc)
+	}
+
+	var schemas []string
+	handler := traversal.HandlerFunc(func(ctx context.Context, tracker traversal.Tracker, node model.Node) ([]model.Node, error) {
+		desc, ok := node.(*v2.Node)
+		if !ok {
+			return nil, traversal.ErrSkip
+		}
+		if _, seen := blobs[desc.Descriptor().Digest]; seen {
+			return nil, nil
+		}
+
+		isLink := desc.Properties != nil && desc.Properties.IsALink()
+		if isLink && !withLinks {
+			return nil, nil
+		}
+
+		// Check that the blob actually exists within the file
+		// store. This will filter out blobs in the event that this is a
+		// sparse manifest.
+		exists, err := l.internal.Exists(ctx, desc.Descriptor())
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, nil
+		}
+		blobs[desc.Descriptor().Digest] = desc.Descriptor()
+
+		switch {
+		case isLink:
+			// Linked collections are loaded into the graph as leaf nodes.
+			if err := l.loadReference(ctx, fetcherFn, desc.Descriptor()); err != nil {
+				return nil, err
+			}
+		case withLinks && desc.Descriptor().MediaType == empspec.MediaTypeConfiguration:
+			configBytes, err := fetcherFn(ctx, desc.Descriptor())
+			if err != nil {
+				return nil, err
+			}
+			var config clientapi.DataSetConfiguration
+			// Not all configurations are dataset configurations.
+			if err := json.Unmarshal(configBytes, &config); err == nil && config.Collection.SchemaAddress != "" {
+				schemas = append(schemas, config.Collection.SchemaAddress)
+			}
+		}
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.graph.From(node.ID()), nil
+	})
+
+	if err := traversal.Walk(ctx, handler, rootNode); err != nil {
+		return nil, err
+	}
+	return schemas, nil
+}
+
+// writeTarFile writes a regular file to the tar archive.
+func writeTarFile(tw *tar.Writer, name string, size int64, r io.Reader) error {
+	hdr := &tar.Header{
+		Name:     name,
+		Typeflag: tar.TypeReg,
+		Mode:     0444,
+		Size:     size,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, r)
+	return err
+}
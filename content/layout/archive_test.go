@@ -0,0 +1,129 @@
+package layout
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImport(t *testing.T) {
+	ctx := context.TODO()
+	ref := "localhost:5001/test:latest"
+	source, err := NewWithContext(ctx, copyLayout(t, "testdata/valid", true))
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	require.NoError(t, source.Export(ctx, &archive, true, ref))
+
+	// Exports are reproducible.
+	var second bytes.Buffer
+	require.NoError(t, source.Export(ctx, &second, true, ref))
+	require.Equal(t, archive.Bytes(), second.Bytes())
+
+	destination, err := NewWithContext(ctx, t.TempDir())
+	require.NoError(t, err)
+	refs, err := destination.Import(ctx, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, []string{ref}, refs)
+
+	expDesc, err := source.Resolve(ctx, ref)
+	require.NoError(t, err)
+	desc, err := destination.Resolve(ctx, ref)
+	require.NoError(t, err)
+	require.Equal(t, expDesc.Digest, desc.Digest)
+
+	expDescs, err := source.ResolveAll(ctx, ref)
+	require.NoError(t, err)
+	descs, err := destination.ResolveAll(ctx, ref)
+	require.NoError(t, err)
+	require.ElementsMatch(t, expDescs, descs)
+
+	blobs, err := destination.Blobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, blobs, 6)
+
+	// The imported layout is reloaded from disk with the
+	// references tagged.
+	reloaded, err := NewWithContext(ctx, destination.rootPath)
+	require.NoError(t, err)
+	_, err = reloaded.Resolve(ctx, ref)
+	require.NoError(t, err)
+}
+
+func TestExport(t *testing.T) {
+	ctx := context.TODO()
+	l, err := NewWithContext(ctx, copyLayout(t, "testdata/valid", true))
+	require.NoError(t, err)
+	var archive bytes.Buffer
+	err = l.Export(ctx, &archive, false, "localhost:5001/notexists:latest")
+	require.EqualError(t, err, "descriptor for reference localhost:5001/notexists:latest is not stored")
+}
+
+func TestImport(t *testing.T) {
+	blob := []byte("test")
+	layoutJSON := []byte(`{"imageLayoutVersion":"1.0.0"}`)
+
+	type spec struct {
+		name     string
+		files    map[string][]byte
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Failure/DigestMismatch",
+			files: map[string][]byte{
+				ocispec.ImageLayoutFile: layoutJSON,
+				"index.json":            []byte(`{"schemaVersion":2,"manifests":[]}`),
+				"blobs/sha256/" + digest.FromString("other").Encoded(): blob,
+			},
+			expError: "blob " + digest.FromString("other").String() + ": failed to ingest: mismatched digest",
+		},
+		{
+			name: "Failure/MissingLayoutFile",
+			files: map[string][]byte{
+				"index.json": []byte(`{"schemaVersion":2,"manifests":[]}`),
+			},
+			expError: "archive is not an OCI image layout: missing oci-layout",
+		},
+		{
+			name: "Failure/MissingIndex",
+			files: map[string][]byte{
+				ocispec.ImageLayoutFile: layoutJSON,
+			},
+			expError: "archive is not an OCI image layout: missing index.json",
+		},
+		{
+			name: "Failure/MissingManifest",
+			files: map[string][]byte{
+				ocispec.ImageLayoutFile: layoutJSON,
+				"index.json": []byte(`{"schemaVersion":2,"manifests":[{"mediaType":"application/vnd.oci.image.manifest.v1+json",` +
+					`"digest":"` + digest.FromBytes(blob).String() + `","size":4}]}`),
+			},
+			expError: digest.FromBytes(blob).String() + ": application/vnd.oci.image.manifest.v1+json: not found",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.TODO()
+			var archive bytes.Buffer
+			tw := tar.NewWriter(&archive)
+			for name, data := range c.files {
+				require.NoError(t, writeTarFile(tw, name, int64(len(data)), bytes.NewReader(data)))
+			}
+			require.NoError(t, tw.Close())
+
+			l, err := NewWithContext(ctx, t.TempDir())
+			require.NoError(t, err)
+			_, err = l.Import(ctx, &archive)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), c.expError)
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"sync"
 
 	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
 	"github.com/opencontainers/image-spec/specs-go"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	orascontent "oras.land/oras-go/v2/content"
@@ -33,7 +34,10 @@ var (
 	_ content.AttributeStore = &Layout{}
 )
 
-const indexFile = "index.json"
+const (
+	indexFile = "index.json"
+	blobsDir  = "blobs"
+)
 
 // Layout implements the storage interface by wrapping the oras
 // content.Storage.
@@ -273,6 +277,173 @@ func (l *Layout) Tag(ctx context.Context, desc ocispec.Descriptor, reference str
 	return l.SaveIndex()
 }
 
+// Untag removes a reference from the index. The content referenced
+// is not removed from the layout until GarbageCollect is called.
+func (l *Layout) Untag(_ context.Context, reference string) error {
+	if _, ok := l.resolver.LoadAndDelete(reference); !ok {
+		return &content.ErrNotStored{Reference: reference}
+	}
+	return l.SaveIndex()
+}
+
+// Delete removes the content identified by the descriptor from the
+// layout. Tagged descriptors must be untagged before they can be deleted.
+func (l *Layout) Delete(_ context.Context, desc ocispec.Descriptor) error {
+	var tagged string
+	l.resolver.Range(func(key, value interface{}) bool {
+		if value.(ocispec.Descriptor).Digest == desc.Digest {
+			tagged = key.(string)
+			return false
+		}
+		return true
+	})
+	if tagged != "" {
+		return fmt.Errorf("%s: descriptor is tagged as %s", desc.Digest, tagged)
+	}
+
+	path, err := l.blobPath(desc.Digest)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%s: %s: %w", desc.Digest, desc.MediaType, errdef.ErrNotFound)
+		}
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.graph.RemoveNode(desc.Digest.String())
+	return nil
+}
+
+// Blobs returns descriptors for all blobs stored in the layout. The
+// media type is only set for blobs that are known to the graph.
+func (l *Layout) Blobs(_ context.Context) ([]ocispec.Descriptor, error) {
+	var res []ocispec.Descriptor
+	blobRoot := filepath.Join(l.rootPath, blobsDir)
+	err := filepath.Walk(blobRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == blobRoot {
+				return nil
+			}
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		algorithm := filepath.Base(filepath.Dir(path))
+		dgst := digest.NewDigestFromEncoded(digest.Algorithm(algorithm), info.Name())
+		if err := dgst.Validate(); err != nil {
+			// Skip any files that are not blobs
+			return nil
+		}
+
+		desc := ocispec.Descriptor{
+			Digest: dgst,
+			Size:   info.Size(),
+		}
+		l.mu.Lock()
+		node, ok := l.graph.NodeByID(dgst.String()).(*v2.Node)
+		l.mu.Unlock()
+		if ok {
+			desc.MediaType = node.Descriptor().MediaType
+		}
+		res = append(res, desc)
+		return nil
+	})
+	return res, err
+}
+
+// GarbageCollect removes all blobs that are not reachable from a tagged
+// reference and returns the descriptors of the removed blobs. If dryRun is
+// set, the blobs are returned without being removed.
+func (l *Layout) GarbageCollect(ctx context.Context, dryRun bool) ([]ocispec.Descriptor, error) {
+	reachable := map[digest.Digest]struct{}{}
+
+	var roots []model.Node
+	var resolveErr error
+	l.resolver.Range(func(key, value interface{}) bool {
+		desc := value.(ocispec.Descriptor)
+		reachable[desc.Digest] = struct{}{}
+		l.mu.Lock()
+		root := l.graph.NodeByID(desc.Digest.String())
+		l.mu.Unlock()
+		if root == nil {
+			resolveErr = fmt.Errorf("node %q does not exist in graph", key)
+			return false
+		}
+		roots = append(roots, root)
+		return true
+	})
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+
+	fetcherFn := func(ctx context.Context, desc ocispec.Descriptor) ([]byte, error) {
+		return orascontent.FetchAll(ctx, l, desc)
+	}
+
+	handler := traversal.HandlerFunc(func(ctx context.Context, tracker traversal.Tracker, node model.Node) ([]model.Node, error) {
+		desc, ok := node.(*v2.Node)
+		if !ok {
+			return nil, traversal.ErrSkip
+		}
+		reachable[desc.Descriptor().Digest] = struct{}{}
+
+		// Linked collections are loaded into the graph as leaf nodes. If the
+		// linked content has been stored, load it so its blobs are retained.
+		if desc.Properties != nil && desc.Properties.IsALink() {
+			exists, err := l.internal.Exists(ctx, desc.Descriptor())
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				if err := l.loadReference(ctx, fetcherFn, desc.Descriptor()); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		var successors []model.Node
+		for _, s := range l.graph.From(node.ID()) {
+			if _, seen := reachable[digest.Digest(s.ID())]; !seen {
+				successors = append(successors, s)
+			}
+		}
+		return successors, nil
+	})
+
+	for _, root := range roots {
+		if err := traversal.Walk(ctx, handler, root); err != nil {
+			return nil, err
+		}
+	}
+
+	blobs, err := l.Blobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []ocispec.Descriptor
+	for _, blob := range blobs {
+		if _, ok := reachable[blob.Digest]; ok {
+			continue
+		}
+		if !dryRun {
+			if err := l.Delete(ctx, blob); err != nil {
+				return removed, err
+			}
+		}
+		removed = append(removed, blob)
+	}
+
+	return removed, nil
+}
+
 // Index returns an index manifest object.
 func (l *Layout) Index() (ocispec.Index, error) {
 	return *l.index, nil
@@ -394,6 +565,15 @@ func (l *Layout) validateOCILayoutFile() error {
 	return nil
 }
 
+// blobPath returns the location of a blob in the
+// layout from the given digest.
+func (l *Layout) blobPath(dgst digest.Digest) (string, error) {
+	if err := dgst.Validate(); err != nil {
+		return "", fmt.Errorf("cannot calculate blob path from invalid digest %s: %v", dgst.String(), err)
+	}
+	return filepath.Join(l.rootPath, blobsDir, dgst.Algorithm().String(), dgst.Encoded()), nil
+}
+
 // validateReference ensures the build reference
 // contains a tag component.
 func validateReference(name string) error {
@@ -1,6 +1,7 @@
 package layout
 
 import (
+	"bytes"
 	"context"
 	"io/fs"
 	"io/ioutil"
@@ -10,8 +11,10 @@ import (
 	"testing"
 
 	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/errdef"
 
 	"github.com/emporous/emporous-go/attributes"
 	"github.com/emporous/emporous-go/attributes/matchers"
@@ -364,3 +367,130 @@ func TestAttributeSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestUntag(t *testing.T) {
+	ctx := context.TODO()
+	l, err := NewWithContext(ctx, copyLayout(t, "testdata/valid", true))
+	require.NoError(t, err)
+
+	require.NoError(t, l.Untag(ctx, "localhost:5001/test:latest"))
+	_, err = l.Resolve(ctx, "localhost:5001/test:latest")
+	require.EqualError(t, err, "descriptor for reference localhost:5001/test:latest is not stored")
+
+	ii, err := l.Index()
+	require.NoError(t, err)
+	require.Len(t, ii.Manifests, 0)
+
+	err = l.Untag(ctx, "localhost:5001/test:latest")
+	require.EqualError(t, err, "descriptor for reference localhost:5001/test:latest is not stored")
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.TODO()
+	l, err := NewWithContext(ctx, copyLayout(t, "testdata/valid", true))
+	require.NoError(t, err)
+
+	manifest := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Digest:    "sha256:473f7d69dbc51105aff4bb2f7ec80e27402d2f40c3e9a076e8c773b15969eadf",
+		Size:      1013,
+	}
+	err = l.Delete(ctx, manifest)
+	require.EqualError(t, err, "sha256:473f7d69dbc51105aff4bb2f7ec80e27402d2f40c3e9a076e8c773b15969eadf:"+
+		" descriptor is tagged as localhost:5001/test:latest")
+
+	blob := ocispec.Descriptor{
+		MediaType: "application/json",
+		Digest:    "sha256:5c29ebcf4a3e7ac6dca6dcea98b4fa98de57c4aca65fa0b49989fbeab1dfdf84",
+		Size:      32,
+	}
+	require.NoError(t, l.Delete(ctx, blob))
+	exists, err := l.Exists(ctx, blob)
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.Nil(t, l.graph.NodeByID(blob.Digest.String()))
+
+	err = l.Delete(ctx, blob)
+	require.ErrorIs(t, err, errdef.ErrNotFound)
+}
+
+func TestGarbageCollect(t *testing.T) {
+	ctx := context.TODO()
+	l, err := NewWithContext(ctx, copyLayout(t, "testdata/valid", true))
+	require.NoError(t, err)
+
+	orphan := []byte("orphan")
+	orphanDesc := ocispec.Descriptor{
+		MediaType: "text/plain",
+		Digest:    digest.FromBytes(orphan),
+		Size:      int64(len(orphan)),
+	}
+	require.NoError(t, l.Push(ctx, orphanDesc, bytes.NewReader(orphan)))
+
+	removed, err := l.GarbageCollect(ctx, true)
+	require.NoError(t, err)
+	require.Equal(t, []ocispec.Descriptor{orphanDesc}, removed)
+	exists, err := l.Exists(ctx, orphanDesc)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	removed, err = l.GarbageCollect(ctx, false)
+	require.NoError(t, err)
+	require.Equal(t, []ocispec.Descriptor{orphanDesc}, removed)
+	exists, err = l.Exists(ctx, orphanDesc)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, l.Untag(ctx, "localhost:5001/test:latest"))
+	removed, err = l.GarbageCollect(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, removed, 6)
+	blobs, err := l.Blobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, blobs, 0)
+}
+
+// copyLayout copies the layout at source into a temporary directory
+// and returns the location. The index file is only copied if withIndex is set.
+func copyLayout(t *testing.T, source string, withIndex bool) string {
+	cacheDir := t.TempDir()
+	err := filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !withIndex && info.Name() == indexFile {
+			return nil
+		}
+		relPath := strings.Replace(path, source, "", 1)
+		if relPath == "" {
+			return nil
+		}
+		switch m := info.Mode(); {
+		case m&fs.ModeSymlink != 0:
+			dst, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			id := filepath.Base(dst)
+			if err := os.Symlink(id, filepath.Join(cacheDir, relPath)); err != nil {
+				return err
+			}
+		case m.IsDir():
+			return os.Mkdir(filepath.Join(cacheDir, relPath), 0750)
+		default:
+			newSource := filepath.Join(source, relPath)
+			cleanSource := filepath.Clean(newSource)
+			data, err := ioutil.ReadFile(cleanSource)
+			if err != nil {
+				return err
+			}
+			newDest := filepath.Join(cacheDir, relPath)
+			cleanDest := filepath.Clean(newDest)
+			return ioutil.WriteFile(cleanDest, data, 0600)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	return cacheDir
+}
@@ -0,0 +1,153 @@
+package license
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SPDX license expression operators.
+const (
+	opAnd  = "AND"
+	opOr   = "OR"
+	opWith = "WITH"
+)
+
+// expression is a parsed SPDX license expression. It is either
+// a license with an optional exception or a conjunction or
+// disjunction of its operands.
+type expression struct {
+	op        string
+	license   string
+	exception string
+	operands  []*expression
+}
+
+// evaluate returns whether the expression is satisfied when the
+// licenses are allowed per the allowed function. An AND expression
+// requires all operands and an OR expression requires at least one operand
+// to be satisfied. Exceptions do not change whether a license is allowed.
+func (e *expression) evaluate(allowed func(license string) bool) bool {
+	switch e.op {
+	case opAnd:
+		for _, operand := range e.operands {
+			if !operand.evaluate(allowed) {
+				return false
+			}
+		}
+		return true
+	case opOr:
+		for _, operand := range e.operands {
+			if operand.evaluate(allowed) {
+				return true
+			}
+		}
+		return false
+	default:
+		return allowed(e.license)
+	}
+}
+
+// parseExpression parses an SPDX license expression
+// (e.g. "MIT OR (Apache-2.0 AND GPL-2.0-only WITH Classpath-exception-2.0)").
+// Operators are matched case-insensitively and AND binds tighter than OR.
+func parseExpression(value string) (*expression, error) {
+	p := &parser{tokens: tokenize(value)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("license expression %q: empty expression", value)
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("license expression %q: %w", value, err)
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("license expression %q: unexpected %q", value, p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+// tokenize splits an expression into parentheses and words.
+func tokenize(value string) []string {
+	value = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(value)
+	return strings.Fields(value)
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *parser) parseOr() (*expression, error) {
+	return p.parseBinary(opOr, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (*expression, error) {
+	return p.parseBinary(opAnd, p.parseTerm)
+}
+
+// parseBinary parses operands separated by the operator.
+func (p *parser) parseBinary(op string, parseOperand func() (*expression, error)) (*expression, error) {
+	operand, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []*expression{operand}
+	for strings.EqualFold(p.peek(), op) {
+		p.next()
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return operand, nil
+	}
+	return &expression{op: op, operands: operands}, nil
+}
+
+func (p *parser) parseTerm() (*expression, error) {
+	token := p.next()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case token == "(":
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return expr, nil
+	case token == ")" || isOperator(token):
+		return nil, fmt.Errorf("unexpected %q", token)
+	}
+
+	expr := &expression{license: token}
+	if strings.EqualFold(p.peek(), opWith) {
+		p.next()
+		exception := p.next()
+		if exception == "" || exception == "(" || exception == ")" || isOperator(exception) {
+			return nil, fmt.Errorf("missing exception after %s", opWith)
+		}
+		expr.exception = exception
+	}
+	return expr, nil
+}
+
+func isOperator(token string) bool {
+	return strings.EqualFold(token, opAnd) || strings.EqualFold(token, opOr) || strings.EqualFold(token, opWith)
+}
@@ -0,0 +1,165 @@
+package license
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/nodes/collection"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+)
+
+// Policy checks the licenses of collection components
+// against a license policy.
+type Policy struct {
+	allow []string
+	deny  []string
+}
+
+// NewPolicy returns a Policy for the license policy configuration.
+func NewPolicy(policy clientapi.LicensePolicy) (*Policy, error) {
+	p := &Policy{}
+	for _, license := range policy.Allow {
+		if _, err := path.Match(license, ""); err != nil {
+			return nil, fmt.Errorf("allowed license %q: %w", license, err)
+		}
+		p.allow = append(p.allow, strings.ToLower(license))
+	}
+	for _, license := range policy.Deny {
+		if _, err := path.Match(license, ""); err != nil {
+			return nil, fmt.Errorf("denied license %q: %w", license, err)
+		}
+		p.deny = append(p.deny, strings.ToLower(license))
+	}
+	return p, nil
+}
+
+// Allowed returns whether the SPDX license expression is allowed
+// by the policy.
+func (p *Policy) Allowed(value string) (bool, error) {
+	expr, err := parseExpression(value)
+	if err != nil {
+		return false, err
+	}
+	return expr.evaluate(p.allowedLicense), nil
+}
+
+// allowedLicense returns whether a license identifier is not denied and, if
+// allowed licenses are set, is allowed.
+func (p *Policy) allowedLicense(license string) bool {
+	license = strings.ToLower(license)
+	if matchAny(p.deny, license) {
+		return false
+	}
+	return len(p.allow) == 0 || matchAny(p.allow, license)
+}
+
+func matchAny(patterns []string, license string) bool {
+	for _, pattern := range patterns {
+		// Patterns are validated when the policy is created.
+		if matched, _ := path.Match(pattern, license); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Violation is a component with a license
+// expression that is not allowed.
+type Violation struct {
+	// Locations are the files of the component.
+	Locations []string
+	// Component is the name of the component.
+	Component string
+	// License is the license expression.
+	License string
+	// Reason describes why the license expression is not allowed.
+	Reason string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s (component %s): %s", strings.Join(v.Locations, ", "), v.Component, v.Reason)
+}
+
+// ViolationError is returned when components of
+// a collection violate the license policy.
+type ViolationError struct {
+	Reference  string
+	Violations []Violation
+}
+
+func (e *ViolationError) Error() string {
+	var violations []string
+	for _, violation := range e.Violations {
+		violations = append(violations, violation.String())
+	}
+	return fmt.Sprintf("collection %q violates the license policy: %s", e.Reference, strings.Join(violations, "; "))
+}
+
+// CheckComponent checks the licenses of the component of a collection.
+// The component locations are reported for violations.
+func (p *Policy) CheckComponent(reference string, component empspec.Component) error {
+	return p.check(reference, p.violations(component, nil))
+}
+
+// CheckCollection checks the licenses of the components in the core-descriptor
+// attributes of the collection manifest and files. The file locations and the
+// component locations are reported for violations.
+func (p *Policy) CheckCollection(reference string, co collection.Collection) error {
+	var violations []Violation
+	for _, n := range co.Nodes() {
+		node, ok := n.(*v2.Node)
+		if !ok || node.Properties == nil || !node.Properties.IsAComponent() || node.Properties.IsALink() {
+			continue
+		}
+		var locations []string
+		if title, ok := node.Descriptor().Annotations[ocispec.AnnotationTitle]; ok {
+			locations = append(locations, title)
+		}
+		violations = append(violations, p.violations(node.Properties.Descriptor.Component, locations)...)
+	}
+	return p.check(reference, violations)
+}
+
+func (p *Policy) violations(component empspec.Component, locations []string) []Violation {
+	locations = append(locations, component.Locations...)
+	if len(locations) == 0 {
+		locations = []string{"collection manifest"}
+	}
+
+	var violations []Violation
+	for _, license := range component.Licenses {
+		violation := Violation{
+			Locations: locations,
+			Component: component.Name,
+			License:   license,
+		}
+		allowed, err := p.Allowed(license)
+		switch {
+		case err != nil:
+			violation.Reason = err.Error()
+		case !allowed:
+			violation.Reason = fmt.Sprintf("license %q is not allowed", license)
+		default:
+			continue
+		}
+		violations = append(violations, violation)
+	}
+	return violations
+}
+
+func (p *Policy) check(reference string, violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	// Keep the reported order deterministic
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Locations[0] < violations[j].Locations[0]
+	})
+	return &ViolationError{Reference: reference, Violations: violations}
+}
@@ -0,0 +1,223 @@
+package license
+
+import (
+	"encoding/json"
+	"testing"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/nodes/collection"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+)
+
+func TestAllowed(t *testing.T) {
+	type spec struct {
+		name       string
+		policy     clientapi.LicensePolicy
+		expression string
+		exp        bool
+		expError   string
+	}
+
+	allowList := clientapi.LicensePolicy{Allow: []string{"MIT", "Apache-2.0", "LicenseRef-*"}}
+	denyList := clientapi.LicensePolicy{Deny: []string{"GPL-*", "AGPL-3.0-only"}}
+
+	cases := []spec{
+		{
+			name:       "Success/Allowed",
+			policy:     allowList,
+			expression: "MIT",
+			exp:        true,
+		},
+		{
+			name:       "Success/AllowedCaseInsensitive",
+			policy:     allowList,
+			expression: "apache-2.0",
+			exp:        true,
+		},
+		{
+			name:       "Success/AllowedWildcard",
+			policy:     allowList,
+			expression: "LicenseRef-internal",
+			exp:        true,
+		},
+		{
+			name:       "Success/NotAllowed",
+			policy:     allowList,
+			expression: "BSD-3-Clause",
+		},
+		{
+			name:       "Success/OrOneAllowed",
+			policy:     allowList,
+			expression: "GPL-2.0-only OR MIT",
+			exp:        true,
+		},
+		{
+			name:       "Success/AndOneNotAllowed",
+			policy:     allowList,
+			expression: "MIT AND BSD-3-Clause",
+		},
+		{
+			name:       "Success/AndBindsTighter",
+			policy:     allowList,
+			expression: "MIT AND BSD-3-Clause OR Apache-2.0",
+			exp:        true,
+		},
+		{
+			name:       "Success/Parentheses",
+			policy:     allowList,
+			expression: "MIT AND (BSD-3-Clause OR Apache-2.0)",
+			exp:        true,
+		},
+		{
+			name:       "Success/Denied",
+			policy:     denyList,
+			expression: "GPL-3.0-or-later",
+		},
+		{
+			name:       "Success/DeniedWithException",
+			policy:     denyList,
+			expression: "GPL-2.0-only WITH Classpath-exception-2.0",
+		},
+		{
+			name:       "Success/NotDenied",
+			policy:     denyList,
+			expression: "AGPL-3.0-only or mit",
+			exp:        true,
+		},
+		{
+			name:       "Success/DenyOverridesAllow",
+			policy:     clientapi.LicensePolicy{Allow: []string{"*"}, Deny: []string{"SSPL-1.0"}},
+			expression: "SSPL-1.0",
+		},
+		{
+			name:       "Failure/MissingOperand",
+			policy:     allowList,
+			expression: "MIT AND",
+			expError:   "license expression \"MIT AND\": unexpected end of expression",
+		},
+		{
+			name:       "Failure/MissingParenthesis",
+			policy:     allowList,
+			expression: "(MIT OR Apache-2.0",
+			expError:   "license expression \"(MIT OR Apache-2.0\": missing closing parenthesis",
+		},
+		{
+			name:       "Failure/MissingOperator",
+			policy:     allowList,
+			expression: "MIT Apache-2.0",
+			expError:   "license expression \"MIT Apache-2.0\": unexpected \"Apache-2.0\"",
+		},
+		{
+			name:       "Failure/MissingException",
+			policy:     allowList,
+			expression: "MIT WITH",
+			expError:   "license expression \"MIT WITH\": missing exception after WITH",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			policy, err := NewPolicy(c.policy)
+			require.NoError(t, err)
+			allowed, err := policy.Allowed(c.expression)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.exp, allowed)
+			}
+		})
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	_, err := NewPolicy(clientapi.LicensePolicy{Allow: []string{"MIT["}})
+	require.EqualError(t, err, "allowed license \"MIT[\": syntax error in pattern")
+}
+
+func TestCheckComponent(t *testing.T) {
+	policy, err := NewPolicy(clientapi.LicensePolicy{Deny: []string{"GPL-3.0-only"}})
+	require.NoError(t, err)
+
+	err = policy.CheckComponent("localhost:5001/test:latest", empspec.Component{
+		Name:     "app",
+		Licenses: []string{"MIT"},
+	})
+	require.NoError(t, err)
+
+	err = policy.CheckComponent("localhost:5001/test:latest", empspec.Component{
+		Name:      "app",
+		Locations: []string{"bin/app"},
+		Licenses:  []string{"MIT", "GPL-3.0-only"},
+	})
+	require.EqualError(t, err, "collection \"localhost:5001/test:latest\" violates the license policy: "+
+		"bin/app (component app): license \"GPL-3.0-only\" is not allowed")
+}
+
+func TestCheckCollection(t *testing.T) {
+	policy, err := NewPolicy(clientapi.LicensePolicy{Allow: []string{"MIT", "Apache-2.0"}})
+	require.NoError(t, err)
+
+	node := func(content string, annotations map[string]string, component *empspec.Component) *v2.Node {
+		if component != nil {
+			attr, err := json.Marshal(map[string]interface{}{
+				"core-descriptor": empspec.DescriptorAttributes{Component: *component},
+			})
+			require.NoError(t, err)
+			annotations[empspec.AnnotationEmporousAttributes] = string(attr)
+		}
+		desc := ocispec.Descriptor{
+			MediaType:   "application/octet-stream",
+			Digest:      digest.FromString(content),
+			Size:        int64(len(content)),
+			Annotations: annotations,
+		}
+		n, err := v2.NewNode(desc.Digest.String(), desc)
+		require.NoError(t, err)
+		return n
+	}
+
+	co := collection.New("localhost:5001/test:latest")
+	nodes := []*v2.Node{
+		node("manifest", map[string]string{}, &empspec.Component{
+			Name:     "app",
+			Licenses: []string{"Apache-2.0"},
+		}),
+		node("lib", map[string]string{ocispec.AnnotationTitle: "lib/libfoo.so"}, &empspec.Component{
+			Name:     "foo",
+			Licenses: []string{"MIT AND GPL-2.0-only"},
+		}),
+		node("vendor", map[string]string{ocispec.AnnotationTitle: "vendor/bar.tar"}, &empspec.Component{
+			Name:      "bar",
+			Locations: []string{"vendor/bar/LICENSE"},
+			Licenses:  []string{"MIT OR"},
+		}),
+		node("readme", map[string]string{ocispec.AnnotationTitle: "README.md"}, nil),
+	}
+	for _, n := range nodes {
+		require.NoError(t, co.AddNode(n))
+	}
+
+	err = policy.CheckCollection("localhost:5001/test:latest", *co)
+	var violationErr *ViolationError
+	require.ErrorAs(t, err, &violationErr)
+	require.Equal(t, []Violation{
+		{
+			Locations: []string{"lib/libfoo.so"},
+			Component: "foo",
+			License:   "MIT AND GPL-2.0-only",
+			Reason:    "license \"MIT AND GPL-2.0-only\" is not allowed",
+		},
+		{
+			Locations: []string{"vendor/bar.tar", "vendor/bar/LICENSE"},
+			Component: "bar",
+			License:   "MIT OR",
+			Reason:    "license expression \"MIT OR\": unexpected end of expression",
+		},
+	}, violationErr.Violations)
+}
@@ -3,6 +3,7 @@ package defaultmanager
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -50,7 +51,6 @@ func (d DefaultManager) Build(ctx context.Context, space workspace.Workspace, co
 		return "", fmt.Errorf("path %q empty workspace", space.Path("."))
 	}
 
-	var sets []model.AttributeSet
 	regexpByFilename := map[string]*regexp.Regexp{}
 	fileInfoByName := map[string]fileInformation{}
 	for _, file := range config.Collection.Files {
@@ -74,7 +74,6 @@ func (d DefaultManager) Build(ctx context.Context, space workspace.Workspace, co
 		if err != nil {
 			return "", err
 		}
-		sets = append(sets, set)
 
 		fileInfo := fileInformation{
 			AttributeSet: set,
@@ -84,11 +83,23 @@ func (d DefaultManager) Build(ctx context.Context, space workspace.Workspace, co
 		fileInfoByName[file.File] = fileInfo
 	}
 
-	// Merge the sets to ensure the dataset configuration
-	// meet the schema require.
-	mergedSet, err := attributes.Merge(sets...)
-	if err != nil {
-		return "", fmt.Errorf("failed to merge attributes: %w", err)
+	// resolveFile returns the attribute sets and file information
+	// configured for a file location.
+	resolveFile := func(location string) ([]model.AttributeSet, []empspec.File) {
+		var sets []model.AttributeSet
+		var fileConfig []empspec.File
+		for file, fileInfo := range fileInfoByName {
+			nameSearch := regexpByFilename[file]
+			if nameSearch.Match([]byte(location)) {
+				if fileInfo.HasAttributes() {
+					sets = append(sets, fileInfo.AttributeSet)
+				}
+				if fileInfo.HasFileInfo() {
+					fileConfig = append(fileConfig, fileInfo.File)
+				}
+			}
+		}
+		return sets, fileConfig
 	}
 
 	// If a schema is present, pull it and do the validation before
@@ -112,12 +123,34 @@ func (d DefaultManager) Build(ctx context.Context, space workspace.Workspace, co
 			schemaID = detectedSchemaID
 		}
 
-		valid, err := schemaDoc.Validate(mergedSet)
-		if err != nil {
-			return "", fmt.Errorf("schema validation error: %w", err)
+		// Validate the attributes of each file individually, so a key required
+		// by the schema cannot be satisfied by the attributes of another file.
+		// Files without attributes are not validated.
+		validationErr := &schema.ValidationError{}
+		for _, file := range files {
+			sets, _ := resolveFile(file)
+			if len(sets) == 0 {
+				continue
+			}
+			merged, err := attributes.Merge(sets...)
+			if err != nil {
+				return "", fmt.Errorf("file %s: %w", file, err)
+			}
+			_, err = schemaDoc.Validate(merged)
+			var fileErr *schema.ValidationError
+			switch {
+			case errors.As(err, &fileErr):
+				for _, violation := range fileErr.Violations {
+					violation.Location = file
+					validationErr.Violations = append(validationErr.Violations, violation)
+				}
+			case err != nil:
+				return "", fmt.Errorf("schema validation error: %w", err)
+			}
 		}
-		if !valid {
-			return "", fmt.Errorf("attributes are not valid for schema %s", config.Collection.SchemaAddress)
+		if len(validationErr.Violations) != 0 {
+			validationErr.Sort()
+			return "", fmt.Errorf("attributes are not valid for schema %s: %w", config.Collection.SchemaAddress, validationErr)
 		}
 	}
 
@@ -165,20 +198,7 @@ func (d DefaultManager) Build(ctx context.Context, space workspace.Workspace, co
 			return nil
 		}
 
-		var sets []model.AttributeSet
-		var fileConfig []empspec.File
-		for file, fileInfo := range fileInfoByName {
-			nameSearch := regexpByFilename[file]
-			if nameSearch.Match([]byte(node.Location)) {
-				if fileInfo.HasAttributes() {
-					sets = append(sets, fileInfo.AttributeSet)
-				}
-				if fileInfo.HasFileInfo() {
-					fileConfig = append(fileConfig, fileInfo.File)
-				}
-			}
-		}
-
+		sets, fileConfig := resolveFile(node.Location)
 		switch {
 		case len(fileConfig) == 1:
 			node.Properties.File = &fileConfig[0]
@@ -188,7 +208,7 @@ func (d DefaultManager) Build(ctx context.Context, space workspace.Workspace, co
 
 		merged, err := attributes.Merge(sets...)
 		if err != nil {
-			return err
+			return fmt.Errorf("file %s: %w", node.Location, err)
 		}
 		if err := node.Properties.Merge(map[string]model.AttributeSet{schemaID: merged}); err != nil {
 			return fmt.Errorf("file %s: %w", node.Location, err)
@@ -0,0 +1,29 @@
+package defaultmanager
+
+import (
+	"context"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/emporous/emporous-go/registryclient"
+)
+
+// Copy copies a collection from a source remote location to a destination remote location.
+// If followLinks is set, linked collections are copied to the destination and the links
+// are rewritten. If successful, the root descriptor is returned.
+func (d DefaultManager) Copy(ctx context.Context, source, destination string, remote registryclient.Remote, followLinks bool) (string, error) {
+	var desc ocispec.Descriptor
+	var err error
+	if followLinks {
+		desc, err = remote.CopyWithLinks(ctx, source, destination)
+	} else {
+		desc, err = remote.Copy(ctx, source, destination)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error copying %s to %s: %w", source, destination, err)
+	}
+
+	d.logger.Infof("Artifact %s copied to %s\n", desc.Digest, destination)
+	return desc.Digest.String(), nil
+}
@@ -40,6 +40,14 @@ func (d DefaultManager) PullAll(ctx context.Context, source string, remote regis
 		return nil, err
 	}
 
+	// Ensure the store is tagged with the new reference. The source
+	// collection is not stored if none of its content matched.
+	if rootDesc, err := destination.Resolve(ctx, source); err == nil {
+		if err := d.store.Tag(ctx, rootDesc, source); err != nil {
+			return nil, err
+		}
+	}
+
 	var digests []string
 	for _, desc := range descs {
 		digests = append(digests, desc.Digest.String())
@@ -25,4 +25,8 @@ type Manager interface {
 	// PullAll is similar to Pull with the exception that it walks a graph of linked collections
 	// starting with the source collection reference.
 	PullAll(ctx context.Context, source string, remote registryclient.Remote, destination content.Store) ([]string, error)
+	// Copy copies a collection from a source remote location to a destination remote location.
+	// If followLinks is set, linked collections are copied to the destination and the links
+	// are rewritten. If successful, the root descriptor is returned.
+	Copy(ctx context.Context, source, destination string, remote registryclient.Remote, followLinks bool) (string, error)
 }
@@ -124,6 +124,11 @@ type Attribute interface {
 	AsFloat() (float64, error)
 	// AsString will return the attribute value as a string.
 	AsString() (string, error)
+	// AsList will return the attribute value as a list of attributes.
+	AsList() ([]Attribute, error)
+	// AsMap will return the attribute value as a map of attributes
+	// keyed by the nested attribute keys.
+	AsMap() (map[string]Attribute, error)
 	// AsAny returns the value of the attribute with no type checking.
 	AsAny() interface{}
 }
@@ -138,6 +143,8 @@ const (
 	KindInt
 	KindFloat
 	KindString
+	KindList
+	KindMap
 )
 
 // String prints a string representation of the attribute kind.
@@ -155,6 +162,10 @@ func (k Kind) String() string {
 		return "int"
 	case KindString:
 		return "string"
+	case KindList:
+		return "list"
+	case KindMap:
+		return "map"
 	default:
 		panic("invalid kind")
 	}
@@ -99,3 +99,25 @@ func TestCollection_SubCollection(t *testing.T) {
 		})
 	}
 }
+
+func TestCollection_RemoveNode(t *testing.T) {
+	c := New("test")
+	node1 := &testutils.FakeNode{I: "node1"}
+	node2 := &testutils.FakeNode{I: "node2"}
+	node3 := &testutils.FakeNode{I: "node3"}
+	for _, n := range []model.Node{node1, node2, node3} {
+		require.NoError(t, c.AddNode(n))
+	}
+	require.NoError(t, c.AddEdge(&Edge{F: node1, T: node2}))
+	require.NoError(t, c.AddEdge(&Edge{F: node2, T: node3}))
+
+	c.RemoveNode("node2")
+	require.Nil(t, c.NodeByID("node2"))
+	require.Len(t, c.From("node1"), 0)
+	require.Len(t, c.To("node3"), 0)
+	require.Len(t, c.Edges(), 0)
+
+	// Removing a node that does not exist is a no-op.
+	c.RemoveNode("node2")
+	require.Len(t, c.Nodes(), 2)
+}
@@ -248,30 +248,10 @@ func Parse(in map[string]json.RawMessage) (*Properties, error) {
 			out.File = &f
 		default:
 			set := attributes.Attributes{}
-			handler := func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) (err error) {
-				valueAsString := string(value)
-				keyAsString := string(key)
-				var attr model.Attribute
-				switch dataType {
-				case jsonparser.String:
-					attr = attributes.NewString(keyAsString, valueAsString)
-				case jsonparser.Number:
-					// Using float for number like the standard lib
-					floatVal, err := strconv.ParseFloat(valueAsString, 64)
-					if err != nil {
-						return err
-					}
-					attr = attributes.NewFloat(keyAsString, floatVal)
-				case jsonparser.Boolean:
-					boolVal, err := strconv.ParseBool(valueAsString)
-					if err != nil {
-						return err
-					}
-					attr = attributes.NewBool(keyAsString, boolVal)
-				case jsonparser.Null:
-					attr = attributes.NewNull(keyAsString)
-				default:
-					return ParseError{Key: keyAsString, Err: errors.New("unsupported attribute type")}
+			handler := func(key []byte, value []byte, dataType jsonparser.ValueType, offset int) error {
+				attr, err := parseAttribute(string(key), value, dataType)
+				if err != nil {
+					return err
 				}
 				set[attr.Key()] = attr
 				return nil
@@ -288,3 +268,66 @@ func Parse(in map[string]json.RawMessage) (*Properties, error) {
 	out.Others = other
 	return &out, result.ErrorOrNil()
 }
+
+// parseAttribute converts a JSON value into an attribute. Arrays and
+// objects are converted recursively into list and map attributes.
+func parseAttribute(key string, value []byte, dataType jsonparser.ValueType) (model.Attribute, error) {
+	valueAsString := string(value)
+	switch dataType {
+	case jsonparser.String:
+		return attributes.NewString(key, valueAsString), nil
+	case jsonparser.Number:
+		// Using float for number like the standard lib
+		floatVal, err := strconv.ParseFloat(valueAsString, 64)
+		if err != nil {
+			return nil, err
+		}
+		return attributes.NewFloat(key, floatVal), nil
+	case jsonparser.Boolean:
+		boolVal, err := strconv.ParseBool(valueAsString)
+		if err != nil {
+			return nil, err
+		}
+		return attributes.NewBool(key, boolVal), nil
+	case jsonparser.Null:
+		return attributes.NewNull(key), nil
+	case jsonparser.Array:
+		// List elements share the key of the list
+		values := []model.Attribute{}
+		var elemErr error
+		_, err := jsonparser.ArrayEach(value, func(elemValue []byte, elemType jsonparser.ValueType, _ int, _ error) {
+			if elemErr != nil {
+				return
+			}
+			elem, err := parseAttribute(key, elemValue, elemType)
+			if err != nil {
+				elemErr = err
+				return
+			}
+			values = append(values, elem)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if elemErr != nil {
+			return nil, elemErr
+		}
+		return attributes.NewList(key, values), nil
+	case jsonparser.Object:
+		values := map[string]model.Attribute{}
+		handler := func(nestedKey []byte, nestedValue []byte, nestedType jsonparser.ValueType, _ int) error {
+			elem, err := parseAttribute(string(nestedKey), nestedValue, nestedType)
+			if err != nil {
+				return err
+			}
+			values[elem.Key()] = elem
+			return nil
+		}
+		if err := jsonparser.ObjectEach(value, handler); err != nil {
+			return nil, err
+		}
+		return attributes.NewMap(key, values), nil
+	default:
+		return nil, ParseError{Key: key, Err: errors.New("unsupported attribute type")}
+	}
+}
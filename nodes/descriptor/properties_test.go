@@ -1,6 +1,7 @@
 package descriptor
 
 import (
+	"encoding/json"
 	"testing"
 
 	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
@@ -32,3 +33,58 @@ func TestProperties_MarshalJSON(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expJSON, string(propsJSON))
 }
+
+func TestParse(t *testing.T) {
+	type spec struct {
+		name     string
+		input    map[string]json.RawMessage
+		assertFn func(t *testing.T, props *Properties)
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/ListAndMap",
+			input: map[string]json.RawMessage{
+				"test": json.RawMessage(`{"tags":["gpu","fp16"],"owner":{"team":"x","members":[]}}`),
+			},
+			assertFn: func(t *testing.T, props *Properties) {
+				set := props.Others["test"]
+				require.NotNil(t, set)
+				exists, err := set.Exists(attributes.NewString("tags", "fp16"))
+				require.NoError(t, err)
+				require.True(t, exists)
+
+				owner := set.Find("owner")
+				require.Equal(t, model.KindMap, owner.Kind())
+				nested, err := owner.AsMap()
+				require.NoError(t, err)
+				require.Len(t, nested, 2)
+				require.Equal(t, model.KindList, nested["members"].Kind())
+
+				setJSON, err := set.MarshalJSON()
+				require.NoError(t, err)
+				require.JSONEq(t, `{"tags":["gpu","fp16"],"owner":{"team":"x","members":[]}}`, string(setJSON))
+			},
+		},
+		{
+			name: "Failure/InvalidNestedValue",
+			input: map[string]json.RawMessage{
+				"test": json.RawMessage(`{"owner":{"team":tru}}`),
+			},
+			expError: "key test",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			props, err := Parse(c.input)
+			if c.expError != "" {
+				require.ErrorContains(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				c.assertFn(t, props)
+			}
+		})
+	}
+}
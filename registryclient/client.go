@@ -23,18 +23,41 @@ type Remote interface {
 	// Push pushes an artifact to a remote registry from a source
 	// content store and returns the root manifest digest.
 	Push(context.Context, content.Store, string) (ocispec.Descriptor, error)
+	// PushGraph pushes the content graph rooted at a descriptor from a source content
+	// store to the repository of a remote reference without tagging it.
+	PushGraph(context.Context, content.Store, string, ocispec.Descriptor) error
 	// Pull pulls an artifact from a remote registry to a local
 	// content store. If successful it returns the root descriptor and all the descriptors pulled.
 	Pull(context.Context, string, content.Store) (ocispec.Descriptor, []ocispec.Descriptor, error)
 	// PullWithLinks pulls an artifact from a remote registry to a local
 	// content store and follows all the links. If successful it returns the root descriptor and all the descriptors pulled.
 	PullWithLinks(context.Context, string, content.Store) ([]ocispec.Descriptor, error)
+	// Copy copies an artifact from a remote registry to another remote
+	// location. If successful it returns the root descriptor.
+	Copy(context.Context, string, string) (ocispec.Descriptor, error)
+	// CopyWithLinks copies an artifact from a remote registry to another remote
+	// location and follows all the links. Links are rewritten to the destination.
+	// If successful it returns the root descriptor.
+	CopyWithLinks(context.Context, string, string) (ocispec.Descriptor, error)
 	// GetManifest retrieves the root manifest for a reference.
 	GetManifest(context.Context, string) (ocispec.Descriptor, io.ReadCloser, error)
 	// GetContent retrieves the content for a specified descriptor at a specified reference.
 	GetContent(context.Context, string, ocispec.Descriptor) ([]byte, error)
 	// LoadCollection loads a collection from a remote reference.
 	LoadCollection(context.Context, string) (collection.Collection, error)
+	// Attach pushes an artifact of an artifact type with files and manifest annotations
+	// whose subject is the manifest of a remote reference. If successful it returns the
+	// artifact manifest descriptor.
+	Attach(context.Context, string, string, map[string]string, ...string) (ocispec.Descriptor, error)
+	// Referrers returns the descriptors of the artifacts whose subject is the manifest of
+	// a remote reference. If an artifact type is set, only referrers of that type are returned.
+	Referrers(context.Context, string, string) ([]ocispec.Descriptor, error)
+	// Tags returns the tags of a remote repository starting after the last tag, if set.
+	// If the limit is positive, at most limit tags are returned.
+	Tags(context.Context, string, string, int) ([]string, error)
+	// Repositories returns the repositories of a remote registry starting after the last
+	// repository, if set. If the limit is positive, at most limit repositories are returned.
+	Repositories(context.Context, string, string, int) ([]string, error)
 }
 
 // Local defines methods to interact with OCI artifacts
@@ -0,0 +1,140 @@
+package orasclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content/memory"
+
+	"github.com/emporous/emporous-go/nodes/descriptor"
+)
+
+func TestPullWithLinksConcurrency(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	rootRef := fmt.Sprintf("%s/root:latest", u.Host)
+
+	// Publish a collection linking to several independent collections.
+	c, err := NewClient(WithPlainHTTP(true))
+	require.NoError(t, err)
+	source, err := c.Store()
+	require.NoError(t, err)
+	configDesc, err := c.AddContent(ctx, empspec.MediaTypeConfiguration, []byte("{}"), nil)
+	require.NoError(t, err)
+	var links []ocispec.Descriptor
+	for i := 0; i < 5; i++ {
+		namespace := fmt.Sprintf("leaf%d", i)
+		leafRef := fmt.Sprintf("%s/%s:latest", u.Host, namespace)
+		layer, err := c.AddContent(ctx, "text/plain", []byte(namespace), nil)
+		require.NoError(t, err)
+		_, err = c.AddManifest(ctx, leafRef, configDesc, nil, layer)
+		require.NoError(t, err)
+		leafDesc, err := c.Push(ctx, source, leafRef)
+		require.NoError(t, err)
+
+		linkPropsJSON, err := json.Marshal(descriptor.Properties{
+			Link: &empspec.LinkAttributes{
+				RegistryHint:  u.Host,
+				NamespaceHint: namespace,
+				Transitive:    true,
+			},
+		})
+		require.NoError(t, err)
+		leafDesc.Annotations = map[string]string{empspec.AnnotationEmporousAttributes: string(linkPropsJSON)}
+		links = append(links, leafDesc)
+	}
+	linkJSON, err := json.Marshal(links)
+	require.NoError(t, err)
+	layer, err := c.AddContent(ctx, "text/plain", []byte("root"), nil)
+	require.NoError(t, err)
+	_, err = c.AddManifest(ctx, rootRef, configDesc, map[string]string{empspec.AnnotationLink: string(linkJSON)}, layer)
+	require.NoError(t, err)
+	rootDesc, err := c.Push(ctx, source, rootRef)
+	require.NoError(t, err)
+	require.NoError(t, c.Destroy())
+
+	var expDescs []ocispec.Descriptor
+	for _, concurrency := range []int{1, 2, 0} {
+		t.Run(fmt.Sprintf("Success/Concurrency%d", concurrency), func(t *testing.T) {
+			c, err := NewClient(WithPlainHTTP(true), WithConcurrency(concurrency))
+			require.NoError(t, err)
+			store := &countingStore{Store: memory.New()}
+			descs, err := c.PullWithLinks(ctx, rootRef, store)
+			require.NoError(t, err)
+			require.NoError(t, c.Destroy())
+
+			limit := concurrency
+			if limit == 0 {
+				limit = DefaultConcurrency
+			}
+			require.LessOrEqual(t, store.peak, int64(limit))
+
+			for _, link := range links {
+				exists, err := store.Exists(ctx, link)
+				require.NoError(t, err)
+				require.True(t, exists)
+			}
+			desc, err := store.Resolve(ctx, rootRef)
+			require.NoError(t, err)
+			require.Equal(t, rootDesc.Digest, desc.Digest)
+
+			// The root collection only adds its manifest and layer.
+			require.Len(t, descs, 13)
+			var rootDigests []digest.Digest
+			for _, desc := range descs[len(descs)-2:] {
+				rootDigests = append(rootDigests, desc.Digest)
+			}
+			require.Contains(t, rootDigests, rootDesc.Digest)
+			if expDescs == nil {
+				expDescs = descs
+			}
+			require.Equal(t, expDescs, descs)
+		})
+	}
+
+	t.Run("Failure/NegativeConcurrency", func(t *testing.T) {
+		_, err := NewClient(WithConcurrency(-1))
+		require.EqualError(t, err, "concurrency must not be negative")
+	})
+}
+
+// countingStore records the peak number of concurrent pushes.
+type countingStore struct {
+	*memory.Store
+	mu       sync.Mutex
+	inFlight int64
+	peak     int64
+}
+
+func (s *countingStore) Push(ctx context.Context, expected ocispec.Descriptor, content io.Reader) error {
+	s.mu.Lock()
+	s.inFlight++
+	if s.inFlight > s.peak {
+		s.peak = s.inFlight
+	}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+	// Hold the push so concurrent copies overlap.
+	time.Sleep(10 * time.Millisecond)
+	return s.Store.Push(ctx, expected, content)
+}
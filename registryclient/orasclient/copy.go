@@ -0,0 +1,426 @@
+package orasclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	orascontent "oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+)
+
+// Copy performs a copy of OCI artifacts from a remote location to another remote location.
+// Linked collections are not copied.
+func (c *orasClient) Copy(ctx context.Context, src, dst string) (ocispec.Descriptor, error) {
+	if c.prePullFn != nil {
+		if err := c.prePullFn(ctx, src); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+
+	srcRepo, err := c.setupRepo(src)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	dstRepo, err := c.setupRepo(dst)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	// Create a copy of the options so the original copy
+	// options are not modified.
+	cCopyOpts := c.copyOpts
+	cCopyOpts.FindSuccessors = successorFnWithSparseManifests
+
+	return oras.Copy(ctx, c.withProgress(srcRepo), src, c.mountingTarget(srcRepo, dstRepo), dst, cCopyOpts)
+}
+
+// CopyWithLinks performs a copy of OCI artifacts from a remote location to another remote location and
+// follows all links and the schema address. Linked collections are copied to the destination repository
+// by digest and the link information is rewritten so the copied graph is self-contained.
+func (c *orasClient) CopyWithLinks(ctx context.Context, src, dst string) (ocispec.Descriptor, error) {
+	dstRepo, err := c.setupRepo(dst)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	copied := map[digest.Digest]ocispec.Descriptor{}
+	desc, err := c.copyCollection(ctx, src, dstRepo, copied)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return desc, dstRepo.Tag(ctx, desc, dst)
+}
+
+// copyCollection copies the collection at the source reference to the destination repository
+// by digest. If the collection has links or a schema address, they are copied first and the
+// collection manifest is rewritten to point to the destination repository.
+func (c *orasClient) copyCollection(ctx context.Context, src string, dstRepo *remote.Repository, copied map[digest.Digest]ocispec.Descriptor) (ocispec.Descriptor, error) {
+	srcRepo, err := c.setupRepo(src)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	srcDesc, err := srcRepo.Resolve(ctx, src)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if desc, ok := copied[srcDesc.Digest]; ok {
+		return desc, nil
+	}
+
+	if c.prePullFn != nil {
+		if err := c.prePullFn(ctx, src); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+
+	target := c.mountingTarget(srcRepo, dstRepo)
+	cCopyOpts := c.copyOpts.CopyGraphOptions
+	cCopyOpts.FindSuccessors = successorFnWithSparseManifests
+
+	// Only image manifests can contain links.
+	if srcDesc.MediaType != ocispec.MediaTypeImageManifest {
+		if err := oras.CopyGraph(ctx, c.withProgress(srcRepo), target, srcDesc, cCopyOpts); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		copied[srcDesc.Digest] = srcDesc
+		return srcDesc, nil
+	}
+
+	manifestBytes, err := c.fetch(ctx, srcRepo, srcDesc)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	linksChanged, err := c.rewriteLinks(ctx, &manifest, dstRepo, copied)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("collection %q: %w", src, err)
+	}
+	configChanged, err := c.rewriteConfig(ctx, &manifest, srcRepo, dstRepo, copied)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("collection %q: %w", src, err)
+	}
+
+	if !linksChanged && !configChanged {
+		if err := oras.CopyGraph(ctx, c.withProgress(srcRepo), target, srcDesc, cCopyOpts); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		copied[srcDesc.Digest] = srcDesc
+		return srcDesc, nil
+	}
+
+	// Copy the blobs before pushing the rewritten manifest.
+	blobs := manifest.Layers
+	if !configChanged {
+		blobs = append(blobs, manifest.Config)
+	}
+	for _, blob := range blobs {
+		node, err := v2.NewNode(blob.Digest.String(), blob)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+		if node.Properties != nil && node.Properties.IsALink() {
+			continue
+		}
+		if err := oras.CopyGraph(ctx, c.withProgress(srcRepo), target, blob, cCopyOpts); err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc := orascontent.NewDescriptorFromBytes(srcDesc.MediaType, manifestJSON)
+	if err := pushIfNotExist(ctx, dstRepo, desc, manifestJSON); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	copied[srcDesc.Digest] = desc
+	return desc, nil
+}
+
+// rewriteLinks copies all linked collections in the manifest to the destination repository
+// and updates the link descriptors. It returns whether the manifest was changed.
+func (c *orasClient) rewriteLinks(ctx context.Context, manifest *ocispec.Manifest, dstRepo *remote.Repository, copied map[digest.Digest]ocispec.Descriptor) (bool, error) {
+	linkJSON, ok := manifest.Annotations[empspec.AnnotationLink]
+	if !ok {
+		return false, nil
+	}
+
+	var links []ocispec.Descriptor
+	if err := json.Unmarshal([]byte(linkJSON), &links); err != nil {
+		return false, err
+	}
+
+	var changed bool
+	for i, link := range links {
+		node, err := v2.NewNode(link.Digest.String(), link)
+		if err != nil {
+			return false, err
+		}
+		props := node.Properties
+		if props == nil || !props.IsALink() {
+			continue
+		}
+
+		linkRef := fmt.Sprintf("%s/%s@%s", props.Link.RegistryHint, props.Link.NamespaceHint, link.Digest)
+		linkedDesc, err := c.copyCollection(ctx, linkRef, dstRepo, copied)
+		if err != nil {
+			return false, fmt.Errorf("link %q: %w", linkRef, err)
+		}
+
+		if linkedDesc.Digest == link.Digest &&
+			props.Link.RegistryHint == dstRepo.Reference.Registry &&
+			props.Link.NamespaceHint == dstRepo.Reference.Repository {
+			continue
+		}
+
+		props.Link.RegistryHint = dstRepo.Reference.Registry
+		props.Link.NamespaceHint = dstRepo.Reference.Repository
+		propsJSON, err := props.MarshalJSON()
+		if err != nil {
+			return false, err
+		}
+		link.Digest = linkedDesc.Digest
+		link.Size = linkedDesc.Size
+		link.Annotations[empspec.AnnotationEmporousAttributes] = string(propsJSON)
+		links[i] = link
+		changed = true
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	updatedJSON, err := json.Marshal(links)
+	if err != nil {
+		return false, err
+	}
+	manifest.Annotations[empspec.AnnotationLink] = string(updatedJSON)
+	return true, nil
+}
+
+// rewriteConfig copies the schema referenced in the collection configuration to the destination
+// repository and updates the schema and linked collection addresses. It returns whether the manifest
+// was changed.
+func (c *orasClient) rewriteConfig(ctx context.Context, manifest *ocispec.Manifest, srcRepo, dstRepo *remote.Repository, copied map[digest.Digest]ocispec.Descriptor) (bool, error) {
+	if manifest.Config.MediaType != empspec.MediaTypeConfiguration {
+		return false, nil
+	}
+
+	configBytes, err := c.fetch(ctx, srcRepo, manifest.Config)
+	if err != nil {
+		return false, err
+	}
+	var config clientapi.DataSetConfiguration
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		// Configurations for schemas and other collections
+		// that were not built from a dataset configuration
+		// are copied as is.
+		return false, nil
+	}
+
+	var changed bool
+	if config.Collection.SchemaAddress != "" {
+		schemaDesc, err := c.copyCollection(ctx, config.Collection.SchemaAddress, dstRepo, copied)
+		if err != nil {
+			return false, fmt.Errorf("schema %q: %w", config.Collection.SchemaAddress, err)
+		}
+		schemaAddress := destinationReference(dstRepo, schemaDesc.Digest)
+		if schemaAddress != config.Collection.SchemaAddress {
+			config.Collection.SchemaAddress = schemaAddress
+			changed = true
+		}
+	}
+
+	// Linked collections are recorded in the same order as
+	// the links in the manifest.
+	var links []ocispec.Descriptor
+	if linkJSON, ok := manifest.Annotations[empspec.AnnotationLink]; ok {
+		if err := json.Unmarshal([]byte(linkJSON), &links); err != nil {
+			return false, err
+		}
+	}
+	if len(links) == len(config.Collection.LinkedCollections) {
+		for i, link := range links {
+			linkAddress := destinationReference(dstRepo, link.Digest)
+			if linkAddress != config.Collection.LinkedCollections[i] {
+				config.Collection.LinkedCollections[i] = linkAddress
+				changed = true
+			}
+		}
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	updatedJSON, err := json.Marshal(config)
+	if err != nil {
+		return false, err
+	}
+	configDesc := orascontent.NewDescriptorFromBytes(empspec.MediaTypeConfiguration, updatedJSON)
+	if err := pushIfNotExist(ctx, dstRepo, configDesc, updatedJSON); err != nil {
+		return false, err
+	}
+	manifest.Config = configDesc
+	return true, nil
+}
+
+// fetch retrieves the content for a descriptor from a repository.
+func (c *orasClient) fetch(ctx context.Context, repo *remote.Repository, desc ocispec.Descriptor) ([]byte, error) {
+	r, err := repo.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return orascontent.ReadAll(r, desc)
+}
+
+// mountingTarget returns a copy target for the destination repository. If the source and destination
+// repositories are on the same registry, blobs are mounted from the source repository instead of copied.
+func (c *orasClient) mountingTarget(srcRepo, dstRepo *remote.Repository) oras.Target {
+	if srcRepo.Reference.Registry != dstRepo.Reference.Registry ||
+		srcRepo.Reference.Repository == dstRepo.Reference.Repository {
+		return dstRepo
+	}
+	return &mountTarget{
+		Repository: dstRepo,
+		mount: func(ctx context.Context, desc ocispec.Descriptor) (bool, error) {
+			return c.mountBlob(ctx, srcRepo, dstRepo, desc)
+		},
+	}
+}
+
+// mountTarget is an oras.Target that attempts to mount
+// blobs that do not exist in the destination repository.
+type mountTarget struct {
+	*remote.Repository
+	mount func(context.Context, ocispec.Descriptor) (bool, error)
+}
+
+// Exists returns true if the described content exists or
+// was successfully mounted from the source repository.
+func (t *mountTarget) Exists(ctx context.Context, desc ocispec.Descriptor) (bool, error) {
+	exists, err := t.Repository.Exists(ctx, desc)
+	if err != nil || exists {
+		return exists, err
+	}
+	if isManifest(desc) {
+		return false, nil
+	}
+	return t.mount(ctx, desc)
+}
+
+// mountBlob performs a cross-repository blob mount. It returns false if
+// the registry did not mount the blob so the blob can be copied instead.
+func (c *orasClient) mountBlob(ctx context.Context, srcRepo, dstRepo *remote.Repository, desc ocispec.Descriptor) (bool, error) {
+	scheme := "https"
+	if c.plainHTTP {
+		scheme = "http"
+	}
+
+	ctx = auth.AppendScopes(ctx,
+		auth.ScopeRepository(srcRepo.Reference.Repository, auth.ActionPull),
+		auth.ScopeRepository(dstRepo.Reference.Repository, auth.ActionPull, auth.ActionPush),
+	)
+
+	query := url.Values{}
+	query.Set("mount", desc.Digest.String())
+	query.Set("from", srcRepo.Reference.Repository)
+	mountURL := fmt.Sprintf("%s://%s/v2/%s/blobs/uploads/?%s", scheme, dstRepo.Reference.Host(), dstRepo.Reference.Repository, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, mountURL, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.authClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	// A registry that cannot mount the blob may start
+	// an upload session instead with a 202 response. The session
+	// is cancelled since the blob is pushed with a new session.
+	if resp.StatusCode == http.StatusAccepted {
+		if err := c.cancelUpload(ctx, resp); err != nil && c.logger != nil {
+			c.logger.Debugf("error cancelling upload session for blob %s: %v", desc.Digest, err)
+		}
+	}
+	return resp.StatusCode == http.StatusCreated, nil
+}
+
+// cancelUpload cancels the upload session started by the response.
+func (c *orasClient) cancelUpload(ctx context.Context, resp *http.Response) error {
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil
+	}
+	uploadURL, err := resp.Request.URL.Parse(location)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, uploadURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	deleteResp, err := c.authClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer deleteResp.Body.Close()
+	if deleteResp.StatusCode != http.StatusNoContent && deleteResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", deleteResp.Status)
+	}
+	return nil
+}
+
+// pushIfNotExist pushes content to the repository if it does not already exist.
+func pushIfNotExist(ctx context.Context, repo *remote.Repository, desc ocispec.Descriptor, content []byte) error {
+	exists, err := repo.Exists(ctx, desc)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	if err := repo.Push(ctx, desc, bytes.NewReader(content)); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+		return err
+	}
+	return nil
+}
+
+// destinationReference returns the reference for a digest in the destination repository.
+func destinationReference(repo *remote.Repository, dgst digest.Digest) string {
+	return fmt.Sprintf("%s/%s@%s", repo.Reference.Registry, repo.Reference.Repository, dgst)
+}
+
+// isManifest determines if the given descriptor points to a manifest.
+func isManifest(desc ocispec.Descriptor) bool {
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageManifest, ocispec.MediaTypeImageIndex, ocispec.MediaTypeArtifactManifest,
+		string(types.DockerManifestSchema2), string(types.DockerManifestList):
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,260 @@
+package orasclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/registry/remote"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/nodes/descriptor"
+	"github.com/emporous/emporous-go/registryclient"
+)
+
+func TestCopy(t *testing.T) {
+	srcServer := httptest.NewServer(registry.New())
+	t.Cleanup(srcServer.Close)
+	srcURL, err := url.Parse(srcServer.URL)
+	require.NoError(t, err)
+
+	dstServer := httptest.NewServer(registry.New())
+	t.Cleanup(dstServer.Close)
+	dstURL, err := url.Parse(dstServer.URL)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	testdata := filepath.Join("testdata", "workspace", "fish.jpg")
+	schemaRef := fmt.Sprintf("%s/schema:latest", srcURL.Host)
+	leafRef := fmt.Sprintf("%s/leaf:latest", srcURL.Host)
+	rootRef := fmt.Sprintf("%s/root:latest", srcURL.Host)
+
+	// Publish a schema, a collection using the schema, and a collection linking
+	// to the collection using the schema.
+	c, err := NewClient(WithPlainHTTP(true))
+	require.NoError(t, err)
+	descs, err := c.AddFiles(ctx, "", testdata)
+	require.NoError(t, err)
+	source, err := c.Store()
+	require.NoError(t, err)
+
+	schemaConfig, err := c.AddContent(ctx, empspec.MediaTypeConfiguration, []byte("{}"), nil)
+	require.NoError(t, err)
+	_, err = c.AddManifest(ctx, schemaRef, schemaConfig, nil, descs...)
+	require.NoError(t, err)
+	_, err = c.Push(ctx, source, schemaRef)
+	require.NoError(t, err)
+
+	leafConfigJSON, err := json.Marshal(clientapi.DataSetConfiguration{
+		Collection: clientapi.DataSetConfigurationSpec{SchemaAddress: schemaRef},
+	})
+	require.NoError(t, err)
+	leafConfig, err := c.AddContent(ctx, empspec.MediaTypeConfiguration, leafConfigJSON, nil)
+	require.NoError(t, err)
+	_, err = c.AddManifest(ctx, leafRef, leafConfig, nil, descs...)
+	require.NoError(t, err)
+	leafDesc, err := c.Push(ctx, source, leafRef)
+	require.NoError(t, err)
+
+	linkProps := descriptor.Properties{
+		Link: &empspec.LinkAttributes{
+			RegistryHint:  srcURL.Host,
+			NamespaceHint: "leaf",
+			Transitive:    true,
+		},
+	}
+	linkPropsJSON, err := json.Marshal(linkProps)
+	require.NoError(t, err)
+	link := ocispec.Descriptor{
+		MediaType:   leafDesc.MediaType,
+		Digest:      leafDesc.Digest,
+		Size:        leafDesc.Size,
+		Annotations: map[string]string{empspec.AnnotationEmporousAttributes: string(linkPropsJSON)},
+	}
+	linkJSON, err := json.Marshal([]ocispec.Descriptor{link})
+	require.NoError(t, err)
+	rootConfigJSON, err := json.Marshal(clientapi.DataSetConfiguration{
+		Collection: clientapi.DataSetConfigurationSpec{LinkedCollections: []string{leafRef}},
+	})
+	require.NoError(t, err)
+	rootConfig, err := c.AddContent(ctx, empspec.MediaTypeConfiguration, rootConfigJSON, nil)
+	require.NoError(t, err)
+	_, err = c.AddManifest(ctx, rootRef, rootConfig, map[string]string{empspec.AnnotationLink: string(linkJSON)}, descs...)
+	require.NoError(t, err)
+	rootDesc, err := c.Push(ctx, source, rootRef)
+	require.NoError(t, err)
+	require.NoError(t, c.Destroy())
+
+	t.Run("Success/CopyOneCollection", func(t *testing.T) {
+		dst := fmt.Sprintf("%s/copy:latest", dstURL.Host)
+		c, err := NewClient(WithPlainHTTP(true))
+		require.NoError(t, err)
+		desc, err := c.Copy(ctx, rootRef, dst)
+		require.NoError(t, err)
+		require.Equal(t, rootDesc.Digest, desc.Digest)
+
+		// Links are not copied.
+		_, _, err = c.GetManifest(ctx, fmt.Sprintf("%s/copy@%s", dstURL.Host, leafDesc.Digest))
+		require.Error(t, err)
+		require.NoError(t, c.Destroy())
+	})
+
+	t.Run("Success/CopyWithLinks", func(t *testing.T) {
+		dst := fmt.Sprintf("%s/copy-all:latest", dstURL.Host)
+		var copied []string
+		prePullFn := func(ctx context.Context, reference string) error {
+			copied = append(copied, reference)
+			return nil
+		}
+		c, err := NewClient(WithPlainHTTP(true), WithPrePullFunc(prePullFn))
+		require.NoError(t, err)
+		desc, err := c.CopyWithLinks(ctx, rootRef, dst)
+		require.NoError(t, err)
+		require.NotEqual(t, rootDesc.Digest, desc.Digest)
+		require.Len(t, copied, 3)
+
+		var root ocispec.Manifest
+		getJSON(t, c, dst, desc, &root)
+		var links []ocispec.Descriptor
+		require.NoError(t, json.Unmarshal([]byte(root.Annotations[empspec.AnnotationLink]), &links))
+		require.Len(t, links, 1)
+		require.NotEqual(t, leafDesc.Digest, links[0].Digest)
+
+		var props descriptor.Properties
+		require.NoError(t, json.Unmarshal([]byte(links[0].Annotations[empspec.AnnotationEmporousAttributes]), &props))
+		require.Equal(t, dstURL.Host, props.Link.RegistryHint)
+		require.Equal(t, "copy-all", props.Link.NamespaceHint)
+
+		var rootConfig clientapi.DataSetConfiguration
+		getJSON(t, c, dst, root.Config, &rootConfig)
+		expLeafRef := fmt.Sprintf("%s/copy-all@%s", dstURL.Host, links[0].Digest)
+		require.Equal(t, []string{expLeafRef}, rootConfig.Collection.LinkedCollections)
+
+		// The linked collection schema address is rewritten.
+		var leaf ocispec.Manifest
+		getJSON(t, c, expLeafRef, links[0], &leaf)
+		var leafConfig clientapi.DataSetConfiguration
+		getJSON(t, c, expLeafRef, leaf.Config, &leafConfig)
+		schemaDesc, _, err := c.GetManifest(ctx, leafConfig.Collection.SchemaAddress)
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("%s/copy-all@%s", dstURL.Host, schemaDesc.Digest), leafConfig.Collection.SchemaAddress)
+
+		// The copied graph can be loaded from the destination.
+		co, err := c.LoadCollection(ctx, dst)
+		require.NoError(t, err)
+		require.NotNil(t, co.NodeByID(links[0].Digest.String()))
+		require.NoError(t, c.Destroy())
+	})
+
+	t.Run("Failure/SourceDoesNotExist", func(t *testing.T) {
+		notExistRef := fmt.Sprintf("%s/notexist:latest", srcURL.Host)
+		c, err := NewClient(WithPlainHTTP(true))
+		require.NoError(t, err)
+		_, err = c.CopyWithLinks(ctx, notExistRef, fmt.Sprintf("%s/notexist:latest", dstURL.Host))
+		require.EqualError(t, err, fmt.Sprintf("%s: not found", notExistRef))
+		require.NoError(t, c.Destroy())
+	})
+}
+
+func TestMountingTarget(t *testing.T) {
+	blob := []byte("testing")
+	blobDesc := ocispec.Descriptor{
+		MediaType: "application/octet-stream",
+		Digest:    digest.FromBytes(blob),
+		Size:      int64(len(blob)),
+	}
+
+	var mounts, cancelled []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPost && r.URL.Query().Get("mount") != "":
+			mounts = append(mounts, fmt.Sprintf("%s %s from %s", r.URL.Path, r.URL.Query().Get("mount"), r.URL.Query().Get("from")))
+			// The registry starts an upload session instead of
+			// mounting blobs from the unmountable repository.
+			if r.URL.Query().Get("from") == "unmountable" {
+				w.Header().Set("Location", "/v2/dst/blobs/uploads/session")
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodDelete:
+			cancelled = append(cancelled, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	c, err := NewClient(WithPlainHTTP(true))
+	require.NoError(t, err)
+	client, ok := c.(*orasClient)
+	require.True(t, ok)
+
+	srcRepo, err := client.setupRepo(fmt.Sprintf("%s/src:latest", u.Host))
+	require.NoError(t, err)
+	dstRepo, err := client.setupRepo(fmt.Sprintf("%s/dst:latest", u.Host))
+	require.NoError(t, err)
+	otherRepo, err := client.setupRepo("localhost:1/dst:latest")
+	require.NoError(t, err)
+	unmountableRepo, err := client.setupRepo(fmt.Sprintf("%s/unmountable:latest", u.Host))
+	require.NoError(t, err)
+
+	t.Run("Success/SameRegistry", func(t *testing.T) {
+		target := client.mountingTarget(srcRepo, dstRepo)
+		exists, err := target.Exists(ctx, blobDesc)
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, []string{fmt.Sprintf("/v2/dst/blobs/uploads/ %s from src", blobDesc.Digest)}, mounts)
+	})
+
+	t.Run("Success/MountNotHonored", func(t *testing.T) {
+		mounts = nil
+		target := client.mountingTarget(unmountableRepo, dstRepo)
+		exists, err := target.Exists(ctx, blobDesc)
+		require.NoError(t, err)
+		require.False(t, exists)
+		require.Len(t, mounts, 1)
+		require.Equal(t, []string{"/v2/dst/blobs/uploads/session"}, cancelled)
+	})
+
+	t.Run("Success/ManifestNotMounted", func(t *testing.T) {
+		mounts = nil
+		target := client.mountingTarget(srcRepo, dstRepo)
+		manifestDesc := blobDesc
+		manifestDesc.MediaType = ocispec.MediaTypeImageManifest
+		exists, err := target.Exists(ctx, manifestDesc)
+		require.NoError(t, err)
+		require.False(t, exists)
+		require.Len(t, mounts, 0)
+	})
+
+	t.Run("Success/DifferentRegistry", func(t *testing.T) {
+		target := client.mountingTarget(srcRepo, otherRepo)
+		_, ok := target.(*remote.Repository)
+		require.True(t, ok)
+	})
+	require.NoError(t, c.Destroy())
+}
+
+func getJSON(t *testing.T, c registryclient.Client, reference string, desc ocispec.Descriptor, v interface{}) {
+	t.Helper()
+	data, err := c.GetContent(context.TODO(), reference, desc)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, v))
+}
@@ -10,9 +10,14 @@ import (
 	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/config/credentials"
+	"github.com/docker/cli/cli/config/types"
 	"oras.land/oras-go/v2/registry/remote/auth"
 )
 
+// dockerHubKey is the key Docker Hub
+// credentials are stored under.
+const dockerHubKey = "https://index.docker.io/v1/"
+
 // AuthStore contains authentication configuration
 // file information for registry interactions.
 type AuthStore struct {
@@ -54,12 +59,16 @@ func NewAuthStore(configPaths ...string) (*AuthStore, error) {
 }
 
 // Credential iterates all the config files, returns the first non-empty
-// credential in a best-effort way.
+// credential in a best-effort way. Credentials are read from the credential
+// helper configured for the registry (credHelpers), the default credential
+// helper (credsStore), or the config file in that order. Identity tokens are
+// returned as refresh tokens so access tokens can be renewed as they expire.
 func (s *AuthStore) Credential(_ context.Context, registry string) (auth.Credential, error) {
+	key := credentialKey(registry)
 	for _, c := range s.configs {
-		authConf, err := c.GetCredentialsStore(registry).Get(registry)
+		authConf, err := c.GetCredentialsStore(key).Get(key)
 		if err != nil {
-			return auth.EmptyCredential, err
+			return auth.EmptyCredential, fmt.Errorf("get credentials for %s: %w", registry, err)
 		}
 		cred := auth.Credential{
 			Username:     authConf.Username,
@@ -67,6 +76,12 @@ func (s *AuthStore) Credential(_ context.Context, registry string) (auth.Credent
 			AccessToken:  authConf.RegistryToken,
 			RefreshToken: authConf.IdentityToken,
 		}
+		if cred.RefreshToken != "" {
+			// The username and password are not used
+			// if an identity token is set.
+			cred.Username = ""
+			cred.Password = ""
+		}
 		if cred != auth.EmptyCredential {
 			return cred, nil
 		}
@@ -74,6 +89,67 @@ func (s *AuthStore) Credential(_ context.Context, registry string) (auth.Credent
 	return auth.EmptyCredential, nil
 }
 
+// Store saves the credential for the registry in the first config file or the
+// default Docker config file if no config file exists. The credential is stored
+// in the credential helper configured for the registry if one is set.
+func (s *AuthStore) Store(registry string, cred auth.Credential) error {
+	cfg := s.writableConfig()
+	key := credentialKey(registry)
+	authConf := types.AuthConfig{
+		ServerAddress: key,
+		Username:      cred.Username,
+		Password:      cred.Password,
+		IdentityToken: cred.RefreshToken,
+	}
+	if err := cfg.GetCredentialsStore(key).Store(authConf); err != nil {
+		return fmt.Errorf("store credentials for %s: %w", registry, err)
+	}
+	return nil
+}
+
+// Erase removes the credentials for the registry from the first config
+// file or the default Docker config file if no config file exists.
+func (s *AuthStore) Erase(registry string) error {
+	cfg := s.writableConfig()
+	key := credentialKey(registry)
+	keys := []string{key}
+	// Credentials may be stored by URL
+	// (e.g. https://registry.example.com).
+	for address := range cfg.GetAuthConfigs() {
+		if address != key && credentials.ConvertToHostname(address) == credentials.ConvertToHostname(key) {
+			keys = append(keys, address)
+		}
+	}
+	for _, k := range keys {
+		if err := cfg.GetCredentialsStore(k).Erase(k); err != nil {
+			return fmt.Errorf("erase credentials for %s: %w", registry, err)
+		}
+	}
+	return nil
+}
+
+// writableConfig returns the config file
+// credentials are written to.
+func (s *AuthStore) writableConfig() *configfile.ConfigFile {
+	if len(s.configs) != 0 {
+		return s.configs[0]
+	}
+	cfg := configfile.New(filepath.Join(config.Dir(), config.ConfigFileName))
+	cfg.CredentialsStore = credentials.DetectDefaultStore(cfg.CredentialsStore)
+	s.configs = append(s.configs, cfg)
+	return cfg
+}
+
+// credentialKey returns the key credentials for the registry are stored
+// under. Docker Hub credentials are stored under the index server address.
+func credentialKey(registry string) string {
+	switch registry {
+	case "docker.io", "index.docker.io", "registry-1.docker.io":
+		return dockerHubKey
+	}
+	return registry
+}
+
 // loadConfigFile reads the credential-related configuration
 // from the given path.
 func loadConfigFile(path string) (*configfile.ConfigFile, error) {
@@ -82,6 +158,9 @@ func loadConfigFile(path string) (*configfile.ConfigFile, error) {
 		if !os.IsNotExist(err) {
 			return cfg, err
 		}
+		// The file is created when credentials are stored.
+		cfg.CredentialsStore = credentials.DetectDefaultStore(cfg.CredentialsStore)
+		return cfg, nil
 	}
 
 	file, err := os.Open(path)
@@ -114,10 +193,14 @@ func loadDefaultConfig() (*configfile.ConfigFile, error) {
 			return cfg, err
 		}
 	case os.IsNotExist(err):
+		// Credentials are stored in the Docker location
+		// if the Podman location does not exist.
 		podmanConfig := filepath.Join(xdg.RuntimeDir, "containers/auth.json")
-		cfg, err = loadConfigFile(podmanConfig)
-		if err != nil {
-			return cfg, err
+		if _, err := os.Stat(podmanConfig); err == nil {
+			cfg, err = loadConfigFile(podmanConfig)
+			if err != nil {
+				return cfg, err
+			}
 		}
 	}
 
@@ -0,0 +1,148 @@
+package orasclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+func TestAuthStoreCredential(t *testing.T) {
+	// Install a credential helper that returns an identity token.
+	helperDir := t.TempDir()
+	helper := "#!/bin/sh\nread server\necho '{\"ServerURL\":\"'$server'\",\"Username\":\"<token>\",\"Secret\":\"identity\"}'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(helperDir, "docker-credential-emporous-test"), []byte(helper), 0700))
+	t.Setenv("PATH", helperDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	configJSON := `{
+  "auths": {
+    "https://index.docker.io/v1/": {"auth": "aHViOnNlY3JldA=="},
+    "https://registry.example.com": {"auth": "dXNlcjpwYXNz"},
+    "token.example.com": {"identitytoken": "refresh"}
+  },
+  "credHelpers": {
+    "helper.example.com": "emporous-test"
+  }
+}`
+	require.NoError(t, os.WriteFile(configPath, []byte(configJSON), 0600))
+
+	type spec struct {
+		name     string
+		registry string
+		exp      auth.Credential
+	}
+
+	cases := []spec{
+		{
+			name:     "Success/URLKey",
+			registry: "registry.example.com",
+			exp:      auth.Credential{Username: "user", Password: "pass"},
+		},
+		{
+			name:     "Success/DockerHub",
+			registry: "registry-1.docker.io",
+			exp:      auth.Credential{Username: "hub", Password: "secret"},
+		},
+		{
+			name:     "Success/IdentityToken",
+			registry: "token.example.com",
+			exp:      auth.Credential{RefreshToken: "refresh"},
+		},
+		{
+			name:     "Success/CredentialHelper",
+			registry: "helper.example.com",
+			exp:      auth.Credential{RefreshToken: "identity"},
+		},
+		{
+			name:     "Success/NoCredentials",
+			registry: "unknown.example.com",
+			exp:      auth.EmptyCredential,
+		},
+	}
+
+	store, err := NewAuthStore(configPath)
+	require.NoError(t, err)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cred, err := store.Credential(context.TODO(), c.registry)
+			require.NoError(t, err)
+			require.Equal(t, c.exp, cred)
+		})
+	}
+}
+
+func TestAuthStoreStore(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	store, err := NewAuthStore(configPath)
+	require.NoError(t, err)
+
+	cred := auth.Credential{Username: "user", Password: "pass"}
+	require.NoError(t, store.Store("registry.example.com", cred))
+	require.FileExists(t, configPath)
+
+	store, err = NewAuthStore(configPath)
+	require.NoError(t, err)
+	actual, err := store.Credential(context.TODO(), "registry.example.com")
+	require.NoError(t, err)
+	require.Equal(t, cred, actual)
+
+	require.NoError(t, store.Erase("registry.example.com"))
+	store, err = NewAuthStore(configPath)
+	require.NoError(t, err)
+	actual, err = store.Credential(context.TODO(), "registry.example.com")
+	require.NoError(t, err)
+	require.Equal(t, auth.EmptyCredential, actual)
+}
+
+func TestLogin(t *testing.T) {
+	reg := registry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "user" || password != "pass" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	opts := []ClientOption{WithPlainHTTP(true), WithAuthConfigs([]string{configPath})}
+
+	t.Run("Failure/InvalidCredentials", func(t *testing.T) {
+		err := Login(ctx, u.Host, auth.Credential{Username: "user", Password: "wrong"}, opts...)
+		require.ErrorContains(t, err, fmt.Sprintf("login to %s", u.Host))
+		require.NoFileExists(t, configPath)
+	})
+
+	t.Run("Success/LoginLogout", func(t *testing.T) {
+		cred := auth.Credential{Username: "user", Password: "pass"}
+		require.NoError(t, Login(ctx, u.Host, cred, opts...))
+		store, err := NewAuthStore(configPath)
+		require.NoError(t, err)
+		actual, err := store.Credential(ctx, u.Host)
+		require.NoError(t, err)
+		require.Equal(t, cred, actual)
+
+		require.NoError(t, Logout(u.Host, opts...))
+		store, err = NewAuthStore(configPath)
+		require.NoError(t, err)
+		actual, err = store.Credential(ctx, u.Host)
+		require.NoError(t, err)
+		require.Equal(t, auth.EmptyCredential, actual)
+	})
+}
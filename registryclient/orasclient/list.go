@@ -0,0 +1,81 @@
+package orasclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// errLimitReached stops paging through a list
+// once the limit is reached.
+var errLimitReached = errors.New("limit reached")
+
+// Tags returns the tags of the repository in the order returned by the registry
+// (lexical order per the distribution spec), starting after the last tag if set.
+// All pages are fetched unless the limit is positive, in which case at most limit
+// tags are returned.
+func (c *orasClient) Tags(ctx context.Context, repository, last string, limit int) ([]string, error) {
+	repo, err := c.setupRepo(repository)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 {
+		repo.TagListPageSize = limit
+	}
+
+	var tags []string
+	err = repo.Tags(ctx, last, func(page []string) error {
+		tags = appendPage(tags, page, limit)
+		if limit > 0 && len(tags) == limit {
+			return errLimitReached
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errLimitReached) {
+		return nil, fmt.Errorf("list tags of %s: %w", repository, err)
+	}
+	return tags, nil
+}
+
+// Repositories returns the repositories of the registry from the catalog API in the
+// order returned by the registry, starting after the last repository if set. All pages
+// are fetched unless the limit is positive, in which case at most limit repositories
+// are returned.
+func (c *orasClient) Repositories(ctx context.Context, registry, last string, limit int) ([]string, error) {
+	if c.offline {
+		return nil, errOffline
+	}
+	reg, err := remote.NewRegistry(registry)
+	if err != nil {
+		return nil, fmt.Errorf("could not create registry target: %w", err)
+	}
+	reg.PlainHTTP = c.plainHTTP
+	reg.Client = c.authClient
+	if limit > 0 {
+		reg.RepositoryListPageSize = limit
+	}
+
+	var repos []string
+	err = reg.Repositories(ctx, last, func(page []string) error {
+		repos = appendPage(repos, page, limit)
+		if limit > 0 && len(repos) == limit {
+			return errLimitReached
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errLimitReached) {
+		return nil, fmt.Errorf("list repositories of %s: %w", registry, err)
+	}
+	return repos, nil
+}
+
+// appendPage appends the page to the list without
+// exceeding the limit, if positive.
+func appendPage(list, page []string, limit int) []string {
+	if limit > 0 && len(list)+len(page) > limit {
+		page = page[:limit-len(list)]
+	}
+	return append(list, page...)
+}
@@ -0,0 +1,116 @@
+package orasclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagsRepositories(t *testing.T) {
+	tags := []string{"v1", "v2", "v3", "v4", "v5"}
+	repos := []string{"team/app", "team/lib", "tools"}
+
+	// page serves the names after the last query parameter in pages of
+	// size n (two by default) with a Link header to the next page.
+	page := func(w http.ResponseWriter, r *http.Request, key string, names []string) {
+		query := r.URL.Query()
+		start := sort.SearchStrings(names, query.Get("last"))
+		if start < len(names) && names[start] == query.Get("last") {
+			start++
+		}
+		n := 2
+		if size, err := strconv.Atoi(query.Get("n")); err == nil {
+			n = size
+		}
+		end := len(names)
+		if start+n < end {
+			end = start + n
+			next := url.Values{"n": {strconv.Itoa(n)}, "last": {names[end-1]}}
+			w.Header().Set("Link", fmt.Sprintf("<%s?%s>; rel=\"next\"", r.URL.Path, next.Encode()))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string][]string{key: names[start:end]}))
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/team/app/tags/list":
+			page(w, r, "tags", tags)
+		case "/v2/_catalog":
+			page(w, r, "repositories", repos)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client, err := NewClient(WithPlainHTTP(true))
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, client.Destroy()) })
+
+	type spec struct {
+		name  string
+		list  func(ctx context.Context, name, last string, limit int) ([]string, error)
+		arg   string
+		last  string
+		limit int
+		exp   []string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/AllTags",
+			list: client.Tags,
+			arg:  u.Host + "/team/app",
+			exp:  tags,
+		},
+		{
+			name:  "Success/TagsWithLimit",
+			list:  client.Tags,
+			arg:   u.Host + "/team/app",
+			limit: 3,
+			exp:   []string{"v1", "v2", "v3"},
+		},
+		{
+			name:  "Success/TagsAfterLast",
+			list:  client.Tags,
+			arg:   u.Host + "/team/app",
+			last:  "v3",
+			limit: 1,
+			exp:   []string{"v4"},
+		},
+		{
+			name: "Success/AllRepositories",
+			list: client.Repositories,
+			arg:  u.Host,
+			exp:  repos,
+		},
+		{
+			name: "Success/RepositoriesAfterLast",
+			list: client.Repositories,
+			arg:  u.Host,
+			last: "team/app",
+			exp:  []string{"team/lib", "tools"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			names, err := c.list(context.TODO(), c.arg, c.last, c.limit)
+			require.NoError(t, err)
+			require.Equal(t, c.exp, names)
+		})
+	}
+
+	_, err = client.Tags(context.TODO(), u.Host+"/missing", "", 0)
+	require.ErrorContains(t, err, fmt.Sprintf("list tags of %s/missing", u.Host))
+}
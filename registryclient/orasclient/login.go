@@ -0,0 +1,60 @@
+package orasclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// Login verifies the credential with the registry and stores it in the first
+// config file set with WithAuthConfigs or the default Docker config file. The
+// TLS, plain HTTP, and retry options are used to contact the registry.
+func Login(ctx context.Context, registry string, cred auth.Credential, options ...ClientOption) error {
+	config := &ClientConfig{}
+	if err := config.apply(options); err != nil {
+		return err
+	}
+	transport, err := config.transport()
+	if err != nil {
+		return err
+	}
+
+	reg, err := remote.NewRegistry(registry)
+	if err != nil {
+		return err
+	}
+	reg.PlainHTTP = config.plainHTTP
+	reg.Client = &auth.Client{
+		Client: &http.Client{
+			Transport: newRetryTransport(transport, config.retryPolicy, config.logger),
+		},
+		Credential: auth.StaticCredential(reg.Reference.Registry, cred),
+		Cache:      auth.NewCache(),
+	}
+	if err := reg.Ping(ctx); err != nil {
+		return fmt.Errorf("login to %s: %w", registry, err)
+	}
+
+	store, err := NewAuthStore(config.configs...)
+	if err != nil {
+		return err
+	}
+	return store.Store(registry, cred)
+}
+
+// Logout removes the credentials for the registry from the first config
+// file set with WithAuthConfigs or the default Docker config file.
+func Logout(registry string, options ...ClientOption) error {
+	config := &ClientConfig{}
+	if err := config.apply(options); err != nil {
+		return err
+	}
+	store, err := NewAuthStore(config.configs...)
+	if err != nil {
+		return err
+	}
+	return store.Erase(registry)
+}
@@ -0,0 +1,214 @@
+package orasclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/log"
+)
+
+// WithRegistryConfig tries the mirrors configured for a registry before the
+// registry when pulling content. Content is always pushed to the registry.
+func WithRegistryConfig(registryConfig clientapi.RegistryConfiguration) ClientOption {
+	return func(config *ClientConfig) error {
+		for _, reg := range registryConfig.Registries {
+			if reg.Location == "" {
+				return errors.New("registry location must be set")
+			}
+			for _, mirror := range reg.Mirrors {
+				if mirror.Location == "" {
+					return fmt.Errorf("registry %s: mirror location must be set", reg.Location)
+				}
+				switch mirror.PullFromMirror {
+				case "", clientapi.PullFromMirrorAll, clientapi.PullFromMirrorDigestOnly, clientapi.PullFromMirrorTagOnly:
+				default:
+					return fmt.Errorf("registry %s: mirror %s: invalid pullFromMirror value %q", reg.Location, mirror.Location, mirror.PullFromMirror)
+				}
+			}
+		}
+		config.registries = registryConfig.Registries
+		return nil
+	}
+}
+
+// pullTarget is a remote location to pull content from.
+type pullTarget interface {
+	oras.Target
+	registry.ReferenceFetcher
+}
+
+// pullTarget returns the target to pull the reference from. If mirrors are configured
+// for the reference, the mirrors are tried in order before the registry.
+func (c *orasClient) pullTarget(reference string) (pullTarget, error) {
+	repo, err := c.setupRepo(reference)
+	if err != nil {
+		return nil, err
+	}
+	mirrorRefs, err := mirrorReferences(c.registries, reference)
+	if err != nil {
+		return nil, err
+	}
+	if len(mirrorRefs) == 0 {
+		return repo, nil
+	}
+
+	target := &mirrorTarget{Repository: repo, logger: c.logger}
+	for _, mirrorRef := range mirrorRefs {
+		mirror, err := c.newRepository(mirrorRef.reference, c.plainHTTP || mirrorRef.plainHTTP)
+		if err != nil {
+			return nil, err
+		}
+		target.mirrors = append(target.mirrors, mirror)
+	}
+	return target, nil
+}
+
+// mirrorReference is the location of a reference
+// on a mirror.
+type mirrorReference struct {
+	reference string
+	plainHTTP bool
+}
+
+// mirrorReferences returns the references to try in order before the reference
+// per the mirrors of the most specific registry location matching the reference.
+func mirrorReferences(registries []clientapi.Registry, reference string) ([]mirrorReference, error) {
+	if len(registries) == 0 {
+		return nil, nil
+	}
+	ref, err := registry.ParseReference(reference)
+	if err != nil {
+		return nil, err
+	}
+	name := path.Join(ref.Registry, ref.Repository)
+
+	var match *clientapi.Registry
+	var matchLocation string
+	for i, reg := range registries {
+		location := strings.TrimSuffix(reg.Location, "/")
+		if name != location && !strings.HasPrefix(name, location+"/") {
+			continue
+		}
+		if len(location) > len(matchLocation) {
+			match = &registries[i]
+			matchLocation = location
+		}
+	}
+	if match == nil {
+		return nil, nil
+	}
+
+	_, digestErr := ref.Digest()
+	byDigest := digestErr == nil
+	remainder := strings.TrimPrefix(strings.TrimPrefix(name, matchLocation), "/")
+
+	var mirrorRefs []mirrorReference
+	for _, mirror := range match.Mirrors {
+		switch {
+		case mirror.PullFromMirror == clientapi.PullFromMirrorDigestOnly && !byDigest:
+			continue
+		case mirror.PullFromMirror == clientapi.PullFromMirrorTagOnly && byDigest:
+			continue
+		}
+		host, prefix, _ := strings.Cut(strings.TrimSuffix(mirror.Location, "/"), "/")
+		mirrorRef := registry.Reference{
+			Registry:   host,
+			Repository: path.Join(prefix, remainder),
+			Reference:  ref.Reference,
+		}
+		if err := mirrorRef.Validate(); err != nil {
+			return nil, fmt.Errorf("mirror %s: %w", mirror.Location, err)
+		}
+		mirrorRefs = append(mirrorRefs, mirrorReference{
+			reference: mirrorRef.String(),
+			plainHTTP: mirror.PlainHTTP,
+		})
+	}
+	return mirrorRefs, nil
+}
+
+// mirrorTarget reads content from the first mirror that provides it and falls
+// back to the registry. Content is only written to the registry.
+type mirrorTarget struct {
+	*remote.Repository
+	mirrors []*remote.Repository
+	logger  log.Logger
+}
+
+// Resolve resolves a reference to a descriptor.
+func (t *mirrorTarget) Resolve(ctx context.Context, reference string) (desc ocispec.Descriptor, err error) {
+	err = t.try(ctx, func(repo *remote.Repository) (err error) {
+		desc, err = repo.Resolve(ctx, t.reference(repo, reference))
+		return err
+	})
+	return desc, err
+}
+
+// Fetch fetches the content identified by the descriptor.
+func (t *mirrorTarget) Fetch(ctx context.Context, target ocispec.Descriptor) (rc io.ReadCloser, err error) {
+	err = t.try(ctx, func(repo *remote.Repository) (err error) {
+		rc, err = repo.Fetch(ctx, target)
+		return err
+	})
+	return rc, err
+}
+
+// FetchReference fetches the manifest identified by the reference.
+func (t *mirrorTarget) FetchReference(ctx context.Context, reference string) (desc ocispec.Descriptor, rc io.ReadCloser, err error) {
+	err = t.try(ctx, func(repo *remote.Repository) (err error) {
+		desc, rc, err = repo.FetchReference(ctx, t.reference(repo, reference))
+		return err
+	})
+	return desc, rc, err
+}
+
+// Exists returns whether the content identified by the descriptor exists.
+func (t *mirrorTarget) Exists(ctx context.Context, target ocispec.Descriptor) (exists bool, err error) {
+	err = t.try(ctx, func(repo *remote.Repository) (err error) {
+		exists, err = repo.Exists(ctx, target)
+		if err == nil && !exists && repo != t.Repository {
+			return fmt.Errorf("%s: not found", target.Digest)
+		}
+		return err
+	})
+	return exists, err
+}
+
+// try calls fn for each mirror in order and then the
+// registry until fn succeeds.
+func (t *mirrorTarget) try(ctx context.Context, fn func(repo *remote.Repository) error) error {
+	for _, mirror := range t.mirrors {
+		err := fn(mirror)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+		if t.logger != nil {
+			t.logger.Debugf("mirror %s: %v: trying the next location", mirror.Reference.String(), err)
+		}
+	}
+	return fn(t.Repository)
+}
+
+// reference returns the reference to use for the repository. References for the
+// registry are rewritten to the tag or digest so they are valid for the mirrors.
+func (t *mirrorTarget) reference(repo *remote.Repository, reference string) string {
+	if repo == t.Repository {
+		return reference
+	}
+	if ref, err := registry.ParseReference(reference); err == nil {
+		return ref.Reference
+	}
+	return reference
+}
+
+var _ pullTarget = &mirrorTarget{}
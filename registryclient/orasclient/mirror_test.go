@@ -0,0 +1,234 @@
+package orasclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content/memory"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+)
+
+func TestMirrorReferences(t *testing.T) {
+	dgst := "sha256:0fee6a79262a48a06b5403cd2e684bb05174cc67e8d9d8560bc89a039170ed47"
+	registries := []clientapi.Registry{
+		{
+			Location: "registry.example.com",
+			Mirrors: []clientapi.Mirror{
+				{Location: "mirror.example.com"},
+				{Location: "localhost:5000/cache", PullFromMirror: clientapi.PullFromMirrorDigestOnly, PlainHTTP: true},
+			},
+		},
+		{
+			Location: "registry.example.com/team",
+			Mirrors: []clientapi.Mirror{
+				{Location: "team.example.com/mirror", PullFromMirror: clientapi.PullFromMirrorTagOnly},
+			},
+		},
+	}
+
+	type spec struct {
+		name      string
+		reference string
+		exp       []mirrorReference
+		expError  string
+	}
+
+	cases := []spec{
+		{
+			name:      "Success/ByTag",
+			reference: "registry.example.com/app/collection:latest",
+			exp: []mirrorReference{
+				{reference: "mirror.example.com/app/collection:latest"},
+			},
+		},
+		{
+			name:      "Success/ByDigest",
+			reference: "registry.example.com/app/collection@" + dgst,
+			exp: []mirrorReference{
+				{reference: "mirror.example.com/app/collection@" + dgst},
+				{reference: "localhost:5000/cache/app/collection@" + dgst, plainHTTP: true},
+			},
+		},
+		{
+			name:      "Success/MostSpecificLocation",
+			reference: "registry.example.com/team/collection:latest",
+			exp: []mirrorReference{
+				{reference: "team.example.com/mirror/collection:latest"},
+			},
+		},
+		{
+			name:      "Success/MostSpecificLocationByDigest",
+			reference: "registry.example.com/team/collection@" + dgst,
+		},
+		{
+			name:      "Success/NoMatchingLocation",
+			reference: "registry.example.com.evil/app/collection:latest",
+		},
+		{
+			name:      "Failure/InvalidReference",
+			reference: "registry.example.com/App:latest",
+			expError:  "invalid reference: invalid repository",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mirrorRefs, err := mirrorReferences(registries, c.reference)
+			if c.expError != "" {
+				require.ErrorContains(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.exp, mirrorRefs)
+			}
+		})
+	}
+}
+
+func TestPullFromMirror(t *testing.T) {
+	// countingServer returns a registry server that records
+	// the number of read requests.
+	countingServer := func(t *testing.T) (*httptest.Server, func() int) {
+		var mu sync.Mutex
+		var reads int
+		reg := registry.New()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				mu.Lock()
+				reads++
+				mu.Unlock()
+			}
+			reg.ServeHTTP(w, r)
+		}))
+		t.Cleanup(server.Close)
+		return server, func() int {
+			mu.Lock()
+			defer mu.Unlock()
+			return reads
+		}
+	}
+
+	upstream, upstreamReads := countingServer(t)
+	upstreamURL, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	mirror, mirrorReads := countingServer(t)
+	mirrorURL, err := url.Parse(mirror.URL)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	ref := fmt.Sprintf("%s/test:latest", upstreamURL.Host)
+	mirrorRef := fmt.Sprintf("%s/upstream/test:latest", mirrorURL.Host)
+	missingRef := fmt.Sprintf("%s/missing:latest", upstreamURL.Host)
+
+	// Publish the collection to the upstream and the mirror
+	// and a second collection to the upstream only.
+	c, err := NewClient(WithPlainHTTP(true))
+	require.NoError(t, err)
+	descs, err := c.AddFiles(ctx, "", filepath.Join("testdata", "workspace", "fish.jpg"))
+	require.NoError(t, err)
+	configDesc, err := c.AddContent(ctx, empspec.MediaTypeConfiguration, []byte("{}"), nil)
+	require.NoError(t, err)
+	source, err := c.Store()
+	require.NoError(t, err)
+	for _, r := range []string{ref, mirrorRef, missingRef} {
+		_, err = c.AddManifest(ctx, r, configDesc, nil, descs...)
+		require.NoError(t, err)
+		_, err = c.Push(ctx, source, r)
+		require.NoError(t, err)
+	}
+	require.NoError(t, c.Destroy())
+
+	registryConfig := func(policy string) clientapi.RegistryConfiguration {
+		return clientapi.RegistryConfiguration{
+			Registries: []clientapi.Registry{
+				{
+					Location: upstreamURL.Host,
+					Mirrors: []clientapi.Mirror{
+						{Location: fmt.Sprintf("%s/upstream", mirrorURL.Host), PullFromMirror: policy},
+					},
+				},
+			},
+		}
+	}
+
+	type spec struct {
+		name        string
+		reference   string
+		policy      string
+		expMirror   bool
+		expUpstream bool
+	}
+
+	cases := []spec{
+		{
+			name:        "Success/PullFromMirror",
+			reference:   ref,
+			expMirror:   true,
+			expUpstream: false,
+		},
+		{
+			name:        "Success/FallbackToUpstream",
+			reference:   missingRef,
+			expMirror:   true,
+			expUpstream: true,
+		},
+		{
+			name:        "Success/MirrorDigestOnly",
+			reference:   ref,
+			policy:      clientapi.PullFromMirrorDigestOnly,
+			expMirror:   false,
+			expUpstream: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			prevMirror, prevUpstream := mirrorReads(), upstreamReads()
+			client, err := NewClient(WithPlainHTTP(true), WithRegistryConfig(registryConfig(c.policy)))
+			require.NoError(t, err)
+			desc, pulled, err := client.Pull(ctx, c.reference, memory.New())
+			require.NoError(t, err)
+			require.NotEmpty(t, desc.Digest)
+			require.Len(t, pulled, 4)
+			require.NoError(t, client.Destroy())
+
+			require.Equal(t, c.expMirror, mirrorReads() > prevMirror)
+			require.Equal(t, c.expUpstream, upstreamReads() > prevUpstream)
+		})
+	}
+
+	t.Run("Success/PushToUpstream", func(t *testing.T) {
+		pushRef := fmt.Sprintf("%s/pushed:latest", upstreamURL.Host)
+		client, err := NewClient(WithPlainHTTP(true), WithRegistryConfig(registryConfig("")))
+		require.NoError(t, err)
+		_, err = client.AddManifest(ctx, pushRef, configDesc, nil, descs...)
+		require.NoError(t, err)
+		store, err := client.Store()
+		require.NoError(t, err)
+		_, err = client.Push(ctx, store, pushRef)
+		require.NoError(t, err)
+		require.NoError(t, client.Destroy())
+
+		client, err = NewClient(WithPlainHTTP(true))
+		require.NoError(t, err)
+		_, _, err = client.GetManifest(ctx, pushRef)
+		require.NoError(t, err)
+		_, _, err = client.GetManifest(ctx, fmt.Sprintf("%s/upstream/pushed:latest", mirrorURL.Host))
+		require.Error(t, err)
+		require.NoError(t, client.Destroy())
+	})
+
+	t.Run("Failure/InvalidPolicy", func(t *testing.T) {
+		_, err := NewClient(WithRegistryConfig(registryConfig("sometimes")))
+		require.EqualError(t, err, fmt.Sprintf("registry %s: mirror %s/upstream: invalid pullFromMirror value \"sometimes\"", upstreamURL.Host, mirrorURL.Host))
+	})
+}
@@ -0,0 +1,103 @@
+package orasclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry"
+
+	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/nodes/collection"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+)
+
+// errOffline is returned when an operation that requires
+// network access is performed in offline mode.
+var errOffline = errors.New("operation requires network access and the client is in offline mode")
+
+// offlineTarget resolves references and fetches content
+// from the cache only.
+type offlineTarget struct {
+	content.Store
+	client    *orasClient
+	reference string
+}
+
+// offlineTarget returns a target to use in place of the remote repository
+// for the reference in offline mode.
+func (c *orasClient) offlineTarget(reference string) *offlineTarget {
+	return &offlineTarget{
+		Store:     c.cache,
+		client:    c,
+		reference: reference,
+	}
+}
+
+// Resolve resolves a reference to a descriptor stored in the cache. References
+// by digest are resolved using the collections already loaded by the client (e.g. links).
+func (t *offlineTarget) Resolve(ctx context.Context, reference string) (ocispec.Descriptor, error) {
+	desc, err := t.Store.Resolve(ctx, reference)
+	if err == nil {
+		return desc, nil
+	}
+
+	ref, err := registry.ParseReference(reference)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	dgst, err := ref.Digest()
+	if err != nil {
+		return ocispec.Descriptor{}, &content.ErrNotStored{Reference: reference}
+	}
+
+	var found bool
+	t.client.collections.Range(func(_, value any) bool {
+		co := value.(collection.Collection)
+		node, ok := co.NodeByID(dgst.String()).(*v2.Node)
+		if ok {
+			desc = node.Descriptor()
+			found = true
+		}
+		return !found
+	})
+	if !found {
+		return ocispec.Descriptor{}, &content.ErrNotStored{Reference: reference}
+	}
+
+	exists, err := t.Store.Exists(ctx, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if !exists {
+		return ocispec.Descriptor{}, &content.ErrNotStored{Reference: reference}
+	}
+	return desc, nil
+}
+
+// Fetch fetches the content identified by the descriptor from the cache.
+func (t *offlineTarget) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	exists, err := t.Store.Exists(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", desc.Digest, &content.ErrNotStored{Reference: t.reference})
+	}
+	return t.Store.Fetch(ctx, desc)
+}
+
+// FetchReference fetches the content identified by the reference from the cache.
+func (t *offlineTarget) FetchReference(ctx context.Context, reference string) (ocispec.Descriptor, io.ReadCloser, error) {
+	desc, err := t.Resolve(ctx, reference)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	rc, err := t.Fetch(ctx, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	return desc, rc, nil
+}
@@ -0,0 +1,150 @@
+package orasclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/google/go-containerregistry/pkg/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	orascontent "oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+
+	"github.com/emporous/emporous-go/attributes"
+	"github.com/emporous/emporous-go/attributes/matchers"
+	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/nodes/descriptor"
+)
+
+func TestOfflinePull(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	testdata := filepath.Join("testdata", "workspace", "fish.jpg")
+	leafRef := fmt.Sprintf("%s/leaf:latest", u.Host)
+	rootRef := fmt.Sprintf("%s/root:latest", u.Host)
+
+	// Publish a collection linking to another collection.
+	c, err := NewClient(WithPlainHTTP(true))
+	require.NoError(t, err)
+	descs, err := c.AddFiles(ctx, "", testdata)
+	require.NoError(t, err)
+	source, err := c.Store()
+	require.NoError(t, err)
+	configDesc, err := c.AddContent(ctx, empspec.MediaTypeConfiguration, []byte("{}"), nil)
+	require.NoError(t, err)
+	_, err = c.AddManifest(ctx, leafRef, configDesc, nil, descs...)
+	require.NoError(t, err)
+	leafDesc, err := c.Push(ctx, source, leafRef)
+	require.NoError(t, err)
+
+	linkPropsJSON, err := json.Marshal(descriptor.Properties{
+		Link: &empspec.LinkAttributes{
+			RegistryHint:  u.Host,
+			NamespaceHint: "leaf",
+			Transitive:    true,
+		},
+	})
+	require.NoError(t, err)
+	leafDesc.Annotations = map[string]string{empspec.AnnotationEmporousAttributes: string(linkPropsJSON)}
+	linkJSON, err := json.Marshal([]ocispec.Descriptor{leafDesc})
+	require.NoError(t, err)
+	_, err = c.AddManifest(ctx, rootRef, configDesc, map[string]string{empspec.AnnotationLink: string(linkJSON)}, descs...)
+	require.NoError(t, err)
+	rootDesc, err := c.Push(ctx, source, rootRef)
+	require.NoError(t, err)
+	require.NoError(t, c.Destroy())
+
+	// Populate the cache and stop the registry so any
+	// network access fails.
+	cache := memory.New()
+	c, err = NewClient(WithPlainHTTP(true), WithCache(cache))
+	require.NoError(t, err)
+	_, err = c.PullWithLinks(ctx, rootRef, memory.New())
+	require.NoError(t, err)
+	require.NoError(t, cache.Tag(ctx, rootDesc, rootRef))
+	require.NoError(t, c.Destroy())
+	server.Close()
+
+	t.Run("Success/PullOneCollection", func(t *testing.T) {
+		c, err := NewClient(WithCache(cache), WithOffline(true))
+		require.NoError(t, err)
+		root, descs, err := c.Pull(ctx, rootRef, memory.New())
+		require.NoError(t, err)
+		require.Equal(t, rootDesc.Digest, root.Digest)
+		require.Len(t, descs, 4)
+		require.NoError(t, c.Destroy())
+	})
+
+	t.Run("Success/PullWithLinks", func(t *testing.T) {
+		c, err := NewClient(WithCache(cache), WithOffline(true))
+		require.NoError(t, err)
+		descs, err := c.PullWithLinks(ctx, rootRef, memory.New())
+		require.NoError(t, err)
+		require.Len(t, descs, 4)
+		require.NoError(t, c.Destroy())
+	})
+
+	t.Run("Success/PullFilteredCollection", func(t *testing.T) {
+		matcher := matchers.PartialAttributeMatcher{
+			"test": attributes.NewString("test", "fail"),
+		}
+		c, err := NewClient(WithCache(cache), WithOffline(true), WithPullableAttributes(matcher))
+		require.NoError(t, err)
+		root, descs, err := c.Pull(ctx, rootRef, memory.New())
+		require.NoError(t, err)
+		require.Empty(t, root.Digest)
+		require.Len(t, descs, 0)
+		require.NoError(t, c.Destroy())
+	})
+
+	t.Run("Failure/ReferenceNotStored", func(t *testing.T) {
+		c, err := NewClient(WithCache(cache), WithOffline(true))
+		require.NoError(t, err)
+		_, _, err = c.Pull(ctx, leafRef, memory.New())
+		var notStored *content.ErrNotStored
+		require.True(t, errors.As(err, &notStored))
+		require.EqualError(t, err, fmt.Sprintf("descriptor for reference %s is not stored", leafRef))
+		require.NoError(t, c.Destroy())
+	})
+
+	t.Run("Failure/BlobNotStored", func(t *testing.T) {
+		// Only store the root manifest.
+		partial := memory.New()
+		manifest, err := orascontent.FetchAll(ctx, cache, rootDesc)
+		require.NoError(t, err)
+		require.NoError(t, partial.Push(ctx, rootDesc, bytes.NewReader(manifest)))
+		require.NoError(t, partial.Tag(ctx, rootDesc, rootRef))
+
+		c, err := NewClient(WithCache(partial), WithOffline(true))
+		require.NoError(t, err)
+		_, _, err = c.Pull(ctx, rootRef, memory.New())
+		var notStored *content.ErrNotStored
+		require.True(t, errors.As(err, &notStored))
+		require.Contains(t, err.Error(), fmt.Sprintf("descriptor for reference %s is not stored", rootRef))
+		require.NoError(t, c.Destroy())
+	})
+
+	t.Run("Failure/Push", func(t *testing.T) {
+		c, err := NewClient(WithCache(cache), WithOffline(true))
+		require.NoError(t, err)
+		_, err = c.Push(ctx, cache, rootRef)
+		require.ErrorIs(t, err, errOffline)
+		require.NoError(t, c.Destroy())
+	})
+
+	t.Run("Failure/NoCache", func(t *testing.T) {
+		_, err := NewClient(WithOffline(true))
+		require.EqualError(t, err, "offline mode requires a cache")
+	})
+}
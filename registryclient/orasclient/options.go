@@ -2,7 +2,7 @@ package orasclient
 
 import (
 	"context"
-	"crypto/tls"
+	"errors"
 	"net/http"
 	"sync"
 
@@ -13,25 +13,37 @@ import (
 	"oras.land/oras-go/v2/content/file"
 	"oras.land/oras-go/v2/content/memory"
 
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
 	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/log"
 	"github.com/emporous/emporous-go/model"
 	"github.com/emporous/emporous-go/registryclient"
 )
 
+// DefaultConcurrency is the maximum number of concurrent
+// copy tasks if the concurrency is not set.
+const DefaultConcurrency = 3
+
 // ClientOption is a function that configures
 // options on the client config.
 type ClientOption func(o *ClientConfig) error
 
 // ClientConfig contains configuration data for the registry client.
 type ClientConfig struct {
-	configs    []string
-	credFn     func(context.Context, string) (auth.Credential, error)
-	prePullFn  func(context.Context, string) error
-	plainHTTP  bool
-	insecure   bool
-	cache      content.Store
-	copyOpts   oras.CopyOptions
-	attributes model.Matcher
+	configs     []string
+	credFn      func(context.Context, string) (auth.Credential, error)
+	prePullFn   func(context.Context, string) error
+	plainHTTP   bool
+	insecure    bool
+	cache       content.Store
+	copyOpts    oras.CopyOptions
+	attributes  model.Matcher
+	offline     bool
+	progressFn  ProgressFunc
+	retryPolicy RetryPolicy
+	logger      log.Logger
+	registries  []clientapi.Registry
+	tlsFiles    clientapi.RegistryTLS
 }
 
 func (c *ClientConfig) apply(options []ClientOption) error {
@@ -52,6 +64,9 @@ func NewClient(options ...ClientOption) (registryclient.Client, error) {
 	if err := config.apply(options); err != nil {
 		return client, err
 	}
+	if config.offline && config.cache == nil {
+		return client, errors.New("offline mode requires a cache")
+	}
 
 	var once sync.Once
 	destroy := func() (destroyErr error) {
@@ -63,13 +78,13 @@ func NewClient(options ...ClientOption) (registryclient.Client, error) {
 	}
 
 	// Setup auth client based on config inputs
+	transport, err := config.transport()
+	if err != nil {
+		return client, err
+	}
 	authClient := &auth.Client{
 		Client: &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: config.insecure,
-				},
-			},
+			Transport: newRetryTransport(transport, config.retryPolicy, config.logger),
 		},
 		Cache: auth.NewCache(),
 	}
@@ -91,6 +106,13 @@ func NewClient(options ...ClientOption) (registryclient.Client, error) {
 	client.cache = config.cache
 	client.attributes = config.attributes
 	client.prePullFn = config.prePullFn
+	client.offline = config.offline
+	client.registries = config.registries
+	client.logger = config.logger
+	if config.progressFn != nil {
+		client.progress = &progressTracker{progressFn: config.progressFn}
+		client.copyOpts = client.progress.withHooks(client.copyOpts)
+	}
 
 	// We are not allowing this to be configurable since
 	// oras file stores turn artifacts into descriptors in
@@ -179,3 +201,35 @@ func WithPullableAttributes(filter model.Matcher) ClientOption {
 		return nil
 	}
 }
+
+// WithOffline resolves all content from the cache set with WithCache
+// without network access. Operations that require network access fail.
+func WithOffline(offline bool) ClientOption {
+	return func(config *ClientConfig) error {
+		config.offline = offline
+		return nil
+	}
+}
+
+// WithLogger sets the logger used to report client
+// activity (e.g. retried requests).
+func WithLogger(logger log.Logger) ClientOption {
+	return func(config *ClientConfig) error {
+		config.logger = logger
+		return nil
+	}
+}
+
+// WithConcurrency limits the maximum number of concurrent copy tasks. When
+// pulling with links, the limit is split between the linked collections pulled in
+// parallel and the blobs of each collection, so the limit applies to the total
+// number of blobs copied at once. If zero, DefaultConcurrency is used.
+func WithConcurrency(concurrency int) ClientOption {
+	return func(config *ClientConfig) error {
+		if concurrency < 0 {
+			return errors.New("concurrency must not be negative")
+		}
+		config.copyOpts.Concurrency = int64(concurrency)
+		return nil
+	}
+}
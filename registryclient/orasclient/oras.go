@@ -15,13 +15,16 @@ import (
 	"github.com/gabriel-vasile/mimetype"
 	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/sync/errgroup"
 	"oras.land/oras-go/v2"
 	orascontent "oras.land/oras-go/v2/content"
 	"oras.land/oras-go/v2/content/file"
 	"oras.land/oras-go/v2/registry/remote"
 	"oras.land/oras-go/v2/registry/remote/auth"
 
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
 	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/log"
 	"github.com/emporous/emporous-go/model"
 	"github.com/emporous/emporous-go/model/traversal"
 	"github.com/emporous/emporous-go/nodes/collection"
@@ -51,6 +54,16 @@ type orasClient struct {
 	// attributes is set to filter
 	// collections by attribute.
 	attributes model.Matcher
+	// offline resolves all content
+	// from the cache.
+	offline bool
+	// progress emits copy
+	// progress events.
+	progress *progressTracker
+	// registries configures the mirrors
+	// to pull content from.
+	registries []clientapi.Registry
+	logger     log.Logger
 }
 
 var _ registryclient.Client = &orasClient{}
@@ -145,7 +158,7 @@ func (c *orasClient) Save(ctx context.Context, ref string, store content.Store)
 	// options are not modified.
 	cCopyOpts := c.copyOpts
 	cCopyOpts.FindSuccessors = successorFnWithSparseManifests
-	return oras.Copy(ctx, c.artifactStore, ref, store, ref, cCopyOpts)
+	return oras.Copy(ctx, c.withProgress(c.artifactStore), ref, store, ref, cCopyOpts)
 }
 
 // LoadCollection loads a Emporous collection type from a remote registry path.
@@ -180,11 +193,8 @@ func (c *orasClient) LoadCollection(ctx context.Context, reference string) (coll
 }
 
 // PullWithLinks performs a copy of OCI artifacts to a local location from a remote location and follow links to
-// other artifacts.
+// other artifacts. Linked artifacts are pulled in parallel.
 func (c *orasClient) PullWithLinks(ctx context.Context, ref string, store content.Store) ([]ocispec.Descriptor, error) {
-	seen := map[string]struct{}{}
-	var allDescs []ocispec.Descriptor
-
 	graph, err := c.LoadCollection(ctx, ref)
 	if err != nil {
 		return nil, err
@@ -194,27 +204,12 @@ func (c *orasClient) PullWithLinks(ctx context.Context, ref string, store conten
 		return nil, err
 	}
 
-	processFunc := func(currRef string) error {
-		rootDesc, descs, err := c.Pull(ctx, currRef, store)
-		if err != nil {
-			return err
-		}
-		if len(rootDesc.Digest) != 0 {
-			if err := store.Tag(ctx, rootDesc, ref); err != nil {
-				return err
-			}
-		}
-		allDescs = append(allDescs, descs...)
-		return nil
-	}
-
-	// Process and pull links before pulling the requested manifests
+	// Find all linked collections before pulling so the
+	// collections can be pulled in parallel.
+	seen := map[string]struct{}{}
+	var linkedRefs []string
 	tracker := traversal.NewTracker(root, nil)
 	handler := traversal.HandlerFunc(func(ctx context.Context, tracker traversal.Tracker, node model.Node) ([]model.Node, error) {
-		if _, ok := seen[node.ID()]; ok {
-			return nil, traversal.ErrSkip
-		}
-
 		desc, ok := node.(*v2.Node)
 		if !ok {
 			return nil, nil
@@ -223,33 +218,102 @@ func (c *orasClient) PullWithLinks(ctx context.Context, ref string, store conten
 		// Load link and provide access to those nodes.
 		if desc.Properties != nil && desc.Properties.IsALink() {
 			constructedRef := fmt.Sprintf("%s/%s@%s", desc.Properties.Link.RegistryHint, desc.Properties.Link.NamespaceHint, desc.ID())
+			if _, ok := seen[constructedRef]; ok {
+				return nil, traversal.ErrSkip
+			}
+			seen[constructedRef] = struct{}{}
 			linkedCollection, err := c.LoadCollection(ctx, constructedRef)
 			if err != nil {
 				return nil, err
 			}
-			if err := processFunc(constructedRef); err != nil {
-				return nil, err
-			}
+			linkedRefs = append(linkedRefs, constructedRef)
 			return linkedCollection.Nodes(), nil
 		}
 
-		successors := graph.From(node.ID())
-		return successors, err
+		return graph.From(node.ID()), nil
 	})
 
 	if err := tracker.Walk(ctx, handler, root); err != nil {
 		return nil, err
 	}
 
-	if err := processFunc(ref); err != nil {
+	// Pull links before pulling the requested manifests. The concurrency limit
+	// is split between the linked collections pulled in parallel and the blobs
+	// copied for each collection, so no more than the limit of blobs are copied
+	// at once.
+	limit := c.concurrency()
+	parallel := len(linkedRefs)
+	if parallel > limit {
+		parallel = limit
+	}
+	var mu sync.Mutex
+	var linkedDescs []ocispec.Descriptor
+	eg, egCtx := errgroup.WithContext(ctx)
+	if parallel != 0 {
+		eg.SetLimit(parallel)
+	}
+	for _, linkedRef := range linkedRefs {
+		linkedRef := linkedRef
+		eg.Go(func() error {
+			_, descs, err := c.pull(egCtx, linkedRef, store, limit/parallel)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			linkedDescs = append(linkedDescs, descs...)
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	rootDesc, descs, err := c.Pull(ctx, ref, store)
+	if err != nil {
 		return nil, err
 	}
+	// Only the requested reference is tagged. Linked collections
+	// are tracked by digest.
+	if len(rootDesc.Digest) != 0 {
+		if err := store.Tag(ctx, rootDesc, ref); err != nil {
+			return nil, err
+		}
+	}
 
+	// Content shared between linked collections is copied by whichever pull
+	// reaches it first, so the descriptors of the linked collections are
+	// aggregated as a sorted set followed by the requested collection.
+	seenDescs := map[digest.Digest]struct{}{}
+	allDescs := uniqueDescriptors(linkedDescs, seenDescs)
+	allDescs = append(allDescs, uniqueDescriptors(descs, seenDescs)...)
 	return allDescs, nil
 }
 
+// uniqueDescriptors returns the descriptors not in seen sorted by digest
+// and adds them to seen.
+func uniqueDescriptors(descs []ocispec.Descriptor, seen map[digest.Digest]struct{}) []ocispec.Descriptor {
+	var result []ocispec.Descriptor
+	for _, desc := range descs {
+		if _, ok := seen[desc.Digest]; ok {
+			continue
+		}
+		seen[desc.Digest] = struct{}{}
+		result = append(result, desc)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Digest < result[j].Digest
+	})
+	return result
+}
+
 // Pull performs a copy of OCI artifacts to a local location from a remote location.
 func (c *orasClient) Pull(ctx context.Context, ref string, store content.Store) (ocispec.Descriptor, []ocispec.Descriptor, error) {
+	return c.pull(ctx, ref, store, c.concurrency())
+}
+
+// pull performs a copy of OCI artifacts with up to the concurrency limit of blobs copied at once.
+func (c *orasClient) pull(ctx context.Context, ref string, store content.Store, concurrency int) (ocispec.Descriptor, []ocispec.Descriptor, error) {
 	var allDescs []ocispec.Descriptor
 
 	if c.prePullFn != nil {
@@ -259,14 +323,18 @@ func (c *orasClient) Pull(ctx context.Context, ref string, store content.Store)
 	}
 
 	var from oras.Target
-	repo, err := c.setupRepo(ref)
-	if err != nil {
-		return ocispec.Descriptor{}, allDescs, fmt.Errorf("could not create registry target: %w", err)
-	}
-	from = repo
+	if c.offline {
+		from = c.offlineTarget(ref)
+	} else {
+		target, err := c.pullTarget(ref)
+		if err != nil {
+			return ocispec.Descriptor{}, allDescs, fmt.Errorf("could not create registry target: %w", err)
+		}
+		from = target
 
-	if c.cache != nil {
-		from = cache.New(repo, c.cache)
+		if c.cache != nil {
+			from = cache.New(target, c.cache)
+		}
 	}
 
 	graph, err := c.LoadCollection(ctx, ref)
@@ -276,49 +344,12 @@ func (c *orasClient) Pull(ctx context.Context, ref string, store content.Store)
 
 	// Filter the collection per the matcher criteria
 	if c.attributes != nil {
-		var matchedLeaf int
-		matchFn := model.MatcherFunc(func(node model.Node) (bool, error) {
-			// This check ensure we are not weeding out any manifests needed
-			// for OCI DAG traversal.
-			if len(graph.From(node.ID())) != 0 {
-				return true, nil
-			}
-
-			// Check that this is a descriptor node and the blob is
-			// not a config or schema resource.
-			desc, ok := node.(*v2.Node)
-			if !ok {
-				return false, nil
-			}
-
-			switch desc.Descriptor().MediaType {
-			case empspec.MediaTypeSchemaDescriptor:
-				return true, nil
-			case ocispec.MediaTypeImageConfig:
-				return true, nil
-			case empspec.MediaTypeConfiguration:
-				return true, nil
-			}
-
-			match, err := c.attributes.Matches(node)
-			if err != nil {
-				return false, err
-			}
-
-			if match {
-				matchedLeaf++
-			}
-
-			return match, nil
-		})
-
-		var err error
-		graph, err = graph.SubCollection(matchFn)
+		var matched bool
+		graph, matched, err = FilterCollection(graph, c.attributes)
 		if err != nil {
 			return ocispec.Descriptor{}, allDescs, err
 		}
-
-		if matchedLeaf == 0 {
+		if !matched {
 			return ocispec.Descriptor{}, allDescs, nil
 		}
 	}
@@ -348,8 +379,9 @@ func (c *orasClient) Pull(ctx context.Context, ref string, store content.Store)
 	// options are not modified.
 	cCopyOpts := c.copyOpts
 	cCopyOpts.FindSuccessors = successorFn
+	cCopyOpts.Concurrency = int64(concurrency)
 
-	desc, err := oras.Copy(ctx, from, ref, store, ref, cCopyOpts)
+	desc, err := oras.Copy(ctx, c.withProgress(from), ref, store, ref, cCopyOpts)
 	if err != nil {
 		return ocispec.Descriptor{}, allDescs, err
 	}
@@ -357,6 +389,53 @@ func (c *orasClient) Pull(ctx context.Context, ref string, store content.Store)
 	return desc, allDescs, nil
 }
 
+// FilterCollection returns the sub-collection of the graph that is copied when pulling
+// with the matcher set as the pullable attributes and whether any files matched. Manifests,
+// configurations, and schemas are kept so the collection can still be traversed.
+func FilterCollection(graph collection.Collection, matcher model.Matcher) (collection.Collection, bool, error) {
+	var matchedLeaf int
+	matchFn := model.MatcherFunc(func(node model.Node) (bool, error) {
+		// This check ensure we are not weeding out any manifests needed
+		// for OCI DAG traversal.
+		if len(graph.From(node.ID())) != 0 {
+			return true, nil
+		}
+
+		// Check that this is a descriptor node and the blob is
+		// not a config or schema resource.
+		desc, ok := node.(*v2.Node)
+		if !ok {
+			return false, nil
+		}
+
+		switch desc.Descriptor().MediaType {
+		case empspec.MediaTypeSchemaDescriptor:
+			return true, nil
+		case ocispec.MediaTypeImageConfig:
+			return true, nil
+		case empspec.MediaTypeConfiguration:
+			return true, nil
+		}
+
+		match, err := matcher.Matches(node)
+		if err != nil {
+			return false, err
+		}
+
+		if match {
+			matchedLeaf++
+		}
+
+		return match, nil
+	})
+
+	sub, err := graph.SubCollection(matchFn)
+	if err != nil {
+		return collection.Collection{}, false, err
+	}
+	return sub, matchedLeaf != 0, nil
+}
+
 // Push performs a copy of OCI artifacts to a remote location.
 func (c *orasClient) Push(ctx context.Context, store content.Store, ref string) (ocispec.Descriptor, error) {
 	repo, err := c.setupRepo(ref)
@@ -369,25 +448,44 @@ func (c *orasClient) Push(ctx context.Context, store content.Store, ref string)
 	cCopyOpts := c.copyOpts
 	cCopyOpts.FindSuccessors = successorFnWithSparseManifests
 
-	return oras.Copy(ctx, store, ref, repo, ref, cCopyOpts)
+	return oras.Copy(ctx, c.withProgress(store), ref, repo, ref, cCopyOpts)
+}
+
+// PushGraph copies the content graph rooted at the descriptor to the repository
+// of the reference without tagging it.
+func (c *orasClient) PushGraph(ctx context.Context, store content.Store, ref string, desc ocispec.Descriptor) error {
+	repo, err := c.setupRepo(ref)
+	if err != nil {
+		return fmt.Errorf("could not create registry target: %w", err)
+	}
+	return oras.CopyGraph(ctx, c.withProgress(store), repo, desc, c.copyOpts.CopyGraphOptions)
 }
 
 // GetManifest returns the manifest the reference resolves to.
 func (c *orasClient) GetManifest(ctx context.Context, reference string) (ocispec.Descriptor, io.ReadCloser, error) {
-	repo, err := c.setupRepo(reference)
+	if c.offline {
+		return c.offlineTarget(reference).FetchReference(ctx, reference)
+	}
+	target, err := c.pullTarget(reference)
 	if err != nil {
 		return ocispec.Descriptor{}, nil, fmt.Errorf("could not create registry target: %w", err)
 	}
-	return repo.FetchReference(ctx, reference)
+	return target.FetchReference(ctx, reference)
 }
 
 // GetContent retrieves the content for a specified descriptor at a specified reference.
 func (c *orasClient) GetContent(ctx context.Context, reference string, desc ocispec.Descriptor) ([]byte, error) {
-	repo, err := c.setupRepo(reference)
-	if err != nil {
-		return nil, fmt.Errorf("could not create registry target: %w", err)
+	var fetcher orascontent.Fetcher
+	if c.offline {
+		fetcher = c.offlineTarget(reference)
+	} else {
+		target, err := c.pullTarget(reference)
+		if err != nil {
+			return nil, fmt.Errorf("could not create registry target: %w", err)
+		}
+		fetcher = target
 	}
-	r, err := repo.Fetch(ctx, desc)
+	r, err := fetcher.Fetch(ctx, desc)
 	if err != nil {
 		return nil, err
 	}
@@ -405,6 +503,14 @@ func (c *orasClient) Destroy() error {
 	return c.destroy()
 }
 
+// concurrency returns the maximum number of concurrent copy tasks.
+func (c *orasClient) concurrency() int {
+	if c.copyOpts.Concurrency > 0 {
+		return int(c.copyOpts.Concurrency)
+	}
+	return DefaultConcurrency
+}
+
 // checkFileStore ensures that the file store
 // has been initialized.
 func (c *orasClient) checkFileStore() error {
@@ -416,11 +522,20 @@ func (c *orasClient) checkFileStore() error {
 
 // setupRepo configures the client to access the remote repository.
 func (c *orasClient) setupRepo(ref string) (*remote.Repository, error) {
+	if c.offline {
+		return nil, errOffline
+	}
+	return c.newRepository(ref, c.plainHTTP)
+}
+
+// newRepository returns a remote repository for the reference
+// using the client authentication.
+func (c *orasClient) newRepository(ref string, plainHTTP bool) (*remote.Repository, error) {
 	repo, err := remote.NewRepository(ref)
 	if err != nil {
 		return nil, fmt.Errorf("could not create registry target: %w", err)
 	}
-	repo.PlainHTTP = c.plainHTTP
+	repo.PlainHTTP = plainHTTP
 	repo.Client = c.authClient
 	return repo, nil
 }
@@ -0,0 +1,159 @@
+package orasclient
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	orascontent "oras.land/oras-go/v2/content"
+)
+
+// ProgressEventType describes the state of a descriptor
+// being copied.
+type ProgressEventType int
+
+const (
+	// ProgressStarted is emitted before a descriptor is copied.
+	ProgressStarted ProgressEventType = iota
+	// ProgressTransferring is emitted as the descriptor content is read.
+	ProgressTransferring
+	// ProgressDone is emitted after a descriptor is copied.
+	ProgressDone
+	// ProgressSkipped is emitted when a descriptor already exists
+	// at the destination.
+	ProgressSkipped
+)
+
+// String returns the name of the event type.
+func (t ProgressEventType) String() string {
+	switch t {
+	case ProgressStarted:
+		return "started"
+	case ProgressTransferring:
+		return "transferring"
+	case ProgressDone:
+		return "done"
+	case ProgressSkipped:
+		return "skipped"
+	default:
+		return "unknown"
+	}
+}
+
+// ProgressEvent describes the copy progress of a single descriptor.
+type ProgressEvent struct {
+	Type       ProgressEventType
+	Descriptor ocispec.Descriptor
+	// Bytes is the number of bytes of the descriptor
+	// content transferred so far.
+	Bytes int64
+}
+
+// ProgressFunc receives progress events during push, pull, save,
+// and copy operations. It may be called concurrently for different
+// descriptors and should return quickly.
+type ProgressFunc func(ProgressEvent)
+
+// WithProgress emits progress events to the progress function for
+// each descriptor copied by the client.
+func WithProgress(progressFn ProgressFunc) ClientOption {
+	return func(config *ClientConfig) error {
+		config.progressFn = progressFn
+		return nil
+	}
+}
+
+// progressTracker emits progress events for the descriptors
+// being copied.
+type progressTracker struct {
+	progressFn ProgressFunc
+	// active stores the descriptors being copied. Content read
+	// outside of a copy (e.g. to find successors) is not reported.
+	active sync.Map // map[digest.Digest]struct{}
+}
+
+// withHooks wraps the copy options to emit start, done, and skipped events
+// while preserving any user specified hooks.
+func (p *progressTracker) withHooks(opts oras.CopyOptions) oras.CopyOptions {
+	preCopy := opts.PreCopy
+	opts.PreCopy = func(ctx context.Context, desc ocispec.Descriptor) error {
+		if preCopy != nil {
+			if err := preCopy(ctx, desc); err != nil {
+				return err
+			}
+		}
+		p.active.Store(desc.Digest, struct{}{})
+		p.progressFn(ProgressEvent{Type: ProgressStarted, Descriptor: desc})
+		return nil
+	}
+
+	postCopy := opts.PostCopy
+	opts.PostCopy = func(ctx context.Context, desc ocispec.Descriptor) error {
+		p.active.Delete(desc.Digest)
+		p.progressFn(ProgressEvent{Type: ProgressDone, Descriptor: desc, Bytes: desc.Size})
+		if postCopy != nil {
+			return postCopy(ctx, desc)
+		}
+		return nil
+	}
+
+	onCopySkipped := opts.OnCopySkipped
+	opts.OnCopySkipped = func(ctx context.Context, desc ocispec.Descriptor) error {
+		p.progressFn(ProgressEvent{Type: ProgressSkipped, Descriptor: desc, Bytes: desc.Size})
+		if onCopySkipped != nil {
+			return onCopySkipped(ctx, desc)
+		}
+		return nil
+	}
+	return opts
+}
+
+// withProgress wraps the copy source to emit transferring events as
+// content is read. The source is returned unchanged if no progress
+// function is configured.
+func (c *orasClient) withProgress(src oras.ReadOnlyTarget) oras.ReadOnlyTarget {
+	if c.progress == nil {
+		return src
+	}
+	return &progressTarget{ReadOnlyTarget: src, tracker: c.progress}
+}
+
+// progressTarget emits transferring events for content
+// fetched from the underlying target.
+type progressTarget struct {
+	oras.ReadOnlyTarget
+	tracker *progressTracker
+}
+
+// Fetch fetches the content identified by the descriptor.
+func (t *progressTarget) Fetch(ctx context.Context, desc ocispec.Descriptor) (io.ReadCloser, error) {
+	rc, err := t.ReadOnlyTarget.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	return &progressReader{ReadCloser: rc, desc: desc, tracker: t.tracker}, nil
+}
+
+var _ orascontent.ReadOnlyStorage = &progressTarget{}
+
+// progressReader emits a transferring event for each read.
+type progressReader struct {
+	io.ReadCloser
+	desc    ocispec.Descriptor
+	tracker *progressTracker
+	read    int64
+}
+
+// Read reads from the underlying reader and reports the total bytes read.
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.read += int64(n)
+		if _, ok := r.tracker.active.Load(r.desc.Digest); ok {
+			r.tracker.progressFn(ProgressEvent{Type: ProgressTransferring, Descriptor: r.desc, Bytes: r.read})
+		}
+	}
+	return n, err
+}
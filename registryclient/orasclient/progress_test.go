@@ -0,0 +1,108 @@
+package orasclient
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func TestProgress(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	ref := fmt.Sprintf("%s/progress:latest", u.Host)
+	testdata := filepath.Join("testdata", "workspace", "fish.jpg")
+
+	var mu sync.Mutex
+	var events []ProgressEvent
+	progressFn := func(event ProgressEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+	// eventTypes returns the final event type and bytes reported for each descriptor.
+	eventTypes := func() (map[digest.Digest]ProgressEventType, map[digest.Digest]int64) {
+		types := map[digest.Digest]ProgressEventType{}
+		transferred := map[digest.Digest]int64{}
+		for _, event := range events {
+			types[event.Descriptor.Digest] = event.Type
+			transferred[event.Descriptor.Digest] = event.Bytes
+		}
+		return types, transferred
+	}
+
+	var preCopied []ocispec.Descriptor
+	preCopyFn := func(ctx context.Context, desc ocispec.Descriptor) error {
+		mu.Lock()
+		defer mu.Unlock()
+		preCopied = append(preCopied, desc)
+		return nil
+	}
+
+	c, err := NewClient(WithPlainHTTP(true), WithProgress(progressFn), WithPreCopy(preCopyFn))
+	require.NoError(t, err)
+	descs, err := c.AddFiles(ctx, "", testdata)
+	require.NoError(t, err)
+	configDesc, err := c.AddContent(ctx, empspec.MediaTypeConfiguration, []byte("{}"), nil)
+	require.NoError(t, err)
+	_, err = c.AddManifest(ctx, ref, configDesc, nil, descs...)
+	require.NoError(t, err)
+	source, err := c.Store()
+	require.NoError(t, err)
+
+	t.Run("Success/Push", func(t *testing.T) {
+		rootDesc, err := c.Push(ctx, source, ref)
+		require.NoError(t, err)
+		require.Len(t, preCopied, 3)
+
+		types, transferred := eventTypes()
+		require.Len(t, types, 3)
+		for _, desc := range []ocispec.Descriptor{rootDesc, configDesc, descs[0]} {
+			require.Equal(t, ProgressDone, types[desc.Digest])
+			require.Equal(t, desc.Size, transferred[desc.Digest])
+		}
+
+		// The file content is reported as it is read.
+		var transferring int
+		for _, event := range events {
+			if event.Type == ProgressTransferring && event.Descriptor.Digest == descs[0].Digest {
+				transferring++
+				require.LessOrEqual(t, event.Bytes, descs[0].Size)
+			}
+		}
+		require.NotZero(t, transferring)
+	})
+
+	t.Run("Success/PushExisting", func(t *testing.T) {
+		events = nil
+		rootDesc, err := c.Push(ctx, source, ref)
+		require.NoError(t, err)
+		types, _ := eventTypes()
+		require.Equal(t, map[digest.Digest]ProgressEventType{rootDesc.Digest: ProgressSkipped}, types)
+	})
+
+	t.Run("Success/Pull", func(t *testing.T) {
+		events = nil
+		rootDesc, _, err := c.Pull(ctx, ref, memory.New())
+		require.NoError(t, err)
+		types, transferred := eventTypes()
+		require.Len(t, types, 3)
+		require.Equal(t, ProgressDone, types[rootDesc.Digest])
+		require.Equal(t, descs[0].Size, transferred[descs[0].Digest])
+	})
+	require.NoError(t, c.Destroy())
+}
@@ -0,0 +1,75 @@
+package orasclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+)
+
+// Attach pushes an artifact of the artifact type with the files as blobs whose subject
+// is the manifest the reference resolves to. The artifact is packed as an image manifest
+// with the artifact type as the config media type for compatibility with registries that
+// do not support artifact manifests. Registries without the referrers API are indexed
+// with the referrers tag schema.
+func (c *orasClient) Attach(ctx context.Context, reference, artifactType string, annotations map[string]string, files ...string) (ocispec.Descriptor, error) {
+	if artifactType == "" {
+		return ocispec.Descriptor{}, errors.New("artifact type must be set")
+	}
+	if err := c.checkFileStore(); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	repo, err := c.setupRepo(reference)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	subject, err := repo.Resolve(ctx, reference)
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("resolve subject %s: %w", reference, err)
+	}
+
+	blobs, err := c.AddFiles(ctx, "", files...)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	packOpts := PackOptions{
+		Subject:             &ocispec.Descriptor{MediaType: subject.MediaType, Digest: subject.Digest, Size: subject.Size},
+		ManifestAnnotations: annotations,
+		PackImageManifest:   true,
+	}
+	desc, err := Pack(ctx, c.artifactStore, artifactType, blobs, packOpts)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	if err := oras.CopyGraph(ctx, c.withProgress(c.artifactStore), repo, desc, c.copyOpts.CopyGraphOptions); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("push artifact: %w", err)
+	}
+	return desc, nil
+}
+
+// Referrers returns the descriptors of the artifacts whose subject is the manifest
+// the reference resolves to, using the referrers API with the referrers tag schema
+// fallback. If the artifact type is set, only referrers of that type are returned.
+func (c *orasClient) Referrers(ctx context.Context, reference, artifactType string) ([]ocispec.Descriptor, error) {
+	repo, err := c.setupRepo(reference)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := repo.Resolve(ctx, reference)
+	if err != nil {
+		return nil, fmt.Errorf("resolve subject %s: %w", reference, err)
+	}
+
+	var referrers []ocispec.Descriptor
+	err = repo.Referrers(ctx, subject, artifactType, func(page []ocispec.Descriptor) error {
+		referrers = append(referrers, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list referrers of %s: %w", reference, err)
+	}
+	return referrers, nil
+}
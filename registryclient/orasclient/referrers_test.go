@@ -0,0 +1,103 @@
+package orasclient
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/google/go-containerregistry/pkg/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachReferrers(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	ref := fmt.Sprintf("%s/subject:latest", u.Host)
+	sbomType := "application/spdx+json"
+	attestationType := "application/vnd.in-toto+json"
+
+	client, err := NewClient(WithPlainHTTP(true))
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, client.Destroy()) })
+	descs, err := client.AddFiles(ctx, "", filepath.Join("testdata", "workspace", "fish.jpg"))
+	require.NoError(t, err)
+	configDesc, err := client.AddContent(ctx, empspec.MediaTypeConfiguration, []byte("{}"), nil)
+	require.NoError(t, err)
+	subject, err := client.AddManifest(ctx, ref, configDesc, nil, descs...)
+	require.NoError(t, err)
+	source, err := client.Store()
+	require.NoError(t, err)
+	_, err = client.Push(ctx, source, ref)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	sbomFile := filepath.Join(dir, "sbom.spdx.json")
+	require.NoError(t, os.WriteFile(sbomFile, []byte(`{"spdxVersion":"SPDX-2.3"}`), 0600))
+	attestationFile := filepath.Join(dir, "attestation.json")
+	require.NoError(t, os.WriteFile(attestationFile, []byte(`{"_type":"https://in-toto.io/Statement/v0.1"}`), 0600))
+
+	sbom, err := client.Attach(ctx, ref, sbomType, map[string]string{"test": "sbom"}, sbomFile)
+	require.NoError(t, err)
+	require.Equal(t, sbomType, sbom.ArtifactType)
+	attestation, err := client.Attach(ctx, ref, attestationType, nil, attestationFile)
+	require.NoError(t, err)
+
+	type spec struct {
+		name         string
+		reference    string
+		artifactType string
+		exp          []ocispec.Descriptor
+		expError     string
+	}
+
+	cases := []spec{
+		{
+			name:      "Success/AllReferrers",
+			reference: ref,
+			exp:       []ocispec.Descriptor{sbom, attestation},
+		},
+		{
+			name:         "Success/FilterByArtifactType",
+			reference:    ref,
+			artifactType: sbomType,
+			exp:          []ocispec.Descriptor{sbom},
+		},
+		{
+			name:      "Success/ByDigest",
+			reference: fmt.Sprintf("%s/subject@%s", u.Host, subject.Digest),
+			exp:       []ocispec.Descriptor{sbom, attestation},
+		},
+		{
+			name:      "Failure/SubjectNotFound",
+			reference: fmt.Sprintf("%s/subject:missing", u.Host),
+			expError:  fmt.Sprintf("resolve subject %s/subject:missing", u.Host),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			referrers, err := client.Referrers(ctx, c.reference, c.artifactType)
+			if c.expError != "" {
+				require.ErrorContains(t, err, c.expError)
+				return
+			}
+			require.NoError(t, err)
+			require.ElementsMatch(t, c.exp, referrers)
+		})
+	}
+
+	t.Run("Failure/NoArtifactType", func(t *testing.T) {
+		_, err := client.Attach(ctx, ref, "", nil, sbomFile)
+		require.EqualError(t, err, "artifact type must be set")
+	})
+}
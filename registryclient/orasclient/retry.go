@@ -0,0 +1,196 @@
+package orasclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/emporous/emporous-go/log"
+)
+
+// RetryPolicy configures the retries of registry requests that fail
+// with a transient error.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts for a request,
+	// including the first attempt. Requests are not retried if
+	// MaxAttempts is less than two.
+	MaxAttempts int
+	// MinBackoff is the wait time before the first retry. The wait
+	// time doubles with each retry.
+	MinBackoff time.Duration
+	// MaxBackoff limits the wait time between attempts. This includes
+	// wait times requested by the registry with the Retry-After header.
+	// The wait time is not limited if MaxBackoff is zero.
+	MaxBackoff time.Duration
+	// Jitter is the fraction of the wait time that is randomized
+	// between zero and one.
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns the retry policy
+// used by the command line client.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Second,
+		MaxBackoff:  30 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// WithRetryPolicy retries registry requests that fail with a transient
+// error (e.g. 429 or 5xx responses) with exponential backoff.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(config *ClientConfig) error {
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			return errors.New("retry jitter must be between 0 and 1")
+		}
+		if policy.MaxBackoff > 0 && policy.MaxBackoff < policy.MinBackoff {
+			return errors.New("maximum retry backoff must not be less than the minimum retry backoff")
+		}
+		config.retryPolicy = policy
+		return nil
+	}
+}
+
+// retryTransport retries requests with transient
+// failures per the retry policy.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+	logger log.Logger
+	// random returns a number in [0.0,1.0)
+	// to add jitter.
+	random func() float64
+}
+
+// newRetryTransport wraps the base transport with the retry policy. The base
+// transport is returned unchanged if the policy does not allow retries.
+func newRetryTransport(base http.RoundTripper, policy RetryPolicy, logger log.Logger) http.RoundTripper {
+	if policy.MaxAttempts < 2 {
+		return base
+	}
+	return &retryTransport{
+		base:   base,
+		policy: policy,
+		logger: logger,
+		random: rand.Float64,
+	}
+}
+
+// RoundTrip executes a single HTTP transaction and retries it if it fails with
+// a transient error. Requests with a body that cannot be replayed are not retried.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(ctx)
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if attempt >= t.policy.MaxAttempts || !t.replayable(req) || !retryable(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if err != nil {
+			t.debugf("%s %s: %v: retrying in %s (attempt %d of %d)", req.Method, req.URL.Redacted(), err, wait, attempt+1, t.policy.MaxAttempts)
+		} else {
+			t.debugf("%s %s: %s: retrying in %s (attempt %d of %d)", req.Method, req.URL.Redacted(), resp.Status, wait, attempt+1, t.policy.MaxAttempts)
+			// Drain the body so the connection can be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			if err := resp.Body.Close(); err != nil {
+				return nil, err
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// debugf logs retry attempts if a logger is set.
+func (t *retryTransport) debugf(format string, args ...interface{}) {
+	if t.logger != nil {
+		t.logger.Debugf(format, args...)
+	}
+}
+
+// replayable returns whether the request body
+// can be sent again.
+func (t *retryTransport) replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// backoff returns the wait time before the next attempt. The wait time requested
+// by the registry is used if it is set.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp); ok {
+			if t.policy.MaxBackoff > 0 && wait > t.policy.MaxBackoff {
+				return t.policy.MaxBackoff
+			}
+			return wait
+		}
+	}
+
+	wait := float64(t.policy.MinBackoff) * math.Pow(2, float64(attempt-1))
+	if t.policy.MaxBackoff > 0 && wait > float64(t.policy.MaxBackoff) {
+		wait = float64(t.policy.MaxBackoff)
+	}
+	wait -= wait * t.policy.Jitter * t.random()
+	return time.Duration(wait)
+}
+
+// retryable returns whether the response or
+// error is transient.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header
+// in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
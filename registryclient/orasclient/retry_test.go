@@ -0,0 +1,215 @@
+package orasclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content/memory"
+
+	"github.com/emporous/emporous-go/log"
+)
+
+func TestRetryTransport(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		MaxBackoff:  10 * time.Millisecond,
+	}
+
+	type spec struct {
+		name        string
+		statuses    []int
+		headers     map[string]string
+		body        io.Reader
+		expStatus   int
+		expAttempts int
+		expBodies   []string
+	}
+
+	cases := []spec{
+		{
+			name:        "Success/RetryUntilSuccess",
+			statuses:    []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			expStatus:   http.StatusOK,
+			expAttempts: 3,
+		},
+		{
+			name:        "Success/NoRetryForClientError",
+			statuses:    []int{http.StatusNotFound, http.StatusOK},
+			expStatus:   http.StatusNotFound,
+			expAttempts: 1,
+		},
+		{
+			name:        "Success/RetryAfter",
+			statuses:    []int{http.StatusTooManyRequests, http.StatusOK},
+			headers:     map[string]string{"Retry-After": "0"},
+			expStatus:   http.StatusOK,
+			expAttempts: 2,
+		},
+		{
+			name:        "Success/ReplayBody",
+			statuses:    []int{http.StatusBadGateway, http.StatusOK},
+			body:        bytes.NewReader([]byte("manifest")),
+			expStatus:   http.StatusOK,
+			expAttempts: 2,
+			expBodies:   []string{"manifest", "manifest"},
+		},
+		{
+			name:        "Success/NoReplayableBody",
+			statuses:    []int{http.StatusBadGateway, http.StatusOK},
+			body:        io.MultiReader(bytes.NewReader([]byte("blob"))),
+			expStatus:   http.StatusBadGateway,
+			expAttempts: 1,
+			expBodies:   []string{"blob"},
+		},
+		{
+			name:        "Failure/MaxAttemptsReached",
+			statuses:    []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+			expStatus:   http.StatusInternalServerError,
+			expAttempts: 3,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var attempts int
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				if r.Body != nil {
+					body, err := io.ReadAll(r.Body)
+					require.NoError(t, err)
+					if len(body) != 0 {
+						bodies = append(bodies, string(body))
+					}
+				}
+				for k, v := range c.headers {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(c.statuses[attempts])
+				attempts++
+			}))
+			t.Cleanup(server.Close)
+
+			var logged bytes.Buffer
+			logger, err := log.NewLogrusLogger(&logged, "debug")
+			require.NoError(t, err)
+			client := &http.Client{Transport: newRetryTransport(http.DefaultTransport, policy, logger)}
+			req, err := http.NewRequest(http.MethodPut, server.URL, c.body)
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+
+			require.Equal(t, c.expStatus, resp.StatusCode)
+			require.Equal(t, c.expAttempts, attempts)
+			require.Equal(t, c.expBodies, bodies)
+			if c.expAttempts > 1 {
+				require.Contains(t, logged.String(), fmt.Sprintf("(attempt %d of 3)", c.expAttempts))
+			}
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	transport := &retryTransport{
+		policy: RetryPolicy{
+			MaxAttempts: 5,
+			MinBackoff:  time.Second,
+			MaxBackoff:  3 * time.Second,
+			Jitter:      0.5,
+		},
+		random: func() float64 {
+			return 0.5
+		},
+	}
+	require.Equal(t, 750*time.Millisecond, transport.backoff(1, nil))
+	require.Equal(t, 1500*time.Millisecond, transport.backoff(2, nil))
+	require.Equal(t, 2250*time.Millisecond, transport.backoff(3, nil))
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "2")
+	require.Equal(t, 2*time.Second, transport.backoff(1, resp))
+	resp.Header.Set("Retry-After", "120")
+	require.Equal(t, 3*time.Second, transport.backoff(1, resp))
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	policy := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Hour}
+	client := &http.Client{Transport: newRetryTransport(http.DefaultTransport, policy, nil)}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	_, err := NewClient(WithRetryPolicy(RetryPolicy{MaxAttempts: 2, Jitter: 2}))
+	require.EqualError(t, err, "retry jitter must be between 0 and 1")
+	_, err = NewClient(WithRetryPolicy(RetryPolicy{MaxAttempts: 2, MinBackoff: time.Second, MaxBackoff: time.Millisecond}))
+	require.EqualError(t, err, "maximum retry backoff must not be less than the minimum retry backoff")
+
+	// The first registry request for each location fails with a transient error.
+	// Streamed blob uploads cannot be replayed, so they do not fail.
+	var mu sync.Mutex
+	requests := map[string]int{}
+	reg := registry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		key := r.Method + " " + r.URL.String()
+		requests[key]++
+		fail := requests[key] == 1 && r.Method != http.MethodPut
+		mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	ctx := context.TODO()
+	ref := fmt.Sprintf("%s/retry:latest", u.Host)
+	policy := RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}
+	c, err := NewClient(WithPlainHTTP(true), WithRetryPolicy(policy))
+	require.NoError(t, err)
+	descs, err := c.AddFiles(ctx, "", filepath.Join("testdata", "workspace", "fish.jpg"))
+	require.NoError(t, err)
+	configDesc, err := c.AddContent(ctx, empspec.MediaTypeConfiguration, []byte("{}"), nil)
+	require.NoError(t, err)
+	_, err = c.AddManifest(ctx, ref, configDesc, nil, descs...)
+	require.NoError(t, err)
+	source, err := c.Store()
+	require.NoError(t, err)
+
+	_, _, err = c.GetManifest(ctx, ref)
+	require.EqualError(t, err, fmt.Sprintf("%s: not found", ref))
+	_, err = c.Push(ctx, source, ref)
+	require.NoError(t, err)
+	_, _, err = c.Pull(ctx, ref, memory.New())
+	require.NoError(t, err)
+	require.NoError(t, c.Destroy())
+}
@@ -0,0 +1,139 @@
+package orasclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+)
+
+// WithCAFile trusts the certificate authorities in the PEM encoded file in
+// addition to the system certificate authorities when connecting to registries.
+func WithCAFile(caFile string) ClientOption {
+	return func(config *ClientConfig) error {
+		config.tlsFiles.CAFile = caFile
+		return nil
+	}
+}
+
+// WithClientCertificate authenticates to registries with the PEM encoded
+// client certificate and private key (mutual TLS).
+func WithClientCertificate(certFile, keyFile string) ClientOption {
+	return func(config *ClientConfig) error {
+		if (certFile == "") != (keyFile == "") {
+			return errors.New("client certificate and key files must be set together")
+		}
+		config.tlsFiles.CertFile = certFile
+		config.tlsFiles.KeyFile = keyFile
+		return nil
+	}
+}
+
+// NewTransport returns the HTTP transport the client uses to connect to registries
+// per the TLS options (SkipTLSVerify, WithCAFile, WithClientCertificate, and the TLS
+// settings of WithRegistryConfig). Other options are ignored. This allows other
+// registry clients (e.g. signature verification) to use the same TLS settings.
+func NewTransport(options ...ClientOption) (*http.Transport, error) {
+	config := &ClientConfig{}
+	if err := config.apply(options); err != nil {
+		return nil, err
+	}
+	return config.transport()
+}
+
+// transport returns an HTTP transport configured with the TLS settings
+// of the client and of each registry.
+func (c *ClientConfig) transport() (*http.Transport, error) {
+	defaultConfig, err := newTLSConfig(c.tlsFiles, c.insecure)
+	if err != nil {
+		return nil, err
+	}
+	transport := &http.Transport{
+		TLSClientConfig: defaultConfig,
+	}
+
+	hostConfigs := map[string]*tls.Config{}
+	for _, reg := range c.registries {
+		if reg.TLS == (clientapi.RegistryTLS{}) {
+			continue
+		}
+		files := c.tlsFiles
+		if reg.TLS.CAFile != "" {
+			files.CAFile = reg.TLS.CAFile
+		}
+		if reg.TLS.CertFile != "" || reg.TLS.KeyFile != "" {
+			if reg.TLS.CertFile == "" || reg.TLS.KeyFile == "" {
+				return nil, fmt.Errorf("registry %s: client certificate and key files must be set together", reg.Location)
+			}
+			files.CertFile = reg.TLS.CertFile
+			files.KeyFile = reg.TLS.KeyFile
+		}
+		hostConfig, err := newTLSConfig(files, c.insecure)
+		if err != nil {
+			return nil, fmt.Errorf("registry %s: %w", reg.Location, err)
+		}
+		host, _, _ := strings.Cut(reg.Location, "/")
+		hostConfigs[host] = hostConfig
+	}
+	if len(hostConfigs) == 0 {
+		return transport, nil
+	}
+
+	// Select the TLS configuration by the registry host when
+	// establishing the connection.
+	transport.DialTLSContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		tlsConfig := defaultConfig
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if hostConfig, ok := hostConfigs[addr]; ok {
+			tlsConfig = hostConfig
+		} else if hostConfig, ok := hostConfigs[host]; ok {
+			tlsConfig = hostConfig
+		}
+		dialer := &tls.Dialer{Config: tlsConfig}
+		return dialer.DialContext(ctx, network, addr)
+	}
+	return transport, nil
+}
+
+// newTLSConfig returns a TLS configuration
+// from PEM encoded files.
+func newTLSConfig(files clientapi.RegistryTLS, insecure bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: insecure,
+	}
+
+	if files.CAFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		caPEM, err := os.ReadFile(filepath.Clean(files.CAFile))
+		if err != nil {
+			return nil, err
+		}
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("%s: no certificates found", files.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if files.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(files.CertFile, files.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,174 @@
+package orasclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/stretchr/testify/require"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+)
+
+func TestTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, caFile := writeCA(t, dir)
+	serverCert := issueCert(t, ca, caKey, x509.ExtKeyUsageServerAuth)
+	certFile, keyFile := writeCert(t, dir, "client", issueCert(t, ca, caKey, x509.ExtKeyUsageClientAuth))
+	emptyFile := filepath.Join(dir, "empty.pem")
+	require.NoError(t, os.WriteFile(emptyFile, nil, 0600))
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	server := httptest.NewUnstartedServer(registry.New())
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	ref := fmt.Sprintf("%s/tls:latest", u.Host)
+
+	type spec struct {
+		name     string
+		opts     []ClientOption
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/MutualTLS",
+			opts: []ClientOption{WithCAFile(caFile), WithClientCertificate(certFile, keyFile)},
+		},
+		{
+			name: "Success/RegistryConfig",
+			opts: []ClientOption{WithRegistryConfig(clientapi.RegistryConfiguration{
+				Registries: []clientapi.Registry{
+					{
+						Location: u.Host + "/tls",
+						TLS:      clientapi.RegistryTLS{CAFile: caFile, CertFile: certFile, KeyFile: keyFile},
+					},
+				},
+			})},
+		},
+		{
+			name:     "Failure/UnknownAuthority",
+			opts:     []ClientOption{WithClientCertificate(certFile, keyFile)},
+			expError: "certificate signed by unknown authority",
+		},
+		{
+			name:     "Failure/NoClientCertificate",
+			opts:     []ClientOption{WithCAFile(caFile)},
+			expError: "remote error: tls",
+		},
+		{
+			name:     "Failure/MissingKeyFile",
+			opts:     []ClientOption{WithClientCertificate(certFile, "")},
+			expError: "client certificate and key files must be set together",
+		},
+		{
+			name:     "Failure/NoCertificatesInCAFile",
+			opts:     []ClientOption{WithCAFile(emptyFile)},
+			expError: fmt.Sprintf("%s: no certificates found", emptyFile),
+		},
+	}
+
+	ctx := context.TODO()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, err := NewClient(c.opts...)
+			if err != nil {
+				require.EqualError(t, err, c.expError)
+				return
+			}
+			descs, err := client.AddFiles(ctx, "", filepath.Join("testdata", "workspace", "fish.jpg"))
+			require.NoError(t, err)
+			configDesc, err := client.AddContent(ctx, empspec.MediaTypeConfiguration, []byte("{}"), nil)
+			require.NoError(t, err)
+			_, err = client.AddManifest(ctx, ref, configDesc, nil, descs...)
+			require.NoError(t, err)
+			source, err := client.Store()
+			require.NoError(t, err)
+			_, err = client.Push(ctx, source, ref)
+			if c.expError != "" {
+				require.ErrorContains(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, client.Destroy())
+		})
+	}
+}
+
+// writeCA writes a self-signed certificate authority to
+// the directory and returns the certificate and key.
+func writeCA(t *testing.T, dir string) (*x509.Certificate, *ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	return ca, key, caFile
+}
+
+// issueCert issues a certificate for 127.0.0.1
+// signed by the certificate authority.
+func issueCert(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, usage x509.ExtKeyUsage) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// writeCert writes the certificate and key
+// to the directory.
+func writeCert(t *testing.T, dir, name string, cert tls.Certificate) (string, string) {
+	keyDER, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	require.NoError(t, err)
+	certFile := filepath.Join(dir, name+".pem")
+	keyFile := filepath.Join(dir, name+"-key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
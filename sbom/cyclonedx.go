@@ -0,0 +1,137 @@
+package sbom
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// cycloneDXDocument is the subset of the CycloneDX 1.4 JSON schema
+// used to describe collections.
+type cycloneDXDocument struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     cycloneDXMetadata    `json:"metadata"`
+	Components   []cycloneDXComponent `json:"components,omitempty"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     []cycloneDXTool    `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTool struct {
+	Vendor  string `json:"vendor"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type cycloneDXComponent struct {
+	BOMRef     string              `json:"bom-ref"`
+	Type       string              `json:"type"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	Hashes     []cycloneDXHash     `json:"hashes,omitempty"`
+	Licenses   []cycloneDXLicense  `json:"licenses,omitempty"`
+	CPE        string              `json:"cpe,omitempty"`
+	PURL       string              `json:"purl,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDXHash struct {
+	Algorithm string `json:"alg"`
+	Content   string `json:"content"`
+}
+
+type cycloneDXLicense struct {
+	License    *cycloneDXLicenseChoice `json:"license,omitempty"`
+	Expression string                  `json:"expression,omitempty"`
+}
+
+type cycloneDXLicenseChoice struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// spdxIdentifier matches license values that can be used as SPDX
+// license identifiers rather than names or expressions.
+var spdxIdentifier = regexp.MustCompile(`^[A-Za-z0-9.+-]+$`)
+
+// newCycloneDXDocument describes the collection as the metadata
+// component and each file as a component. Files with component
+// information are described as libraries with the file location
+// as a property.
+func newCycloneDXDocument(s subject) cycloneDXDocument {
+	doc := cycloneDXDocument{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.4",
+		SerialNumber: uuid.New().URN(),
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: s.created.Format(time.RFC3339),
+			Tools: []cycloneDXTool{
+				{Vendor: "emporous", Name: "emporous", Version: strings.TrimPrefix(s.tool, "emporous-")},
+			},
+			Component: newCycloneDXComponent(s.root.digest.String(), "application", s.root),
+		},
+	}
+	for _, file := range s.files {
+		if file.component == nil {
+			doc.Components = append(doc.Components, newCycloneDXComponent(file.name, "file", file))
+			continue
+		}
+		c := newCycloneDXComponent(file.name, "library", file)
+		c.Properties = []cycloneDXProperty{{Name: "emporous:location", Value: file.name}}
+		doc.Components = append(doc.Components, c)
+	}
+	return doc
+}
+
+func newCycloneDXComponent(bomRef, componentType string, i item) cycloneDXComponent {
+	c := cycloneDXComponent{
+		BOMRef:  bomRef,
+		Type:    componentType,
+		Name:    i.name,
+		Version: i.version(),
+		Hashes: []cycloneDXHash{
+			{Algorithm: cycloneDXAlgorithm(i.digest.Algorithm().String()), Content: i.digest.Encoded()},
+		},
+	}
+	for _, license := range i.licenses() {
+		switch {
+		case spdxIdentifier.MatchString(license):
+			c.Licenses = append(c.Licenses, cycloneDXLicense{License: &cycloneDXLicenseChoice{ID: license}})
+		case strings.Contains(license, " "):
+			c.Licenses = append(c.Licenses, cycloneDXLicense{Expression: license})
+		default:
+			c.Licenses = append(c.Licenses, cycloneDXLicense{License: &cycloneDXLicenseChoice{Name: license}})
+		}
+	}
+	if i.component == nil {
+		return c
+	}
+	if i.component.Name != "" {
+		c.Name = i.component.Name
+	}
+	if len(i.component.CPEs) != 0 {
+		c.CPE = i.component.CPEs[0]
+	}
+	c.PURL = i.component.PURL
+	return c
+}
+
+// cycloneDXAlgorithm converts a digest algorithm (e.g. sha256)
+// to a CycloneDX hash algorithm (e.g. SHA-256).
+func cycloneDXAlgorithm(algorithm string) string {
+	return strings.Replace(strings.ToUpper(algorithm), "SHA", "SHA-", 1)
+}
@@ -0,0 +1,167 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry"
+
+	"github.com/emporous/emporous-go/nodes/collection"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+	"github.com/emporous/emporous-go/version"
+)
+
+const (
+	// FormatSPDXJSON is the SPDX 2.3 JSON format.
+	FormatSPDXJSON = "spdx-json"
+	// FormatCycloneDXJSON is the CycloneDX 1.4 JSON format.
+	FormatCycloneDXJSON = "cyclonedx-json"
+
+	// MediaTypeSPDXJSON is the media type of SPDX JSON documents.
+	MediaTypeSPDXJSON = "application/spdx+json"
+	// MediaTypeCycloneDXJSON is the media type of CycloneDX JSON documents.
+	MediaTypeCycloneDXJSON = "application/vnd.cyclonedx+json"
+)
+
+// Formats are the supported SBOM formats.
+var Formats = []string{FormatSPDXJSON, FormatCycloneDXJSON}
+
+// MediaType returns the media type of documents in the format.
+func MediaType(format string) (string, error) {
+	switch format {
+	case FormatSPDXJSON:
+		return MediaTypeSPDXJSON, nil
+	case FormatCycloneDXJSON:
+		return MediaTypeCycloneDXJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported SBOM format %q, must be one of %v", format, Formats)
+	}
+}
+
+// Generate writes an SBOM document in the format for the collection
+// loaded from the reference. The collection manifest is described as the
+// main component and each file in the collection is listed with its digest
+// and the component information set in its core-descriptor attributes.
+func Generate(w io.Writer, format, reference string, co collection.Collection) error {
+	if _, err := MediaType(format); err != nil {
+		return err
+	}
+	subject, err := newSubject(reference, co)
+	if err != nil {
+		return err
+	}
+
+	var doc interface{}
+	switch format {
+	case FormatSPDXJSON:
+		doc = newSPDXDocument(subject)
+	case FormatCycloneDXJSON:
+		doc = newCycloneDXDocument(subject)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// subject is the collection an SBOM document describes.
+type subject struct {
+	reference string
+	created   time.Time
+	tool      string
+	root      item
+	files     []item
+}
+
+// item is the collection manifest or a file in the collection.
+type item struct {
+	name      string
+	digest    digest.Digest
+	component *empspec.Component
+}
+
+// version returns the version of the component, if set.
+func (i item) version() string {
+	if i.component == nil {
+		return ""
+	}
+	return i.component.Version
+}
+
+// licenses returns the licenses of the component, if set.
+func (i item) licenses() []string {
+	if i.component == nil {
+		return nil
+	}
+	return i.component.Licenses
+}
+
+func newSubject(reference string, co collection.Collection) (subject, error) {
+	ref, err := registry.ParseReference(reference)
+	if err != nil {
+		return subject{}, err
+	}
+	rootNode, err := co.Root()
+	if err != nil {
+		return subject{}, err
+	}
+	root, ok := rootNode.(*v2.Node)
+	if !ok {
+		return subject{}, fmt.Errorf("collection %s: root is not a descriptor", reference)
+	}
+
+	s := subject{
+		reference: reference,
+		created:   time.Now().UTC(),
+		tool:      "emporous-" + version.GetVersion(),
+		root: item{
+			name:      ref.Registry + "/" + ref.Repository,
+			digest:    root.Descriptor().Digest,
+			component: component(root),
+		},
+	}
+	if s.root.component == nil {
+		s.root.component = &empspec.Component{Name: s.root.name}
+		if _, err := ref.Digest(); err != nil {
+			s.root.component.Version = ref.Reference
+		}
+	}
+
+	for _, n := range co.Nodes() {
+		node, ok := n.(*v2.Node)
+		if !ok || node.Properties.IsALink() {
+			continue
+		}
+		desc := node.Descriptor()
+		title, ok := desc.Annotations[ocispec.AnnotationTitle]
+		if !ok {
+			continue
+		}
+		s.files = append(s.files, item{
+			name:      title,
+			digest:    desc.Digest,
+			component: component(node),
+		})
+	}
+	// Keep the document order deterministic
+	sort.Slice(s.files, func(i, j int) bool {
+		return s.files[i].name < s.files[j].name
+	})
+	return s, nil
+}
+
+// component returns the component information in the core-descriptor
+// attributes of the node or nil if none is set.
+func component(node *v2.Node) *empspec.Component {
+	if node.Properties == nil || !node.Properties.IsAComponent() {
+		return nil
+	}
+	c := node.Properties.Descriptor.Component
+	return &c
+}
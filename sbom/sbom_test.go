@@ -0,0 +1,153 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	empspec "github.com/emporous/collection-spec/specs-go/v1alpha1"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/nodes/collection"
+	"github.com/emporous/emporous-go/nodes/descriptor/v2"
+)
+
+const testReference = "localhost:5001/test:latest"
+
+func TestGenerateSPDX(t *testing.T) {
+	co := makeTestCollection(t)
+	out := new(bytes.Buffer)
+	require.NoError(t, Generate(out, FormatSPDXJSON, testReference, co))
+
+	var doc spdxDocument
+	require.NoError(t, json.Unmarshal(out.Bytes(), &doc))
+	require.Equal(t, spdxVersion, doc.SPDXVersion)
+	require.Equal(t, testReference, doc.Name)
+	require.Equal(t, "https://emporous.io/spdxdocs/localhost:5001/test-"+digest.FromString("manifest").Encoded(), doc.DocumentNamespace)
+
+	require.Len(t, doc.Packages, 2)
+	require.Equal(t, "app", doc.Packages[0].Name)
+	require.Equal(t, "v1.0.0", doc.Packages[0].VersionInfo)
+	require.Equal(t, "Apache-2.0", doc.Packages[0].LicenseDeclared)
+	require.Equal(t, []spdxExternalRef{
+		{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:generic/app@v1.0.0"},
+	}, doc.Packages[0].ExternalRefs)
+	require.Equal(t, "lib", doc.Packages[1].Name)
+	require.Equal(t, "MIT AND (BSD-2-Clause OR GPL-2.0-only)", doc.Packages[1].LicenseDeclared)
+	require.Equal(t, "cpe:2.3:a:lib:lib:v2.0.0:*:*:*:*:*:*:*", doc.Packages[1].ExternalRefs[0].ReferenceLocator)
+
+	require.Len(t, doc.Files, 2)
+	require.Equal(t, "lib.so", doc.Files[0].FileName)
+	require.Equal(t, []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: digest.FromString("lib").Encoded()}}, doc.Files[0].Checksums)
+	require.Equal(t, []string{"MIT", "BSD-2-Clause OR GPL-2.0-only"}, doc.Files[0].LicenseInfoInFiles)
+	require.Equal(t, "readme.md", doc.Files[1].FileName)
+	require.Empty(t, doc.Files[1].LicenseInfoInFiles)
+
+	require.Equal(t, []spdxRelationship{
+		{SPDXElementID: spdxDocumentID, RelationshipType: "DESCRIBES", RelatedSPDXElement: spdxRootID},
+		{SPDXElementID: spdxRootID, RelationshipType: "CONTAINS", RelatedSPDXElement: "SPDXRef-File-0"},
+		{SPDXElementID: "SPDXRef-Package-0", RelationshipType: "CONTAINS", RelatedSPDXElement: "SPDXRef-File-0"},
+		{SPDXElementID: spdxRootID, RelationshipType: "CONTAINS", RelatedSPDXElement: "SPDXRef-File-1"},
+	}, doc.Relationships)
+}
+
+func TestGenerateCycloneDX(t *testing.T) {
+	co := makeTestCollection(t)
+	out := new(bytes.Buffer)
+	require.NoError(t, Generate(out, FormatCycloneDXJSON, testReference, co))
+
+	var doc cycloneDXDocument
+	require.NoError(t, json.Unmarshal(out.Bytes(), &doc))
+	require.Equal(t, "CycloneDX", doc.BOMFormat)
+	require.Equal(t, "1.4", doc.SpecVersion)
+	require.Regexp(t, "^urn:uuid:", doc.SerialNumber)
+
+	require.Equal(t, cycloneDXComponent{
+		BOMRef:   digest.FromString("manifest").String(),
+		Type:     "application",
+		Name:     "app",
+		Version:  "v1.0.0",
+		Hashes:   []cycloneDXHash{{Algorithm: "SHA-256", Content: digest.FromString("manifest").Encoded()}},
+		Licenses: []cycloneDXLicense{{License: &cycloneDXLicenseChoice{ID: "Apache-2.0"}}},
+		PURL:     "pkg:generic/app@v1.0.0",
+	}, doc.Metadata.Component)
+
+	require.Len(t, doc.Components, 2)
+	require.Equal(t, cycloneDXComponent{
+		BOMRef:  "lib.so",
+		Type:    "library",
+		Name:    "lib",
+		Version: "v2.0.0",
+		Hashes:  []cycloneDXHash{{Algorithm: "SHA-256", Content: digest.FromString("lib").Encoded()}},
+		Licenses: []cycloneDXLicense{
+			{License: &cycloneDXLicenseChoice{ID: "MIT"}},
+			{Expression: "BSD-2-Clause OR GPL-2.0-only"},
+		},
+		CPE:        "cpe:2.3:a:lib:lib:v2.0.0:*:*:*:*:*:*:*",
+		Properties: []cycloneDXProperty{{Name: "emporous:location", Value: "lib.so"}},
+	}, doc.Components[0])
+	require.Equal(t, "file", doc.Components[1].Type)
+	require.Equal(t, "readme.md", doc.Components[1].Name)
+}
+
+func TestGenerateUnsupportedFormat(t *testing.T) {
+	co := makeTestCollection(t)
+	err := Generate(new(bytes.Buffer), "spdx-tag-value", testReference, co)
+	require.EqualError(t, err, "unsupported SBOM format \"spdx-tag-value\", must be one of [spdx-json cyclonedx-json]")
+}
+
+// makeTestCollection returns a collection with a manifest, a config and
+// two files where the manifest and one file have component information.
+func makeTestCollection(t *testing.T) collection.Collection {
+	node := func(content, mediaType string, annotations map[string]string) *v2.Node {
+		desc := ocispec.Descriptor{
+			MediaType:   mediaType,
+			Digest:      digest.FromString(content),
+			Size:        int64(len(content)),
+			Annotations: annotations,
+		}
+		n, err := v2.NewNode(desc.Digest.String(), desc)
+		require.NoError(t, err)
+		return n
+	}
+	attributes := func(component empspec.Component) string {
+		attr, err := json.Marshal(map[string]interface{}{
+			"core-descriptor": empspec.DescriptorAttributes{Component: component},
+		})
+		require.NoError(t, err)
+		return string(attr)
+	}
+
+	root := node("manifest", ocispec.MediaTypeImageManifest, map[string]string{
+		empspec.AnnotationEmporousAttributes: attributes(empspec.Component{
+			Name:     "app",
+			Version:  "v1.0.0",
+			Licenses: []string{"Apache-2.0"},
+			PURL:     "pkg:generic/app@v1.0.0",
+		}),
+	})
+	config := node("config", empspec.MediaTypeConfiguration, nil)
+	lib := node("lib", "application/octet-stream", map[string]string{
+		ocispec.AnnotationTitle: "lib.so",
+		empspec.AnnotationEmporousAttributes: attributes(empspec.Component{
+			Name:     "lib",
+			Version:  "v2.0.0",
+			Licenses: []string{"MIT", "BSD-2-Clause OR GPL-2.0-only"},
+			CPEs:     []string{"cpe:2.3:a:lib:lib:v2.0.0:*:*:*:*:*:*:*"},
+		}),
+	})
+	readme := node("readme", "text/markdown", map[string]string{
+		ocispec.AnnotationTitle: "readme.md",
+	})
+
+	co := collection.New(testReference)
+	for _, n := range []*v2.Node{root, config, lib, readme} {
+		require.NoError(t, co.AddNode(n))
+	}
+	for _, n := range []*v2.Node{config, lib, readme} {
+		require.NoError(t, co.AddEdge(collection.NewEdge(root, n)))
+	}
+	return *co
+}
@@ -0,0 +1,180 @@
+package sbom
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	spdxVersion     = "SPDX-2.3"
+	spdxNoAssertion = "NOASSERTION"
+	spdxDocumentID  = "SPDXRef-DOCUMENT"
+	spdxRootID      = "SPDXRef-Package-collection"
+)
+
+// spdxDocument is the subset of the SPDX 2.3 JSON schema
+// used to describe collections.
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Files             []spdxFile         `json:"files,omitempty"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxFile struct {
+	SPDXID             string         `json:"SPDXID"`
+	FileName           string         `json:"fileName"`
+	Checksums          []spdxChecksum `json:"checksums"`
+	LicenseConcluded   string         `json:"licenseConcluded"`
+	LicenseInfoInFiles []string       `json:"licenseInfoInFiles,omitempty"`
+	CopyrightText      string         `json:"copyrightText"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// newSPDXDocument describes the collection as a package containing
+// its files. Files with component information are also described
+// as packages that contain the file.
+func newSPDXDocument(s subject) spdxDocument {
+	doc := spdxDocument{
+		SPDXVersion:       spdxVersion,
+		DataLicense:       "CC0-1.0",
+		SPDXID:            spdxDocumentID,
+		Name:              s.reference,
+		DocumentNamespace: fmt.Sprintf("https://emporous.io/spdxdocs/%s-%s", s.root.name, s.root.digest.Encoded()),
+		CreationInfo: spdxCreationInfo{
+			Created:  s.created.Format(time.RFC3339),
+			Creators: []string{"Tool: " + s.tool},
+		},
+		Relationships: []spdxRelationship{
+			{SPDXElementID: spdxDocumentID, RelationshipType: "DESCRIBES", RelatedSPDXElement: spdxRootID},
+		},
+	}
+
+	rootPkg := newSPDXPackage(spdxRootID, s.root)
+	rootPkg.DownloadLocation = s.reference
+	rootPkg.FilesAnalyzed = len(s.files) != 0
+	doc.Packages = append(doc.Packages, rootPkg)
+
+	for i, file := range s.files {
+		fileID := fmt.Sprintf("SPDXRef-File-%d", i)
+		doc.Files = append(doc.Files, spdxFile{
+			SPDXID:             fileID,
+			FileName:           file.name,
+			Checksums:          spdxChecksums(file),
+			LicenseConcluded:   spdxNoAssertion,
+			LicenseInfoInFiles: file.licenses(),
+			CopyrightText:      spdxNoAssertion,
+		})
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID: spdxRootID, RelationshipType: "CONTAINS", RelatedSPDXElement: fileID,
+		})
+
+		if file.component == nil {
+			continue
+		}
+		pkgID := fmt.Sprintf("SPDXRef-Package-%d", i)
+		doc.Packages = append(doc.Packages, newSPDXPackage(pkgID, file))
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID: pkgID, RelationshipType: "CONTAINS", RelatedSPDXElement: fileID,
+		})
+	}
+	return doc
+}
+
+func newSPDXPackage(id string, i item) spdxPackage {
+	pkg := spdxPackage{
+		SPDXID:           id,
+		Name:             i.name,
+		VersionInfo:      i.version(),
+		DownloadLocation: spdxNoAssertion,
+		Checksums:        spdxChecksums(i),
+		LicenseConcluded: spdxNoAssertion,
+		LicenseDeclared:  spdxLicenseExpression(i.licenses()),
+		CopyrightText:    spdxNoAssertion,
+	}
+	if i.component == nil {
+		return pkg
+	}
+	if i.component.Name != "" {
+		pkg.Name = i.component.Name
+	}
+	if i.component.PURL != "" {
+		pkg.ExternalRefs = append(pkg.ExternalRefs, spdxExternalRef{
+			ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: i.component.PURL,
+		})
+	}
+	for _, cpe := range i.component.CPEs {
+		pkg.ExternalRefs = append(pkg.ExternalRefs, spdxExternalRef{
+			ReferenceCategory: "SECURITY", ReferenceType: "cpe23Type", ReferenceLocator: cpe,
+		})
+	}
+	return pkg
+}
+
+func spdxChecksums(i item) []spdxChecksum {
+	return []spdxChecksum{
+		{
+			Algorithm:     strings.ToUpper(i.digest.Algorithm().String()),
+			ChecksumValue: i.digest.Encoded(),
+		},
+	}
+}
+
+// spdxLicenseExpression joins the licenses into a
+// conjunctive license expression.
+func spdxLicenseExpression(licenses []string) string {
+	switch len(licenses) {
+	case 0:
+		return spdxNoAssertion
+	case 1:
+		return licenses[0]
+	}
+	var terms []string
+	for _, license := range licenses {
+		if strings.Contains(license, " ") {
+			license = "(" + license + ")"
+		}
+		terms = append(terms, license)
+	}
+	return strings.Join(terms, " AND ")
+}
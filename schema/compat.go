@@ -0,0 +1,420 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Compatibility defines which schema changes are
+// allowed between two versions of a schema.
+type Compatibility string
+
+const (
+	// CompatibilityBackward allows changes where attributes valid for the
+	// previous schema are valid for the new schema.
+	CompatibilityBackward Compatibility = "backward"
+	// CompatibilityForward allows changes where attributes valid for the
+	// new schema are valid for the previous schema.
+	CompatibilityForward Compatibility = "forward"
+	// CompatibilityFull allows changes that are both backward
+	// and forward compatible.
+	CompatibilityFull Compatibility = "full"
+	// CompatibilityNone allows all changes.
+	CompatibilityNone Compatibility = "none"
+)
+
+// Compatibilities lists the supported compatibility modes.
+var Compatibilities = []Compatibility{CompatibilityBackward, CompatibilityForward, CompatibilityFull, CompatibilityNone}
+
+// ParseCompatibility returns the Compatibility for a string.
+func ParseCompatibility(s string) (Compatibility, error) {
+	for _, c := range Compatibilities {
+		if string(c) == s {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported compatibility mode %q, must be one of %v", s, Compatibilities)
+}
+
+// ChangeType represents the kind of change made to
+// an attribute between two versions of a schema.
+type ChangeType string
+
+const (
+	ChangeAddedOptional      ChangeType = "added-optional"
+	ChangeAddedRequired      ChangeType = "added-required"
+	ChangeRemovedOptional    ChangeType = "removed-optional"
+	ChangeRemovedRequired    ChangeType = "removed-required"
+	ChangeMadeRequired       ChangeType = "made-required"
+	ChangeMadeOptional       ChangeType = "made-optional"
+	ChangeTypeNarrowed       ChangeType = "type-narrowed"
+	ChangeTypeWidened        ChangeType = "type-widened"
+	ChangeTypeChanged        ChangeType = "type-changed"
+	ChangeConstraintNarrowed ChangeType = "constraint-narrowed"
+	ChangeConstraintWidened  ChangeType = "constraint-widened"
+	ChangeConstraintChanged  ChangeType = "constraint-changed"
+)
+
+// compatibilityByChange maps each change type to whether it is backward
+// and forward compatible. Keys that are not defined in a schema are allowed
+// with any value, so removing a key is backward compatible and adding a key
+// is forward compatible. Adding an optional key constrains the values it
+// previously allowed, and removing one allows values it previously constrained.
+var compatibilityByChange = map[ChangeType]struct{ backward, forward bool }{
+	ChangeAddedOptional:      {backward: false, forward: true},
+	ChangeAddedRequired:      {backward: false, forward: true},
+	ChangeRemovedOptional:    {backward: true, forward: false},
+	ChangeRemovedRequired:    {backward: true, forward: false},
+	ChangeMadeRequired:       {backward: false, forward: true},
+	ChangeMadeOptional:       {backward: true, forward: false},
+	ChangeTypeNarrowed:       {backward: false, forward: true},
+	ChangeTypeWidened:        {backward: true, forward: false},
+	ChangeTypeChanged:        {backward: false, forward: false},
+	ChangeConstraintNarrowed: {backward: false, forward: true},
+	ChangeConstraintWidened:  {backward: true, forward: false},
+	ChangeConstraintChanged:  {backward: false, forward: false},
+}
+
+// Change describes a change to an attribute between
+// two versions of a schema.
+type Change struct {
+	// Key is the attribute key.
+	Key string `json:"key"`
+	// Type is the kind of change.
+	Type ChangeType `json:"type"`
+	// Description describes the change.
+	Description string `json:"description"`
+	// Backward is true if attributes valid for the previous schema
+	// remain valid after the change.
+	Backward bool `json:"backward"`
+	// Forward is true if attributes valid for the new schema
+	// are valid for the previous schema.
+	Forward bool `json:"forward"`
+}
+
+// Compatible returns whether the change is allowed
+// under the compatibility mode.
+func (c Change) Compatible(mode Compatibility) bool {
+	switch mode {
+	case CompatibilityBackward:
+		return c.Backward
+	case CompatibilityForward:
+		return c.Forward
+	case CompatibilityFull:
+		return c.Backward && c.Forward
+	default:
+		return true
+	}
+}
+
+// Breaking returns the changes that are not allowed
+// under the compatibility mode.
+func Breaking(changes []Change, mode Compatibility) []Change {
+	var breaking []Change
+	for _, c := range changes {
+		if !c.Compatible(mode) {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
+// Diff compares the top-level attributes of two JSON Schema documents and
+// returns the changes from the previous to the new schema. Type, enum, minimum,
+// maximum, pattern, and format constraints are compared.
+func Diff(previous, next Loader) ([]Change, error) {
+	prevDoc, err := parseDocument(previous.Export())
+	if err != nil {
+		return nil, fmt.Errorf("previous schema: %w", err)
+	}
+	nextDoc, err := parseDocument(next.Export())
+	if err != nil {
+		return nil, fmt.Errorf("new schema: %w", err)
+	}
+
+	var changes []Change
+	add := func(key string, changeType ChangeType, description string) {
+		compat := compatibilityByChange[changeType]
+		changes = append(changes, Change{
+			Key:         key,
+			Type:        changeType,
+			Description: description,
+			Backward:    compat.backward,
+			Forward:     compat.forward,
+		})
+	}
+
+	for key, prevProp := range prevDoc.Properties {
+		nextProp, found := nextDoc.Properties[key]
+		switch {
+		case !found && prevDoc.required[key]:
+			add(key, ChangeRemovedRequired, "required attribute removed")
+		case !found:
+			add(key, ChangeRemovedOptional, "optional attribute removed")
+		default:
+			if prevDoc.required[key] != nextDoc.required[key] {
+				if nextDoc.required[key] {
+					add(key, ChangeMadeRequired, "attribute made required")
+				} else {
+					add(key, ChangeMadeOptional, "attribute made optional")
+				}
+			}
+			for _, c := range compareProperty(prevProp, nextProp) {
+				add(key, c.changeType, c.description)
+			}
+		}
+	}
+
+	for key, nextProp := range nextDoc.Properties {
+		if _, found := prevDoc.Properties[key]; found {
+			continue
+		}
+		if nextDoc.required[key] {
+			add(key, ChangeAddedRequired, fmt.Sprintf("required attribute added with type %s", nextProp.typeString()))
+		} else {
+			add(key, ChangeAddedOptional, fmt.Sprintf("optional attribute added with type %s", nextProp.typeString()))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Key != changes[j].Key {
+			return changes[i].Key < changes[j].Key
+		}
+		return changes[i].Type < changes[j].Type
+	})
+	return changes, nil
+}
+
+// document is the subset of a JSON Schema document
+// used for comparison.
+type document struct {
+	Properties map[string]propertyDocument `json:"properties"`
+	Required   []string                    `json:"required"`
+	required   map[string]bool
+}
+
+// propertyDocument is the subset of a JSON Schema property
+// used for comparison.
+type propertyDocument struct {
+	Type    json.RawMessage `json:"type"`
+	Enum    []interface{}   `json:"enum"`
+	Minimum *float64        `json:"minimum"`
+	Maximum *float64        `json:"maximum"`
+	Pattern string          `json:"pattern"`
+	Format  string          `json:"format"`
+}
+
+func parseDocument(raw []byte) (document, error) {
+	var doc document
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return doc, err
+	}
+	if doc.Properties == nil {
+		doc.Properties = map[string]propertyDocument{}
+	}
+	doc.required = map[string]bool{}
+	for _, key := range doc.Required {
+		doc.required[key] = true
+		// Keys that are required without a property definition
+		// can be of any type.
+		if _, found := doc.Properties[key]; !found {
+			doc.Properties[key] = propertyDocument{}
+		}
+	}
+	return doc, nil
+}
+
+// types returns the sorted types accepted by the property.
+// A nil slice means any type is accepted.
+func (p propertyDocument) types() []string {
+	if len(p.Type) == 0 {
+		return nil
+	}
+	var single string
+	if err := json.Unmarshal(p.Type, &single); err == nil {
+		return []string{single}
+	}
+	var multi []string
+	if err := json.Unmarshal(p.Type, &multi); err != nil {
+		return nil
+	}
+	sort.Strings(multi)
+	return multi
+}
+
+func (p propertyDocument) typeString() string {
+	types := p.types()
+	if types == nil {
+		return "any"
+	}
+	return strings.Join(types, "|")
+}
+
+// propertyChange is a change found when comparing
+// two versions of a property.
+type propertyChange struct {
+	changeType  ChangeType
+	description string
+}
+
+// compareProperty returns the type and constraint changes between
+// two versions of a property.
+func compareProperty(prev, next propertyDocument) []propertyChange {
+	var changes []propertyChange
+	narrowed := func(constraint string, prevVal, nextVal interface{}) {
+		changes = append(changes, propertyChange{ChangeConstraintNarrowed, describe(constraint, prevVal, nextVal)})
+	}
+	widened := func(constraint string, prevVal, nextVal interface{}) {
+		changes = append(changes, propertyChange{ChangeConstraintWidened, describe(constraint, prevVal, nextVal)})
+	}
+	changed := func(constraint string, prevVal, nextVal interface{}) {
+		changes = append(changes, propertyChange{ChangeConstraintChanged, describe(constraint, prevVal, nextVal)})
+	}
+
+	prevTypes, nextTypes := prev.types(), next.types()
+	if prev.typeString() != next.typeString() {
+		description := describe("type", prev.typeString(), next.typeString())
+		switch {
+		case acceptsAll(prevTypes, nextTypes):
+			changes = append(changes, propertyChange{ChangeTypeNarrowed, description})
+		case acceptsAll(nextTypes, prevTypes):
+			changes = append(changes, propertyChange{ChangeTypeWidened, description})
+		default:
+			changes = append(changes, propertyChange{ChangeTypeChanged, description})
+		}
+	}
+
+	prevEnum, nextEnum := enumSet(prev.Enum), enumSet(next.Enum)
+	switch {
+	case equalSets(prevEnum, nextEnum):
+	case prevEnum == nil, nextEnum != nil && subset(nextEnum, prevEnum):
+		narrowed("enum", enumString(prev.Enum), enumString(next.Enum))
+	case nextEnum == nil, subset(prevEnum, nextEnum):
+		widened("enum", enumString(prev.Enum), enumString(next.Enum))
+	default:
+		changed("enum", enumString(prev.Enum), enumString(next.Enum))
+	}
+
+	switch {
+	case equalBounds(prev.Minimum, next.Minimum):
+	case prev.Minimum == nil, next.Minimum != nil && *next.Minimum > *prev.Minimum:
+		narrowed("minimum", boundString(prev.Minimum), boundString(next.Minimum))
+	default:
+		widened("minimum", boundString(prev.Minimum), boundString(next.Minimum))
+	}
+
+	switch {
+	case equalBounds(prev.Maximum, next.Maximum):
+	case prev.Maximum == nil, next.Maximum != nil && *next.Maximum < *prev.Maximum:
+		narrowed("maximum", boundString(prev.Maximum), boundString(next.Maximum))
+	default:
+		widened("maximum", boundString(prev.Maximum), boundString(next.Maximum))
+	}
+
+	for _, constraint := range []struct{ name, prev, next string }{
+		{"pattern", prev.Pattern, next.Pattern},
+		{"format", prev.Format, next.Format},
+	} {
+		switch {
+		case constraint.prev == constraint.next:
+		case constraint.prev == "":
+			narrowed(constraint.name, "none", constraint.next)
+		case constraint.next == "":
+			widened(constraint.name, constraint.prev, "none")
+		default:
+			changed(constraint.name, constraint.prev, constraint.next)
+		}
+	}
+
+	return changes
+}
+
+func describe(constraint string, prevVal, nextVal interface{}) string {
+	return fmt.Sprintf("%s changed from %v to %v", constraint, prevVal, nextVal)
+}
+
+// acceptsAll returns whether all types are accepted by the
+// accepted types. A nil slice accepts any type.
+func acceptsAll(accepted, types []string) bool {
+	if accepted == nil {
+		return true
+	}
+	if types == nil {
+		return false
+	}
+	for _, t := range types {
+		var found bool
+		for _, a := range accepted {
+			// Integers are also numbers
+			if a == t || (a == "number" && t == "integer") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// enumSet returns the set of JSON encoded enum values. A nil
+// map means any value is accepted.
+func enumSet(values []interface{}) map[string]struct{} {
+	if len(values) == 0 {
+		return nil
+	}
+	set := map[string]struct{}{}
+	for _, v := range values {
+		b, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		set[string(b)] = struct{}{}
+	}
+	return set
+}
+
+func equalSets(a, b map[string]struct{}) bool {
+	if (a == nil) != (b == nil) || len(a) != len(b) {
+		return false
+	}
+	return subset(a, b)
+}
+
+// subset returns whether all values of a are in b.
+func subset(a, b map[string]struct{}) bool {
+	for v := range a {
+		if _, found := b[v]; !found {
+			return false
+		}
+	}
+	return true
+}
+
+func enumString(values []interface{}) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Sprint(values)
+	}
+	return string(b)
+}
+
+func equalBounds(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func boundString(bound *float64) string {
+	if bound == nil {
+		return "none"
+	}
+	return fmt.Sprint(*bound)
+}
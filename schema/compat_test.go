@@ -0,0 +1,218 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/emporous/emporous-go/attributes"
+	"github.com/emporous/emporous-go/model"
+)
+
+func TestDiff(t *testing.T) {
+	type spec struct {
+		name       string
+		previous   string
+		next       string
+		expChanges []Change
+		expError   string
+	}
+
+	base := `{"type":"object","properties":{"name":{"type":"string"},"size":{"type":"number"}},"required":["name"]}`
+
+	cases := []spec{
+		{
+			name:     "Success/NoChanges",
+			previous: base,
+			next:     base,
+		},
+		{
+			name:     "Success/AddedKeys",
+			previous: base,
+			next: `{"type":"object","properties":{"name":{"type":"string"},"size":{"type":"number"},` +
+				`"color":{"type":"string"},"owner":{"type":"string"}},"required":["name","owner"]}`,
+			expChanges: []Change{
+				{Key: "color", Type: ChangeAddedOptional, Description: "optional attribute added with type string", Backward: false, Forward: true},
+				{Key: "owner", Type: ChangeAddedRequired, Description: "required attribute added with type string", Backward: false, Forward: true},
+			},
+		},
+		{
+			name:     "Success/RemovedKeys",
+			previous: base,
+			next:     `{"type":"object","properties":{}}`,
+			expChanges: []Change{
+				{Key: "name", Type: ChangeRemovedRequired, Description: "required attribute removed", Backward: true, Forward: false},
+				{Key: "size", Type: ChangeRemovedOptional, Description: "optional attribute removed", Backward: true, Forward: false},
+			},
+		},
+		{
+			name:     "Success/RequiredChanges",
+			previous: base,
+			next:     `{"type":"object","properties":{"name":{"type":"string"},"size":{"type":"number"}},"required":["size"]}`,
+			expChanges: []Change{
+				{Key: "name", Type: ChangeMadeOptional, Description: "attribute made optional", Backward: true, Forward: false},
+				{Key: "size", Type: ChangeMadeRequired, Description: "attribute made required", Backward: false, Forward: true},
+			},
+		},
+		{
+			name:     "Success/TypeChanges",
+			previous: base,
+			next:     `{"type":"object","properties":{"name":{"type":"boolean"},"size":{"type":"integer"}},"required":["name"]}`,
+			expChanges: []Change{
+				{Key: "name", Type: ChangeTypeChanged, Description: "type changed from string to boolean", Backward: false, Forward: false},
+				{Key: "size", Type: ChangeTypeNarrowed, Description: "type changed from number to integer", Backward: false, Forward: true},
+			},
+		},
+		{
+			name:     "Success/TypeWidened",
+			previous: `{"type":"object","properties":{"size":{"type":"integer"}}}`,
+			next:     `{"type":"object","properties":{"size":{"type":["integer","string"]}}}`,
+			expChanges: []Change{
+				{Key: "size", Type: ChangeTypeWidened, Description: "type changed from integer to integer|string", Backward: true, Forward: false},
+			},
+		},
+		{
+			name: "Success/ConstraintChanges",
+			previous: `{"type":"object","properties":{"stage":{"type":"string","enum":["dev","prod"]},` +
+				`"size":{"type":"integer","minimum":1,"maximum":10},"version":{"type":"string","format":"semver"}}}`,
+			next: `{"type":"object","properties":{"stage":{"type":"string","enum":["dev"]},` +
+				`"size":{"type":"integer","minimum":0,"maximum":5},"version":{"type":"string","pattern":"^1\\."}}}`,
+			expChanges: []Change{
+				{Key: "size", Type: ChangeConstraintNarrowed, Description: "maximum changed from 10 to 5", Backward: false, Forward: true},
+				{Key: "size", Type: ChangeConstraintWidened, Description: "minimum changed from 1 to 0", Backward: true, Forward: false},
+				{Key: "stage", Type: ChangeConstraintNarrowed, Description: `enum changed from ["dev","prod"] to ["dev"]`, Backward: false, Forward: true},
+				{Key: "version", Type: ChangeConstraintNarrowed, Description: "pattern changed from none to ^1\\.", Backward: false, Forward: true},
+				{Key: "version", Type: ChangeConstraintWidened, Description: "format changed from semver to none", Backward: true, Forward: false},
+			},
+		},
+		{
+			name:     "Success/RequiredWithoutProperty",
+			previous: `{"type":"object","properties":{}}`,
+			next:     `{"type":"object","required":["name"]}`,
+			expChanges: []Change{
+				{Key: "name", Type: ChangeAddedRequired, Description: "required attribute added with type any", Backward: false, Forward: true},
+			},
+		},
+		{
+			name:     "Failure/InvalidDocument",
+			previous: base,
+			next:     `[]`,
+			expError: "new schema: json: cannot unmarshal array into Go value of type schema.document",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			previous, err := FromBytes([]byte(c.previous))
+			require.NoError(t, err)
+			next, err := FromBytes([]byte(c.next))
+			require.NoError(t, err)
+			changes, err := Diff(previous, next)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.expChanges, changes)
+			}
+		})
+	}
+}
+
+func TestBreaking(t *testing.T) {
+	changes := []Change{
+		{Key: "owner", Type: ChangeAddedRequired, Backward: false, Forward: true},
+		{Key: "name", Type: ChangeRemovedRequired, Backward: true, Forward: false},
+		{Key: "color", Type: ChangeAddedOptional, Backward: false, Forward: true},
+		{Key: "size", Type: ChangeRemovedOptional, Backward: true, Forward: false},
+	}
+
+	type spec struct {
+		name    string
+		mode    Compatibility
+		expKeys []string
+	}
+
+	cases := []spec{
+		{name: "Success/Backward", mode: CompatibilityBackward, expKeys: []string{"owner", "color"}},
+		{name: "Success/Forward", mode: CompatibilityForward, expKeys: []string{"name", "size"}},
+		{name: "Success/Full", mode: CompatibilityFull, expKeys: []string{"owner", "name", "color", "size"}},
+		{name: "Success/None", mode: CompatibilityNone},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var keys []string
+			for _, change := range Breaking(changes, c.mode) {
+				keys = append(keys, change.Key)
+			}
+			require.Equal(t, c.expKeys, keys)
+		})
+	}
+}
+
+func TestDiffOpenContent(t *testing.T) {
+	type spec struct {
+		name     string
+		previous string
+		next     string
+		// doc is valid for one schema and, as the
+		// change is breaking, not valid for the other.
+		doc        model.AttributeSet
+		expChanges []Change
+	}
+
+	cases := []spec{
+		{
+			name:     "Success/AddedOptionalNotBackward",
+			previous: `{"type":"object","properties":{}}`,
+			next:     `{"type":"object","properties":{"color":{"type":"string"}}}`,
+			doc:      attributes.Attributes{"color": attributes.NewFloat("color", 1)},
+			expChanges: []Change{
+				{Key: "color", Type: ChangeAddedOptional, Description: "optional attribute added with type string", Backward: false, Forward: true},
+			},
+		},
+		{
+			name:     "Success/RemovedOptionalNotForward",
+			previous: `{"type":"object","properties":{"color":{"type":"string"}}}`,
+			next:     `{"type":"object","properties":{}}`,
+			doc:      attributes.Attributes{"color": attributes.NewFloat("color", 1)},
+			expChanges: []Change{
+				{Key: "color", Type: ChangeRemovedOptional, Description: "optional attribute removed", Backward: true, Forward: false},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			previous, err := FromBytes([]byte(c.previous))
+			require.NoError(t, err)
+			next, err := FromBytes([]byte(c.next))
+			require.NoError(t, err)
+			changes, err := Diff(previous, next)
+			require.NoError(t, err)
+			require.Equal(t, c.expChanges, changes)
+
+			validate := func(loader Loader) bool {
+				sc, err := New(loader)
+				require.NoError(t, err)
+				valid, _ := sc.Validate(c.doc)
+				return valid
+			}
+			validPrevious, validNext := validate(previous), validate(next)
+			require.NotEqual(t, validPrevious, validNext)
+			// A change is backward compatible if the attributes valid for the
+			// previous schema are valid for the new schema and forward compatible
+			// if the attributes valid for the new schema are valid for the previous one.
+			require.Equal(t, !validPrevious || validNext, changes[0].Backward)
+			require.Equal(t, !validNext || validPrevious, changes[0].Forward)
+		})
+	}
+}
+
+func TestParseCompatibility(t *testing.T) {
+	mode, err := ParseCompatibility("full")
+	require.NoError(t, err)
+	require.Equal(t, CompatibilityFull, mode)
+	_, err = ParseCompatibility("transitive")
+	require.EqualError(t, err, "unsupported compatibility mode \"transitive\", must be one of [backward forward full none]")
+}
@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Violation describes an attribute that does not satisfy
+// a schema rule.
+type Violation struct {
+	// Location is the location of the file the attributes
+	// are associated with, if known.
+	Location string `json:"location,omitempty"`
+	// Key is the attribute key. Key is empty when the
+	// violation applies to the whole attribute set.
+	Key string `json:"key,omitempty"`
+	// Pointer is the JSON pointer to the value in the attribute set.
+	Pointer string `json:"pointer"`
+	// Rule is the schema rule that was violated (e.g. required, enum).
+	Rule string `json:"rule"`
+	// Message describes the violation.
+	Message string `json:"message"`
+}
+
+// String returns a string representation of the violation.
+func (v Violation) String() string {
+	pointer := v.Pointer
+	if pointer == "" {
+		pointer = "(root)"
+	}
+	if v.Location != "" {
+		return fmt.Sprintf("file %s: %s: %s", v.Location, pointer, v.Message)
+	}
+	return fmt.Sprintf("%s: %s", pointer, v.Message)
+}
+
+// ValidationError lists the violations found when
+// validating attributes against a schema.
+type ValidationError struct {
+	Violations []Violation `json:"violations"`
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.String())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Sort orders the violations by location, pointer, and rule.
+func (e *ValidationError) Sort() {
+	sort.SliceStable(e.Violations, func(i, j int) bool {
+		a, b := e.Violations[i], e.Violations[j]
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		if a.Pointer != b.Pointer {
+			return a.Pointer < b.Pointer
+		}
+		return a.Rule < b.Rule
+	})
+}
+
+// newValidationError converts JSON Schema result errors to a ValidationError.
+func newValidationError(errs []gojsonschema.ResultError) *ValidationError {
+	vErr := &ValidationError{}
+	for _, resultErr := range errs {
+		// Context segments are separated by a delimiter that is
+		// not valid in attribute keys to avoid splitting keys with dots.
+		segments := strings.Split(resultErr.Context().String("\x00"), "\x00")
+		// The first segment is always the root.
+		segments = segments[1:]
+		// The missing property is not part of the context for required
+		// properties.
+		if resultErr.Type() == "required" {
+			if property, ok := resultErr.Details()["property"].(string); ok {
+				segments = append(segments, property)
+			}
+		}
+
+		v := Violation{
+			Pointer: jsonPointer(segments),
+			Rule:    resultErr.Type(),
+			Message: strings.ToLower(resultErr.Description()),
+		}
+		if len(segments) != 0 {
+			v.Key = segments[0]
+		}
+		vErr.Violations = append(vErr.Violations, v)
+	}
+	vErr.Sort()
+	return vErr
+}
+
+// jsonPointer returns a RFC 6901 JSON pointer from
+// reference segments.
+func jsonPointer(segments []string) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		segment = strings.ReplaceAll(segment, "~", "~0")
+		segment = strings.ReplaceAll(segment, "/", "~1")
+		b.WriteString("/")
+		b.WriteString(segment)
+	}
+	return b.String()
+}
@@ -24,31 +24,44 @@ func FromTypes(types Types) (Loader, error) {
 	if err := types.Validate(); err != nil {
 		return Loader{}, err
 	}
+	properties := make(Properties, len(types))
+	for key, value := range types {
+		properties[key] = Property{Type: value}
+	}
+	return FromProperties(properties)
+}
 
-	// Build an object in json from the provided types
+// FromProperties builds a JSON Schema from a key with an associated property.
+// Keys will be considered required in the schema when comparing sets of attributes
+// unless the property is optional.
+func FromProperties(properties Properties) (Loader, error) {
+	if err := properties.Validate(); err != nil {
+		return Loader{}, err
+	}
+
+	// Build an object in json from the provided properties
 	type jsonSchema struct {
-		Type       string                       `json:"type"`
-		Properties map[string]map[string]string `json:"properties"`
-		Required   []string                     `json:"required"`
+		Type       string                            `json:"type"`
+		Properties map[string]map[string]interface{} `json:"properties"`
+		Required   []string                          `json:"required,omitempty"`
 	}
 
-	// Fill in properties and required keys. At this point
-	// we consider all keys as required.
-	properties := map[string]map[string]string{}
+	// Fill in properties and required keys.
+	jsonProperties := map[string]map[string]interface{}{}
 	var required []string
-	for key, value := range types {
-		properties[key] = map[string]string{"type": value.String()}
-		required = append(required, key)
+	for key, value := range properties {
+		jsonProperties[key] = value.jsonSchema()
+		if !value.Optional {
+			required = append(required, key)
+		}
 	}
 
 	// Make the required slice order deterministic
-	sort.Slice(required, func(i, j int) bool {
-		return required[i] < required[j]
-	})
+	sort.Strings(required)
 
 	tmp := jsonSchema{
 		Type:       "object",
-		Properties: properties,
+		Properties: jsonProperties,
 		Required:   required,
 	}
 	b, err := json.Marshal(tmp)
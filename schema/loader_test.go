@@ -94,3 +94,58 @@ func TestExport(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, exp, string(s.Export()))
 }
+
+func TestFromProperties(t *testing.T) {
+	type spec struct {
+		name       string
+		properties Properties
+		expSchema  string
+		expError   string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/Constraints",
+			properties: Properties{
+				"size": {Type: TypeInteger, Minimum: float64Ptr(0), Optional: true},
+				"version": {
+					Type:        TypeString,
+					Format:      FormatSemver,
+					Description: "The model version",
+				},
+				"stage": {Type: TypeString, Enum: []interface{}{"dev", "prod"}, Default: "dev"},
+			},
+			expSchema: `{"type":"object","properties":{` +
+				`"size":{"minimum":0,"type":"integer"},` +
+				`"stage":{"default":"dev","enum":["dev","prod"],"type":"string"},` +
+				`"version":{"description":"The model version","format":"semver","type":"string"}},` +
+				`"required":["stage","version"]}`,
+		},
+		{
+			name: "Success/AllOptional",
+			properties: Properties{
+				"size": {Type: TypeNumber, Optional: true},
+			},
+			expSchema: `{"type":"object","properties":{"size":{"type":"number"}}}`,
+		},
+		{
+			name: "Failure/InvalidConstraint",
+			properties: Properties{
+				"size": {Type: TypeNumber, Format: FormatURI},
+			},
+			expError: "attribute size: pattern and format require type string, got number",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			schema, err := FromProperties(c.properties)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.expSchema, string(schema.Export()))
+			}
+		})
+	}
+}
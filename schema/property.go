@@ -0,0 +1,199 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Format represents a string format that can be
+// enforced on attributes of the string type.
+type Format string
+
+const (
+	FormatDateTime Format = "date-time"
+	FormatURI      Format = "uri"
+	FormatSemver   Format = "semver"
+)
+
+// formats lists the supported string formats.
+var formats = map[Format]struct{}{
+	FormatDateTime: {},
+	FormatURI:      {},
+	FormatSemver:   {},
+}
+
+// semverRegexp is the regular expression suggested by the Semantic Versioning 2.0.0
+// specification.
+var semverRegexp = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// semverChecker validates the semver string format.
+type semverChecker struct{}
+
+// IsFormat returns whether the input is a semantic version. Input
+// that is not a string is ignored.
+func (semverChecker) IsFormat(input interface{}) bool {
+	s, ok := input.(string)
+	if !ok {
+		return true
+	}
+	return semverRegexp.MatchString(s)
+}
+
+func init() {
+	gojsonschema.FormatCheckers.Add(string(FormatSemver), semverChecker{})
+}
+
+// Property defines the type and constraints of an attribute in a schema.
+// Properties are required unless set as optional.
+type Property struct {
+	// Type is the schema type of the attribute.
+	Type Type `json:"type"`
+	// Optional allows the attribute to be omitted.
+	Optional bool `json:"optional,omitempty"`
+	// Description describes the attribute.
+	Description string `json:"description,omitempty"`
+	// Enum lists the allowed values for the attribute.
+	Enum []interface{} `json:"enum,omitempty"`
+	// Minimum is the inclusive lower bound of a number or integer attribute.
+	Minimum *float64 `json:"minimum,omitempty"`
+	// Maximum is the inclusive upper bound of a number or integer attribute.
+	Maximum *float64 `json:"maximum,omitempty"`
+	// Pattern is a regular expression a string attribute must match.
+	Pattern string `json:"pattern,omitempty"`
+	// Format is the format a string attribute must have.
+	Format Format `json:"format,omitempty"`
+	// Default is the value used for the attribute when not set.
+	Default interface{} `json:"default,omitempty"`
+}
+
+// UnmarshalJSON unmarshal a JSON serialized property to the Property. A string
+// is accepted as a shorthand for a property with only the type set.
+func (p *Property) UnmarshalJSON(b []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte(`"`)) {
+		var t Type
+		if err := json.Unmarshal(b, &t); err != nil {
+			return err
+		}
+		*p = Property{Type: t}
+		return nil
+	}
+
+	// Use an alias to avoid recursing into this method
+	type property Property
+	var prop property
+	if err := json.Unmarshal(b, &prop); err != nil {
+		return err
+	}
+	*p = Property(prop)
+	return nil
+}
+
+// jsonSchema returns the JSON Schema representation of the property.
+func (p Property) jsonSchema() map[string]interface{} {
+	s := map[string]interface{}{"type": p.Type.String()}
+	if p.Description != "" {
+		s["description"] = p.Description
+	}
+	if len(p.Enum) != 0 {
+		s["enum"] = p.Enum
+	}
+	if p.Minimum != nil {
+		s["minimum"] = *p.Minimum
+	}
+	if p.Maximum != nil {
+		s["maximum"] = *p.Maximum
+	}
+	if p.Pattern != "" {
+		s["pattern"] = p.Pattern
+	}
+	if p.Format != "" {
+		s["format"] = string(p.Format)
+	}
+	if p.Default != nil {
+		s["default"] = p.Default
+	}
+	return s
+}
+
+// validate performs basic validation
+// on a Property.
+func (p Property) validate() error {
+	if err := p.Type.validate(); err != nil {
+		return err
+	}
+
+	if p.Minimum != nil || p.Maximum != nil {
+		if p.Type != TypeNumber && p.Type != TypeInteger {
+			return fmt.Errorf("minimum and maximum require type number or integer, got %s", p.Type)
+		}
+		if p.Minimum != nil && p.Maximum != nil && *p.Minimum > *p.Maximum {
+			return errors.New("minimum is greater than maximum")
+		}
+	}
+
+	if p.Pattern != "" || p.Format != "" {
+		if p.Type != TypeString {
+			return fmt.Errorf("pattern and format require type string, got %s", p.Type)
+		}
+		if _, err := regexp.Compile(p.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		if _, found := formats[p.Format]; p.Format != "" && !found {
+			return fmt.Errorf("unknown format %q", p.Format)
+		}
+	}
+
+	// The enum values must satisfy the other constraints and
+	// the default value must also be one of the enum values.
+	constraints := p.jsonSchema()
+	delete(constraints, "enum")
+	for _, value := range p.Enum {
+		if err := validateValue(constraints, value); err != nil {
+			return fmt.Errorf("enum value %v: %w", value, err)
+		}
+	}
+	if p.Default != nil {
+		if err := validateValue(p.jsonSchema(), p.Default); err != nil {
+			return fmt.Errorf("default value %v: %w", p.Default, err)
+		}
+	}
+	return nil
+}
+
+// validateValue validates a single value against a JSON Schema.
+func validateValue(jsonSchema map[string]interface{}, value interface{}) error {
+	result, err := gojsonschema.Validate(gojsonschema.NewGoLoader(jsonSchema), gojsonschema.NewGoLoader(value))
+	if err != nil {
+		return err
+	}
+	if result.Valid() {
+		return nil
+	}
+	var descriptions []string
+	for _, resultErr := range result.Errors() {
+		descriptions = append(descriptions, resultErr.Description())
+	}
+	return errors.New(strings.ToLower(strings.Join(descriptions, ", ")))
+}
+
+// Properties represent a schema Property mapped to a key of string type.
+type Properties map[string]Property
+
+// Validate performs basic validation
+// on a set of schema properties.
+func (p Properties) Validate() error {
+	for key, value := range p {
+		if err := value.validate(); err != nil {
+			return fmt.Errorf("attribute %s: %w", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProperty_UnmarshalJSON(t *testing.T) {
+	type spec struct {
+		name     string
+		input    string
+		exp      Property
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name:  "Success/Shorthand",
+			input: `"string"`,
+			exp:   Property{Type: TypeString},
+		},
+		{
+			name:  "Success/Constraints",
+			input: `{"type":"integer","optional":true,"minimum":1,"description":"size"}`,
+			exp: Property{
+				Type:        TypeInteger,
+				Optional:    true,
+				Minimum:     float64Ptr(1),
+				Description: "size",
+			},
+		},
+		{
+			name:     "Failure/UnknownShorthandType",
+			input:    `"text"`,
+			expError: "must set schema type",
+		},
+		{
+			name:     "Failure/UnknownType",
+			input:    `{"type":"text"}`,
+			expError: "must set schema type",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var p Property
+			err := json.Unmarshal([]byte(c.input), &p)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, c.exp, p)
+			}
+		})
+	}
+}
+
+func TestProperties_Validate(t *testing.T) {
+	type spec struct {
+		name       string
+		properties Properties
+		expError   string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/ValidConstraints",
+			properties: Properties{
+				"size":    {Type: TypeNumber, Minimum: float64Ptr(0), Maximum: float64Ptr(10), Default: 5.0},
+				"version": {Type: TypeString, Format: FormatSemver, Pattern: "^1\\."},
+				"stage":   {Type: TypeString, Enum: []interface{}{"dev", "prod"}, Default: "dev"},
+			},
+		},
+		{
+			name:       "Failure/MissingType",
+			properties: Properties{"size": {Optional: true}},
+			expError:   "attribute size: must set schema type",
+		},
+		{
+			name:       "Failure/MinimumOnString",
+			properties: Properties{"name": {Type: TypeString, Minimum: float64Ptr(0)}},
+			expError:   "attribute name: minimum and maximum require type number or integer, got string",
+		},
+		{
+			name:       "Failure/MinimumGreaterThanMaximum",
+			properties: Properties{"size": {Type: TypeInteger, Minimum: float64Ptr(2), Maximum: float64Ptr(1)}},
+			expError:   "attribute size: minimum is greater than maximum",
+		},
+		{
+			name:       "Failure/PatternOnNumber",
+			properties: Properties{"size": {Type: TypeNumber, Pattern: "^1"}},
+			expError:   "attribute size: pattern and format require type string, got number",
+		},
+		{
+			name:       "Failure/InvalidPattern",
+			properties: Properties{"name": {Type: TypeString, Pattern: "("}},
+			expError:   "attribute name: invalid pattern: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name:       "Failure/UnknownFormat",
+			properties: Properties{"name": {Type: TypeString, Format: "color"}},
+			expError:   "attribute name: unknown format \"color\"",
+		},
+		{
+			name:       "Failure/EnumTypeMismatch",
+			properties: Properties{"stage": {Type: TypeString, Enum: []interface{}{"dev", 1}}},
+			expError:   "attribute stage: enum value 1: invalid type. expected: string, given: integer",
+		},
+		{
+			name:       "Failure/DefaultNotInEnum",
+			properties: Properties{"stage": {Type: TypeString, Enum: []interface{}{"dev"}, Default: "prod"}},
+			expError:   "attribute stage: default value prod: (root) must be one of the following: \"dev\"",
+		},
+		{
+			name:       "Failure/DefaultInvalidFormat",
+			properties: Properties{"version": {Type: TypeString, Format: FormatSemver, Default: "v1"}},
+			expError:   "attribute version: default value v1: does not match format 'semver'",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.properties.Validate()
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
@@ -1,10 +1,6 @@
 package schema
 
 import (
-	"errors"
-	"fmt"
-	"strings"
-
 	"github.com/xeipuuv/gojsonschema"
 
 	"github.com/emporous/emporous-go/model"
@@ -21,7 +17,8 @@ type Schema struct {
 }
 
 // Validate performs schema validation against the
-// input attribute set.
+// input attribute set. If the attribute set is not valid,
+// a ValidationError is returned.
 func (s *Schema) Validate(set model.AttributeSet) (bool, error) {
 	attrDoc, err := set.MarshalJSON()
 	if err != nil {
@@ -32,18 +29,10 @@ func (s *Schema) Validate(set model.AttributeSet) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return result.Valid(), aggregateErrors(result.Errors())
-}
-
-func aggregateErrors(errs []gojsonschema.ResultError) error {
-	if len(errs) == 0 {
-		return nil
-	}
-	finalErr := errors.New(strings.ToLower(errs[0].String()))
-	for i := 1; i < len(errs); i++ {
-		finalErr = fmt.Errorf("%v:%v", finalErr, strings.ToLower(errs[i].String()))
+	if !result.Valid() {
+		return false, newValidationError(result.Errors())
 	}
-	return finalErr
+	return true, nil
 }
 
 // New create a schema from a Loader
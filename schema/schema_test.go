@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -29,6 +30,33 @@ func TestSchema_Validate(t *testing.T) {
 			},
 			expRes: true,
 		},
+		{
+			name: "Success/ListAndMapAttributes",
+			schemaTypes: map[string]Type{
+				"tags":  TypeArray,
+				"owner": TypeObject,
+			},
+			doc: attributes.Attributes{
+				"tags": attributes.NewList("tags", []model.Attribute{
+					attributes.NewString("tags", "gpu"),
+				}),
+				"owner": attributes.NewMap("owner", map[string]model.Attribute{
+					"team": attributes.NewString("team", "x"),
+				}),
+			},
+			expRes: true,
+		},
+		{
+			name: "Failure/ListIncompatibleType",
+			schemaTypes: map[string]Type{
+				"tags": TypeArray,
+			},
+			doc: attributes.Attributes{
+				"tags": attributes.NewString("tags", "gpu"),
+			},
+			expRes:   false,
+			expError: "/tags: invalid type. expected: array, given: string",
+		},
 		{
 			name: "Failure/IncompatibleType",
 			schemaTypes: map[string]Type{
@@ -38,7 +66,7 @@ func TestSchema_Validate(t *testing.T) {
 				"size": attributes.NewFloat("size", 1.0),
 			},
 			expRes:   false,
-			expError: "size: invalid type. expected: boolean, given: integer",
+			expError: "/size: invalid type. expected: boolean, given: integer",
 		},
 		{
 			name: "Failure/MissingKey",
@@ -48,7 +76,7 @@ func TestSchema_Validate(t *testing.T) {
 			doc: attributes.Attributes{
 				"name": attributes.NewString("name", "test"),
 			},
-			expError: "(root): size is required",
+			expError: "/size: size is required",
 			expRes:   false,
 		},
 	}
@@ -71,3 +99,143 @@ func TestSchema_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestSchema_ValidateWithProperties(t *testing.T) {
+	properties := Properties{
+		"version": {Type: TypeString, Format: FormatSemver},
+		"created": {Type: TypeString, Format: FormatDateTime, Optional: true},
+		"size":    {Type: TypeInteger, Minimum: float64Ptr(1), Maximum: float64Ptr(10), Optional: true},
+		"stage":   {Type: TypeString, Enum: []interface{}{"dev", "prod"}, Optional: true},
+		"name":    {Type: TypeString, Pattern: "^[a-z]+$", Optional: true},
+	}
+
+	type spec struct {
+		name     string
+		doc      model.AttributeSet
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/RequiredOnly",
+			doc: attributes.Attributes{
+				"version": attributes.NewString("version", "1.2.3-rc.1"),
+			},
+		},
+		{
+			name: "Success/AllConstraints",
+			doc: attributes.Attributes{
+				"version": attributes.NewString("version", "1.2.3"),
+				"created": attributes.NewString("created", "2022-10-18T10:00:00Z"),
+				"size":    attributes.NewInt("size", 5),
+				"stage":   attributes.NewString("stage", "prod"),
+				"name":    attributes.NewString("name", "resnet"),
+			},
+		},
+		{
+			name: "Failure/InvalidSemver",
+			doc: attributes.Attributes{
+				"version": attributes.NewString("version", "v1"),
+			},
+			expError: "/version: does not match format 'semver'",
+		},
+		{
+			name: "Failure/OutOfRange",
+			doc: attributes.Attributes{
+				"version": attributes.NewString("version", "1.0.0"),
+				"size":    attributes.NewInt("size", 11),
+			},
+			expError: "/size: must be less than or equal to 10",
+		},
+		{
+			name: "Failure/NotInEnum",
+			doc: attributes.Attributes{
+				"version": attributes.NewString("version", "1.0.0"),
+				"stage":   attributes.NewString("stage", "test"),
+			},
+			expError: "/stage: stage must be one of the following: \"dev\", \"prod\"",
+		},
+		{
+			name: "Failure/PatternMismatch",
+			doc: attributes.Attributes{
+				"version": attributes.NewString("version", "1.0.0"),
+				"name":    attributes.NewString("name", "ResNet"),
+			},
+			expError: "/name: does not match pattern '^[a-z]+$'",
+		},
+	}
+
+	loader, err := FromProperties(properties)
+	require.NoError(t, err)
+	schema, err := New(loader)
+	require.NoError(t, err)
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := schema.Validate(c.doc)
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+				require.False(t, result)
+			} else {
+				require.NoError(t, err)
+				require.True(t, result)
+			}
+		})
+	}
+}
+
+func TestSchema_ValidateError(t *testing.T) {
+	loader, err := FromProperties(Properties{
+		"name":  {Type: TypeString},
+		"tags":  {Type: TypeArray},
+		"owner": {Type: TypeObject},
+	})
+	require.NoError(t, err)
+	// Add nested constraints that cannot be expressed with properties.
+	raw := strings.Replace(string(loader.Export()), `"tags":{"type":"array"}`,
+		`"tags":{"type":"array","items":{"type":"string"}}`, 1)
+	raw = strings.Replace(raw, `"owner":{"type":"object"}`,
+		`"owner":{"type":"object","properties":{"a/b":{"type":"integer"}}}`, 1)
+	loader, err = FromBytes([]byte(raw))
+	require.NoError(t, err)
+	schema, err := New(loader)
+	require.NoError(t, err)
+
+	doc := attributes.Attributes{
+		"tags": attributes.NewList("tags", []model.Attribute{
+			attributes.NewString("tags", "gpu"),
+			attributes.NewInt("tags", 1),
+		}),
+		"owner": attributes.NewMap("owner", map[string]model.Attribute{
+			"a/b": attributes.NewString("a/b", "x"),
+		}),
+	}
+	valid, err := schema.Validate(doc)
+	require.False(t, valid)
+
+	var vErr *ValidationError
+	require.ErrorAs(t, err, &vErr)
+	require.Equal(t, []Violation{
+		{
+			Key:     "name",
+			Pointer: "/name",
+			Rule:    "required",
+			Message: "name is required",
+		},
+		{
+			Key:     "owner",
+			Pointer: "/owner/a~1b",
+			Rule:    "invalid_type",
+			Message: "invalid type. expected: integer, given: string",
+		},
+		{
+			Key:     "tags",
+			Pointer: "/tags/1",
+			Rule:    "invalid_type",
+			Message: "invalid type. expected: string, given: integer",
+		},
+	}, vErr.Violations)
+	require.Equal(t, "/name: name is required; "+
+		"/owner/a~1b: invalid type. expected: integer, given: string; "+
+		"/tags/1: invalid type. expected: string, given: integer", err.Error())
+}
@@ -18,6 +18,8 @@ const (
 	TypeNumber
 	TypeInteger
 	TypeString
+	TypeArray
+	TypeObject
 )
 
 // String prints a string representation of the attribute kind.
@@ -80,6 +82,8 @@ var stringByType = map[Type]string{
 	TypeBool:    "boolean",
 	TypeString:  "string",
 	TypeNull:    "null",
+	TypeArray:   "array",
+	TypeObject:  "object",
 }
 
 // typeByString maps the string representation of the schema Type
@@ -90,6 +94,8 @@ var typeByString = map[string]Type{
 	"boolean": TypeBool,
 	"string":  TypeString,
 	"null":    TypeNull,
+	"array":   TypeArray,
+	"object":  TypeObject,
 }
 
 // modelKindByType maps each schema type to a
@@ -100,6 +106,8 @@ var modelKindByType = map[Type]model.Kind{
 	TypeBool:    model.KindBool,
 	TypeString:  model.KindString,
 	TypeNull:    model.KindNull,
+	TypeArray:   model.KindList,
+	TypeObject:  model.KindMap,
 	TypeInvalid: model.KindInvalid,
 }
 
@@ -3,6 +3,7 @@ package collectionmanager
 import (
 	"context"
 
+	"github.com/google/go-containerregistry/pkg/authn"
 	"oras.land/oras-go/v2/registry/remote/auth"
 
 	managerapi "github.com/emporous/emporous-go/api/services/collectionmanager/v1alpha1"
@@ -36,3 +37,21 @@ func (s *authConfig) Credential(_ context.Context, registry string) (auth.Creden
 
 	return auth.EmptyCredential, nil
 }
+
+// Resolve returns the authenticator for the credential specified from the AuthConfig
+// if the host matches. This allows signatures to be fetched with the same credentials.
+func (s *authConfig) Resolve(resource authn.Resource) (authn.Authenticator, error) {
+	cred, err := s.Credential(context.Background(), resource.RegistryStr())
+	if err != nil {
+		return nil, err
+	}
+	if cred == auth.EmptyCredential {
+		return authn.Anonymous, nil
+	}
+	return authn.FromConfig(authn.AuthConfig{
+		Username:      cred.Username,
+		Password:      cred.Password,
+		IdentityToken: cred.RefreshToken,
+		RegistryToken: cred.AccessToken,
+	}), nil
+}
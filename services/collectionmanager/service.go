@@ -6,15 +6,17 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"oras.land/oras-go/v2/content/file"
 
 	"github.com/emporous/emporous-go/api/client/v1alpha1"
 	managerapi "github.com/emporous/emporous-go/api/services/collectionmanager/v1alpha1"
+	"github.com/emporous/emporous-go/config"
 	"github.com/emporous/emporous-go/content"
+	"github.com/emporous/emporous-go/content/file"
+	"github.com/emporous/emporous-go/log"
 	"github.com/emporous/emporous-go/manager"
-	"github.com/emporous/emporous-go/nodes/descriptor"
 	"github.com/emporous/emporous-go/registryclient/orasclient"
 	"github.com/emporous/emporous-go/util/workspace"
+	"github.com/emporous/emporous-go/verification"
 )
 
 var _ managerapi.CollectionManagerServer = &service{}
@@ -30,7 +32,31 @@ type service struct {
 type ServiceOptions struct {
 	Insecure  bool
 	PlainHTTP bool
+	// CAFile, CertFile, and KeyFile configure
+	// TLS for registry connections.
+	CAFile    string
+	CertFile  string
+	KeyFile   string
 	PullCache content.Store
+	// Progress receives copy progress events
+	// for all requests.
+	Progress orasclient.ProgressFunc
+	// RetryPolicy configures retries of
+	// registry requests.
+	RetryPolicy orasclient.RetryPolicy
+	// RegistryConfig configures the mirrors
+	// to pull content from.
+	RegistryConfig v1alpha1.RegistryConfiguration
+	// VerificationPolicy configures the signers trusted for
+	// pulled collections. Collections are not verified if
+	// no policies are set.
+	VerificationPolicy v1alpha1.VerificationPolicy
+	// RekorURL and VerifyOffline configure the transparency
+	// log lookups of signature verification.
+	RekorURL      string
+	VerifyOffline bool
+	// Logger logs client activity.
+	Logger log.Logger
 }
 
 // FromManager returns a CollectionManager API server from a Manager type.
@@ -44,11 +70,21 @@ func FromManager(mg manager.Manager, serviceOptions ServiceOptions) managerapi.C
 // PublishContent publishes collection content to a storage provide based on client input.
 func (s *service) PublishContent(ctx context.Context, message *managerapi.Publish_Request) (*managerapi.Publish_Response, error) {
 	authConf := authConfig{message.Auth}
-	client, err := orasclient.NewClient(
+	clientOpts := []orasclient.ClientOption{
 		orasclient.WithCache(s.options.PullCache),
 		orasclient.WithPlainHTTP(s.options.PlainHTTP),
 		orasclient.WithCredentialFunc(authConf.Credential),
-		orasclient.SkipTLSVerify(s.options.Insecure))
+		orasclient.SkipTLSVerify(s.options.Insecure),
+		orasclient.WithCAFile(s.options.CAFile),
+		orasclient.WithClientCertificate(s.options.CertFile, s.options.KeyFile),
+		orasclient.WithRetryPolicy(s.options.RetryPolicy),
+		orasclient.WithLogger(s.options.Logger),
+		orasclient.WithRegistryConfig(s.options.RegistryConfig),
+	}
+	if s.options.Progress != nil {
+		clientOpts = append(clientOpts, orasclient.WithProgress(s.options.Progress))
+	}
+	client, err := orasclient.NewClient(clientOpts...)
 	if err != nil {
 		return &managerapi.Publish_Response{}, status.Error(codes.Internal, err.Error())
 	}
@@ -117,10 +153,51 @@ func (s *service) RetrieveContent(ctx context.Context, message *managerapi.Retri
 		orasclient.WithCredentialFunc(authConf.Credential),
 		orasclient.WithPlainHTTP(s.options.PlainHTTP),
 		orasclient.SkipTLSVerify(s.options.Insecure),
+		orasclient.WithCAFile(s.options.CAFile),
+		orasclient.WithClientCertificate(s.options.CertFile, s.options.KeyFile),
+		orasclient.WithRetryPolicy(s.options.RetryPolicy),
+		orasclient.WithLogger(s.options.Logger),
+		orasclient.WithRegistryConfig(s.options.RegistryConfig),
+	}
+	if s.options.Progress != nil {
+		clientOpts = append(clientOpts, orasclient.WithProgress(s.options.Progress))
 	}
 
-	var matcher descriptor.JSONSubsetMatcher = attrSet
-	if len(attrSet) != 0 {
+	if len(s.options.VerificationPolicy.Policies) != 0 {
+		// Signatures are fetched with a dedicated transport so concurrent
+		// requests do not share TLS settings.
+		transport, err := orasclient.NewTransport(
+			orasclient.SkipTLSVerify(s.options.Insecure),
+			orasclient.WithCAFile(s.options.CAFile),
+			orasclient.WithClientCertificate(s.options.CertFile, s.options.KeyFile),
+			orasclient.WithRegistryConfig(s.options.RegistryConfig),
+		)
+		if err != nil {
+			return &managerapi.Retrieve_Response{}, status.Error(codes.Internal, err.Error())
+		}
+		verifier, err := verification.NewVerifier(s.options.VerificationPolicy,
+			verification.WithKeychain(&authConf),
+			verification.WithTransport(transport),
+			verification.WithInsecure(s.options.Insecure || s.options.PlainHTTP),
+			verification.WithRekorURL(s.options.RekorURL),
+			verification.WithOffline(s.options.VerifyOffline),
+			verification.WithLogger(s.options.Logger),
+		)
+		if err != nil {
+			return &managerapi.Retrieve_Response{}, status.Error(codes.Internal, err.Error())
+		}
+		clientOpts = append(clientOpts, orasclient.WithPrePullFunc(verifier.Verify))
+	}
+
+	if len(attrSet) != 0 || message.Query != "" {
+		query := v1alpha1.AttributeQuery{
+			Attributes: attrSet,
+			Query:      message.Query,
+		}
+		matcher, err := config.ConvertToMatcher(query)
+		if err != nil {
+			return &managerapi.Retrieve_Response{}, status.Error(codes.InvalidArgument, err.Error())
+		}
 		clientOpts = append(clientOpts, orasclient.WithPullableAttributes(matcher))
 	}
 
@@ -58,6 +58,7 @@ func TestCollectionManagerServer_All(t *testing.T) {
 		workspace     string
 		collection    map[string]map[string]interface{}
 		filter        json.RawMessage
+		query         string
 		resAssertFunc func(*managerapi.Retrieve_Response, string) bool
 		sev           managerapi.Diagnostic_Severity
 		errMes        string
@@ -84,6 +85,21 @@ func TestCollectionManagerServer_All(t *testing.T) {
 				return err == nil
 			},
 		},
+		{
+			name:      "Success/WithQuery",
+			workspace: "testdata/workspace",
+			collection: map[string]map[string]interface{}{
+				"*.jpg": {
+					"animal": true,
+					"size":   5,
+				},
+			},
+			query: "animal && size in 1..10",
+			resAssertFunc: func(_ *managerapi.Retrieve_Response, root string) bool {
+				_, err := os.Stat(path.Join(root, "fish.jpg"))
+				return err == nil
+			},
+		},
 		{
 			name:      "Warning/FilteredCollection",
 			sev:       2,
@@ -148,6 +164,7 @@ func TestCollectionManagerServer_All(t *testing.T) {
 			rRequest := &managerapi.Retrieve_Request{
 				Source:      fmt.Sprintf("%s/test@%s", u.Host, pResp.Digest),
 				Destination: destination,
+				Query:       c.query,
 			}
 
 			if c.filter != nil {
@@ -0,0 +1,164 @@
+package testutils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// oidcIssuerOID is the certificate extension Fulcio
+// records the issuer of the identity token in.
+var oidcIssuerOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+
+// NewFulcio returns a handler which implements a mock Fulcio certificate
+// authority and the PEM encoded root certificate it issues certificates
+// from. Certificates are issued for the email address and issuer of the
+// identity token without verifying it and are returned without a signed
+// certificate timestamp.
+func NewFulcio(t *testing.T) (http.Handler, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fulcio.test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &key.PublicKey, key)
+	require.NoError(t, err)
+	root, err := x509.ParseCertificate(rootDER)
+	require.NoError(t, err)
+	rootPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootDER})
+
+	var (
+		mu     sync.Mutex
+		serial int64 = 1
+	)
+	fulcioFN := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/signingCert" {
+			t.Errorf("unexpected access: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var claims struct {
+			Email  string `json:"email"`
+			Issuer string `json:"iss"`
+		}
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if err := decodeTokenClaims(token, &claims); err != nil {
+			t.Errorf("failed to read identity token: %v", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			PublicKey struct {
+				Content []byte `json:"content"`
+			} `json:"publicKey"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to read certificate request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		pub, err := x509.ParsePKIXPublicKey(req.PublicKey.Content)
+		if err != nil {
+			t.Errorf("failed to parse public key: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		serial++
+		certSerial := serial
+		mu.Unlock()
+
+		template := &x509.Certificate{
+			SerialNumber:   big.NewInt(certSerial),
+			NotBefore:      time.Now().Add(-time.Minute),
+			NotAfter:       time.Now().Add(10 * time.Minute),
+			KeyUsage:       x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+			EmailAddresses: []string{claims.Email},
+			ExtraExtensions: []pkix.Extension{
+				{Id: oidcIssuerOID, Value: []byte(claims.Issuer)},
+			},
+		}
+		certDER, err := x509.CreateCertificate(rand.Reader, template, root, pub, key)
+		if err != nil {
+			t.Errorf("failed to issue certificate: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		w.WriteHeader(http.StatusCreated)
+		body := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), rootPEM...)
+		if _, err := w.Write(body); err != nil {
+			t.Errorf("failed to write certificate: %v", err)
+		}
+	})
+
+	return fulcioFN, rootPEM
+}
+
+// NewIDToken returns a signed OIDC identity token
+// with a verified email address from the issuer.
+func NewIDToken(t *testing.T, issuer, email string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	header, err := json.Marshal(map[string]interface{}{"alg": "ES256", "typ": "JWT"})
+	require.NoError(t, err)
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":            issuer,
+		"sub":            email,
+		"aud":            "sigstore",
+		"email":          email,
+		"email_verified": true,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	// ES256 signatures are the fixed size concatenation of r and s.
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// decodeTokenClaims decodes the claims of
+// the identity token without verifying it.
+func decodeTokenClaims(token string, claims interface{}) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("identity token is not a compact JWS")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(payload, claims)
+}
@@ -0,0 +1,91 @@
+package testutils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// NewRekor returns a handler which implements a mock Rekor transparency log
+// and the PEM encoded public key the signed entry timestamps of its entries
+// are verified with.
+func NewRekor(t *testing.T) (http.Handler, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pubBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	logIDHash := sha256.Sum256(pubBytes)
+	logID := hex.EncodeToString(logIDHash[:])
+
+	var (
+		mu       sync.Mutex
+		logIndex int64
+	)
+	rekorFN := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/log/entries" {
+			t.Errorf("unexpected access: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		entry, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read entry: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		index := logIndex
+		logIndex++
+		mu.Unlock()
+
+		// The keys of the payload are marshaled in sorted order,
+		// which matches its canonical form.
+		payload := map[string]interface{}{
+			"body":           base64.StdEncoding.EncodeToString(entry),
+			"integratedTime": time.Now().Unix(),
+			"logIndex":       index,
+			"logID":          logID,
+		}
+		payloadJSON, err := json.Marshal(payload)
+		if err != nil {
+			t.Errorf("failed to marshal payload: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		digest := sha256.Sum256(payloadJSON)
+		set, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Errorf("failed to sign entry timestamp: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		payload["verification"] = map[string]interface{}{
+			"signedEntryTimestamp": set,
+		}
+
+		uuid := hex.EncodeToString(digest[:])
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/api/v1/log/entries/"+uuid)
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{uuid: payload}); err != nil {
+			t.Errorf("failed to write entry: %v", err)
+		}
+	})
+
+	publicKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes})
+	return rekorFN, publicKey
+}
@@ -0,0 +1,318 @@
+package verification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/cmd/cosign/cli/fulcio"
+	cosignopts "github.com/sigstore/cosign/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/cmd/cosign/cli/rekor"
+	"github.com/sigstore/cosign/pkg/cosign"
+	ociremote "github.com/sigstore/cosign/pkg/oci/remote"
+	sigs "github.com/sigstore/cosign/pkg/signature"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/log"
+)
+
+// Verifier verifies the signatures of collections
+// per a verification policy.
+type Verifier struct {
+	policies  []clientapi.ReferencePolicy
+	keychain  authn.Keychain
+	transport http.RoundTripper
+	insecure  bool
+	rekorURL  string
+	offline   bool
+	logger    log.Logger
+}
+
+// Option configures a Verifier.
+type Option func(*Verifier) error
+
+// WithKeychain sets the keychain used to authenticate to registries
+// when fetching signatures. Defaults to authn.DefaultKeychain.
+func WithKeychain(keychain authn.Keychain) Option {
+	return func(v *Verifier) error {
+		v.keychain = keychain
+		return nil
+	}
+}
+
+// WithTransport sets the transport used to connect to registries
+// when fetching signatures. Defaults to the go-containerregistry transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(v *Verifier) error {
+		v.transport = transport
+		return nil
+	}
+}
+
+// WithInsecure allows fetching signatures from registries using plain
+// http or TLS certificates that are not verified.
+func WithInsecure(insecure bool) Option {
+	return func(v *Verifier) error {
+		v.insecure = insecure
+		return nil
+	}
+}
+
+// WithRekorURL sets the address of the transparency log signatures are
+// looked up in when they do not bundle a transparency log entry.
+func WithRekorURL(rekorURL string) Option {
+	return func(v *Verifier) error {
+		v.rekorURL = rekorURL
+		return nil
+	}
+}
+
+// WithOffline verifies signatures with the transparency log entries
+// bundled with them instead of contacting the transparency log.
+// Signatures without a bundled entry are rejected.
+func WithOffline(offline bool) Option {
+	return func(v *Verifier) error {
+		v.offline = offline
+		return nil
+	}
+}
+
+// WithLogger sets the logger for verification results.
+func WithLogger(logger log.Logger) Option {
+	return func(v *Verifier) error {
+		v.logger = logger
+		return nil
+	}
+}
+
+// NewVerifier returns a Verifier for the policy.
+func NewVerifier(policy clientapi.VerificationPolicy, options ...Option) (*Verifier, error) {
+	if err := Validate(policy); err != nil {
+		return nil, err
+	}
+	v := &Verifier{
+		policies: policy.Policies,
+		keychain: authn.DefaultKeychain,
+	}
+	for _, option := range options {
+		if err := option(v); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// Validate checks the reference policies of the verification policy.
+func Validate(policy clientapi.VerificationPolicy) error {
+	for _, p := range policy.Policies {
+		if p.Pattern == "" {
+			return errors.New("policy pattern must be set")
+		}
+		if _, err := path.Match(p.Pattern, ""); err != nil {
+			return fmt.Errorf("policy %s: invalid pattern: %w", p.Pattern, err)
+		}
+		switch p.Mode {
+		case "", clientapi.VerificationModeEnforce, clientapi.VerificationModeWarn:
+		case clientapi.VerificationModeSkip:
+			continue
+		default:
+			return fmt.Errorf("policy %s: invalid mode %q", p.Pattern, p.Mode)
+		}
+		if len(p.Authorities) == 0 {
+			return fmt.Errorf("policy %s: at least one authority must be set", p.Pattern)
+		}
+		if p.Threshold < 0 || p.Threshold > len(p.Authorities) {
+			return fmt.Errorf("policy %s: threshold must be between 1 and the number of authorities", p.Pattern)
+		}
+		for i, authority := range p.Authorities {
+			if (authority.PublicKey == "") == (authority.Keyless == nil) {
+				return fmt.Errorf("policy %s: %s: exactly one of publicKey or keyless must be set", p.Pattern, authorityName(i, authority))
+			}
+			if authority.Keyless == nil {
+				continue
+			}
+			if *authority.Keyless == (clientapi.KeylessAuthority{}) {
+				return fmt.Errorf("policy %s: %s: at least one of subject, subjectRegExp, issuer, or issuerRegExp must be set", p.Pattern, authorityName(i, authority))
+			}
+			for _, expr := range []string{authority.Keyless.SubjectRegExp, authority.Keyless.IssuerRegExp} {
+				if _, err := regexp.Compile(expr); err != nil {
+					return fmt.Errorf("policy %s: %s: %w", p.Pattern, authorityName(i, authority), err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Verify verifies the signatures of the collection per the first policy matching
+// the reference. Verification failures are returned in enforce mode and logged in
+// warn mode. Verify can be used with orasclient.WithPrePullFunc.
+func (v *Verifier) Verify(ctx context.Context, reference string) error {
+	var nameOpts []name.Option
+	if v.insecure {
+		nameOpts = append(nameOpts, name.Insecure)
+	}
+	ref, err := name.ParseReference(reference, nameOpts...)
+	if err != nil {
+		return fmt.Errorf("collection %q: %w", reference, err)
+	}
+
+	policy, ok := v.match(ref.Context().Name())
+	if !ok {
+		return fmt.Errorf("collection %q: no verification policy matches the reference", reference)
+	}
+	if policy.Mode == clientapi.VerificationModeSkip {
+		v.debugf("Skipping signature verification of %s", reference)
+		return nil
+	}
+
+	v.debugf("Checking signature of %s", reference)
+	if err := v.verify(ctx, ref, policy); err != nil {
+		if policy.Mode == clientapi.VerificationModeWarn {
+			if v.logger != nil {
+				v.logger.Warnf("collection %q: %v", reference, err)
+			}
+			return nil
+		}
+		return fmt.Errorf("collection %q: %w", reference, err)
+	}
+	return nil
+}
+
+// match returns the first policy with a
+// pattern matching the repository.
+func (v *Verifier) match(repository string) (clientapi.ReferencePolicy, bool) {
+	for _, p := range v.policies {
+		if matchPattern(p.Pattern, repository) {
+			return p, true
+		}
+	}
+	return clientapi.ReferencePolicy{}, false
+}
+
+// verify checks that the number of policy authorities
+// that signed the collection meets the threshold.
+func (v *Verifier) verify(ctx context.Context, ref name.Reference, policy clientapi.ReferencePolicy) error {
+	regopts := cosignopts.RegistryOptions{
+		Keychain:      v.keychain,
+		AllowInsecure: v.insecure,
+	}
+	ociremoteOpts, err := regopts.ClientOpts(ctx)
+	if err != nil {
+		return fmt.Errorf("constructing client options: %w", err)
+	}
+	if v.transport != nil {
+		remoteOpts := append(regopts.GetRegistryClientOpts(ctx), remote.WithTransport(v.transport))
+		ociremoteOpts = append(ociremoteOpts, ociremote.WithRemoteOptions(remoteOpts...))
+	}
+	co := cosign.CheckOpts{
+		RegistryClientOpts: ociremoteOpts,
+	}
+	if !v.offline && v.rekorURL != "" {
+		co.RekorClient, err = rekor.NewClient(v.rekorURL)
+		if err != nil {
+			return fmt.Errorf("creating Rekor client: %w", err)
+		}
+	}
+
+	threshold := policy.Threshold
+	if threshold == 0 {
+		threshold = 1
+	}
+	var verified int
+	var failures []string
+	for i, authority := range policy.Authorities {
+		if err := v.verifyAuthority(ctx, ref, co, authority); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", authorityName(i, authority), err))
+			continue
+		}
+		v.debugf("Verified signature of %s by %s", ref.String(), authorityName(i, authority))
+		verified++
+		if verified == threshold {
+			return nil
+		}
+	}
+	return fmt.Errorf("signed by %d of %d required authorities: %s", verified, threshold, strings.Join(failures, "; "))
+}
+
+// verifyAuthority checks that the collection
+// has a valid signature by the authority.
+func (v *Verifier) verifyAuthority(ctx context.Context, ref name.Reference, co cosign.CheckOpts, authority clientapi.Authority) error {
+	var err error
+	if authority.PublicKey != "" {
+		co.SigVerifier, err = sigs.PublicKeyFromKeyRef(ctx, authority.PublicKey)
+		if err != nil {
+			return fmt.Errorf("loading public key: %w", err)
+		}
+	} else {
+		co.RootCerts, err = fulcio.GetRoots()
+		if err != nil {
+			return fmt.Errorf("getting Fulcio roots: %w", err)
+		}
+		co.IntermediateCerts, err = fulcio.GetIntermediates()
+		if err != nil {
+			return fmt.Errorf("getting Fulcio intermediates: %w", err)
+		}
+		co.Identities = []cosign.Identity{
+			{
+				Subject:       authority.Keyless.Subject,
+				SubjectRegExp: authority.Keyless.SubjectRegExp,
+				Issuer:        authority.Keyless.Issuer,
+				IssuerRegExp:  authority.Keyless.IssuerRegExp,
+			},
+		}
+	}
+	_, bundleVerified, err := cosign.VerifyImageSignatures(ctx, ref, &co)
+	if err != nil {
+		return err
+	}
+	// Without a transparency log to look signatures up in, only
+	// signatures with a verified bundled entry are accepted.
+	if co.RekorClient == nil && !bundleVerified {
+		return errors.New("no signature bundles a verified transparency log entry")
+	}
+	return nil
+}
+
+// debugf logs verification progress if a logger is set.
+func (v *Verifier) debugf(format string, args ...interface{}) {
+	if v.logger != nil {
+		v.logger.Debugf(format, args...)
+	}
+}
+
+// authorityName returns the name of the authority
+// or its position in the policy.
+func authorityName(i int, authority clientapi.Authority) string {
+	if authority.Name != "" {
+		return authority.Name
+	}
+	return fmt.Sprintf("authority %d", i+1)
+}
+
+// matchPattern reports whether the repository matches the pattern. A "*"
+// matches any characters within a path segment and a final "**" segment
+// matches one or more path segments.
+func matchPattern(pattern, repository string) bool {
+	patternSegments := strings.Split(pattern, "/")
+	segments := strings.Split(repository, "/")
+	for i, patternSegment := range patternSegments {
+		if patternSegment == "**" && i == len(patternSegments)-1 {
+			return len(segments) > i
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if ok, _ := path.Match(patternSegment, segments[i]); !ok {
+			return false
+		}
+	}
+	return len(segments) == len(patternSegments)
+}
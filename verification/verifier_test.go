@@ -0,0 +1,339 @@
+package verification
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	cosignopts "github.com/sigstore/cosign/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/cmd/cosign/cli/sign"
+	"github.com/sigstore/cosign/pkg/cosign"
+	"github.com/stretchr/testify/require"
+
+	clientapi "github.com/emporous/emporous-go/api/client/v1alpha1"
+	"github.com/emporous/emporous-go/log"
+	"github.com/emporous/emporous-go/util/testutils"
+)
+
+func TestMatchPattern(t *testing.T) {
+	type spec struct {
+		name       string
+		pattern    string
+		repository string
+		exp        bool
+	}
+
+	cases := []spec{
+		{
+			name:       "Success/Exact",
+			pattern:    "registry.example.com/team/app",
+			repository: "registry.example.com/team/app",
+			exp:        true,
+		},
+		{
+			name:       "Success/SegmentWildcard",
+			pattern:    "registry.example.com/*/app",
+			repository: "registry.example.com/team/app",
+			exp:        true,
+		},
+		{
+			name:       "Success/Namespace",
+			pattern:    "registry.example.com/team/**",
+			repository: "registry.example.com/team/sub/app",
+			exp:        true,
+		},
+		{
+			name:       "Success/Any",
+			pattern:    "**",
+			repository: "registry.example.com/app",
+			exp:        true,
+		},
+		{
+			name:       "Failure/SegmentWildcardAcrossSegments",
+			pattern:    "registry.example.com/*",
+			repository: "registry.example.com/team/app",
+		},
+		{
+			name:       "Failure/NamespaceItself",
+			pattern:    "registry.example.com/team/**",
+			repository: "registry.example.com/team",
+		},
+		{
+			name:       "Failure/DifferentRegistry",
+			pattern:    "registry.example.com/**",
+			repository: "registry.example.com.evil/app",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.exp, matchPattern(c.pattern, c.repository))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	type spec struct {
+		name     string
+		policy   clientapi.ReferencePolicy
+		expError string
+	}
+
+	cases := []spec{
+		{
+			name: "Success/Valid",
+			policy: clientapi.ReferencePolicy{
+				Pattern:   "registry.example.com/**",
+				Threshold: 1,
+				Authorities: []clientapi.Authority{
+					{PublicKey: "cosign.pub"},
+					{Keyless: &clientapi.KeylessAuthority{SubjectRegExp: ".*@example.com"}},
+				},
+			},
+		},
+		{
+			name: "Success/SkipWithoutAuthorities",
+			policy: clientapi.ReferencePolicy{
+				Pattern: "registry.example.com/**",
+				Mode:    clientapi.VerificationModeSkip,
+			},
+		},
+		{
+			name: "Failure/InvalidMode",
+			policy: clientapi.ReferencePolicy{
+				Pattern:     "registry.example.com/**",
+				Mode:        "audit",
+				Authorities: []clientapi.Authority{{PublicKey: "cosign.pub"}},
+			},
+			expError: "policy registry.example.com/**: invalid mode \"audit\"",
+		},
+		{
+			name: "Failure/NoAuthorities",
+			policy: clientapi.ReferencePolicy{
+				Pattern: "registry.example.com/**",
+			},
+			expError: "policy registry.example.com/**: at least one authority must be set",
+		},
+		{
+			name: "Failure/ThresholdExceedsAuthorities",
+			policy: clientapi.ReferencePolicy{
+				Pattern:     "registry.example.com/**",
+				Threshold:   2,
+				Authorities: []clientapi.Authority{{PublicKey: "cosign.pub"}},
+			},
+			expError: "policy registry.example.com/**: threshold must be between 1 and the number of authorities",
+		},
+		{
+			name: "Failure/KeyAndKeyless",
+			policy: clientapi.ReferencePolicy{
+				Pattern: "registry.example.com/**",
+				Authorities: []clientapi.Authority{
+					{Name: "release", PublicKey: "cosign.pub", Keyless: &clientapi.KeylessAuthority{}},
+				},
+			},
+			expError: "policy registry.example.com/**: release: exactly one of publicKey or keyless must be set",
+		},
+		{
+			name: "Failure/KeylessWithoutIdentity",
+			policy: clientapi.ReferencePolicy{
+				Pattern: "registry.example.com/**",
+				Authorities: []clientapi.Authority{
+					{Name: "ci", Keyless: &clientapi.KeylessAuthority{}},
+				},
+			},
+			expError: "policy registry.example.com/**: ci: at least one of subject, subjectRegExp, issuer, or issuerRegExp must be set",
+		},
+		{
+			name: "Failure/InvalidRegExp",
+			policy: clientapi.ReferencePolicy{
+				Pattern: "registry.example.com/**",
+				Authorities: []clientapi.Authority{
+					{Keyless: &clientapi.KeylessAuthority{IssuerRegExp: "("}},
+				},
+			},
+			expError: "policy registry.example.com/**: authority 1: error parsing regexp: missing closing ): `(`",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := Validate(clientapi.VerificationPolicy{Policies: []clientapi.ReferencePolicy{c.policy}})
+			if c.expError != "" {
+				require.EqualError(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Setenv("COSIGN_EXPERIMENTAL", "true")
+	t.Setenv("COSIGN_PASSWORD", "test")
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	rekor, rekorPub := testutils.NewRekor(t)
+	rekorServer := httptest.NewServer(rekor)
+	t.Cleanup(rekorServer.Close)
+
+	keyDir := t.TempDir()
+	signingKey, signerPub := writeKeyPair(t, keyDir, "signer")
+	_, otherPub := writeKeyPair(t, keyDir, "other")
+	rekorKey := filepath.Join(keyDir, "rekor.pub")
+	require.NoError(t, os.WriteFile(rekorKey, rekorPub, 0600))
+	t.Setenv("SIGSTORE_REKOR_PUBLIC_KEY", rekorKey)
+
+	// Publish a collection signed by the signer key, one signed
+	// without a transparency log entry, and one without signatures.
+	signed := fmt.Sprintf("%s/signed/app:latest", u.Host)
+	unbundled := fmt.Sprintf("%s/signed/unbundled:latest", u.Host)
+	unsigned := fmt.Sprintf("%s/unsigned/app:latest", u.Host)
+	for _, reference := range []string{signed, unbundled, unsigned} {
+		ref, err := name.ParseReference(reference)
+		require.NoError(t, err)
+		img, err := random.Image(64, 1)
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(ref, img))
+	}
+	ko := cosignopts.KeyOpts{
+		KeyRef:   signingKey,
+		PassFunc: func(bool) ([]byte, error) { return []byte("test"), nil },
+		RekorURL: rekorServer.URL,
+	}
+	ro := &cosignopts.RootOptions{Timeout: time.Minute}
+	require.NoError(t, sign.SignCmd(ro, ko, cosignopts.RegistryOptions{}, nil, []string{signed},
+		"", "", true, "", "", "", true, false, "", false))
+	require.NoError(t, sign.SignCmd(ro, ko, cosignopts.RegistryOptions{}, nil, []string{unbundled},
+		"", "", true, "", "", "", false, false, "", true))
+
+	policy := func(mode string, threshold int, keys ...string) clientapi.VerificationPolicy {
+		var authorities []clientapi.Authority
+		for _, key := range keys {
+			authorities = append(authorities, clientapi.Authority{Name: filepath.Base(key), PublicKey: key})
+		}
+		return clientapi.VerificationPolicy{
+			Policies: []clientapi.ReferencePolicy{
+				{
+					Pattern:     fmt.Sprintf("%s/signed/**", u.Host),
+					Mode:        mode,
+					Threshold:   threshold,
+					Authorities: authorities,
+				},
+				{
+					Pattern: fmt.Sprintf("%s/unsigned/*", u.Host),
+					Mode:    clientapi.VerificationModeSkip,
+				},
+			},
+		}
+	}
+
+	type spec struct {
+		name      string
+		reference string
+		policy    clientapi.VerificationPolicy
+		expError  string
+		expLog    string
+	}
+
+	cases := []spec{
+		{
+			name:      "Success/SignedByAuthority",
+			reference: signed,
+			policy:    policy("", 0, otherPub, signerPub),
+			expLog:    "Verified signature of " + signed + " by signer.pub",
+		},
+		{
+			name:      "Success/SkipMode",
+			reference: unsigned,
+			policy:    policy("", 0, signerPub),
+			expLog:    "Skipping signature verification of " + unsigned,
+		},
+		{
+			name:      "Success/WarnMode",
+			reference: signed,
+			policy:    policy(clientapi.VerificationModeWarn, 0, otherPub),
+			expLog:    "signed by 0 of 1 required authorities",
+		},
+		{
+			name:      "Failure/ThresholdNotMet",
+			reference: signed,
+			policy:    policy(clientapi.VerificationModeEnforce, 2, signerPub, otherPub),
+			expError:  fmt.Sprintf("collection %q: signed by 1 of 2 required authorities: other.pub:", signed),
+		},
+		{
+			name:      "Failure/NoBundle",
+			reference: unbundled,
+			policy:    policy("", 0, signerPub),
+			expError:  fmt.Sprintf("collection %q: signed by 0 of 1 required authorities: signer.pub: no signature bundles a verified transparency log entry", unbundled),
+		},
+		{
+			name:      "Failure/NoMatchingPolicy",
+			reference: fmt.Sprintf("%s/other/app:latest", u.Host),
+			policy:    policy("", 0, signerPub),
+			expError:  fmt.Sprintf("collection \"%s/other/app:latest\": no verification policy matches the reference", u.Host),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			logger, err := log.NewLogrusLogger(out, "debug")
+			require.NoError(t, err)
+			verifier, err := NewVerifier(c.policy, WithOffline(true), WithInsecure(true), WithLogger(logger))
+			require.NoError(t, err)
+			err = verifier.Verify(context.TODO(), c.reference)
+			if c.expError != "" {
+				require.ErrorContains(t, err, c.expError)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Contains(t, out.String(), c.expLog)
+		})
+	}
+
+	t.Run("Success/Transport", func(t *testing.T) {
+		var requests int32
+		transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&requests, 1)
+			return http.DefaultTransport.RoundTrip(req)
+		})
+		verifier, err := NewVerifier(policy("", 0, signerPub), WithOffline(true), WithInsecure(true), WithTransport(transport))
+		require.NoError(t, err)
+		require.NoError(t, verifier.Verify(context.TODO(), signed))
+		require.NotZero(t, atomic.LoadInt32(&requests))
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+// writeKeyPair writes a cosign key pair encrypted
+// with the password "test" to the directory.
+func writeKeyPair(t *testing.T, dir, name string) (string, string) {
+	keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return []byte("test"), nil })
+	require.NoError(t, err)
+	privateKey := filepath.Join(dir, name+".key")
+	publicKey := filepath.Join(dir, name+".pub")
+	require.NoError(t, os.WriteFile(privateKey, keys.PrivateBytes, 0600))
+	require.NoError(t, os.WriteFile(publicKey, keys.PublicBytes, 0600))
+	return privateKey, publicKey
+}
@@ -28,27 +28,33 @@ var versionTemplate = `Emporous Client:
  Platform:	{{ .Platform }}
 `
 
-type clientVersion struct {
-	Platform  string
-	Version   string
-	GitCommit string
-	GoVersion string
-	BuildDate string
+// ClientVersion describes the version and build of the client.
+type ClientVersion struct {
+	Platform  string `json:"platform"`
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit"`
+	GoVersion string `json:"goVersion"`
+	BuildDate string `json:"buildDate"`
 }
 
 func GetVersion() string {
 	return versionWithBuild()
 }
 
-// WriteVersion will output the templated version message.
-func WriteVersion(writer io.Writer) error {
-	versionInfo := clientVersion{
+// GetVersionInfo returns the version and build information of the client.
+func GetVersionInfo() ClientVersion {
+	return ClientVersion{
 		Version:   versionWithBuild(),
 		GitCommit: commit,
 		BuildDate: buildDate,
 		GoVersion: runtime.Version(),
 		Platform:  fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
 	}
+}
+
+// WriteVersion will output the templated version message.
+func WriteVersion(writer io.Writer) error {
+	versionInfo := GetVersionInfo()
 
 	tmp, err := template.New("version").Parse(versionTemplate)
 	if err != nil {